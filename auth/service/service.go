@@ -99,7 +99,7 @@ func (s *Service) GenerateJWTCookie(userID uuid.UUID, host string) (*fiber.Cooki
 	}, nil
 }
 
-func (s *Service) Auth(ctx context.Context, cookie string, method string, url string) (users.User, error) {
+func (s *Service) Auth(ctx context.Context, cookie string, method string, path string) (users.User, error) {
 	user, err := s.getUserFromToken(ctx, cookie)
 	if err != nil {
 		return users.User{}, ErrNotAuthorized
@@ -110,7 +110,7 @@ func (s *Service) Auth(ctx context.Context, cookie string, method string, url st
 		if err != nil {
 			return users.User{}, err
 		}
-		if r.MatchString(url) {
+		if r.MatchString(path) {
 			for _, ruleMethod := range rule.Method {
 				if ruleMethod == "*" || ruleMethod == method {
 					for _, role := range rule.Allow {
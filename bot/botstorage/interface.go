@@ -16,4 +16,6 @@ type BotStorage interface {
 	UpdateUserRole(user model.User) error
 	GetMyPlayer(user model.User) (uuid.UUID, error)
 	LinkPlayer(user model.User, player domain.Player) error
+	// RelinkPlayer moves the links of the users from one player to another.
+	RelinkPlayer(from, to uuid.UUID) error
 }
@@ -249,3 +249,12 @@ func (s *Storage) LinkPlayer(user model.User, player domain.Player) error {
 	}
 	return nil
 }
+
+func (s *Storage) RelinkPlayer(from, to uuid.UUID) error {
+	_, err := table.UserPlayers.
+		UPDATE(table.UserPlayers.PlayerID).
+		SET(sqlite.String(to.String())).
+		WHERE(table.UserPlayers.PlayerID.EQ(sqlite.String(from.String()))).
+		Exec(s.db)
+	return err
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/goserg/ratingserver/bot/botstorage"
 	botmodel "github.com/goserg/ratingserver/bot/model"
 	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
 	"github.com/sirupsen/logrus"
 
@@ -32,6 +34,9 @@ type Bot struct {
 
 const draw = "ничья"
 
+// pairs asks the event for the proposed games between its players.
+const pairs = "пары"
+
 var ErrBadRequest = errors.New("неизвестная команда")
 
 func New(ps *service.PlayerService, bs botstorage.BotStorage, cfg config.Config, log *logrus.Logger) (Bot, error) {
@@ -76,11 +81,36 @@ func New(ps *service.PlayerService, bs botstorage.BotStorage, cfg config.Config,
 		func(msg string) {
 			b.sendMatchNotification(botmodel.NewMatch, msg)
 		},
+		func(c tgbotapi.Chattable) {
+			if _, err := b.bot.Send(c); err != nil {
+				b.log.WithError(err).Error("send error")
+			}
+		},
 	)
 
+	ps.OnAchievementsUnlocked(func(achievements []domain.Achievement) {
+		b.sendMatchNotification(botmodel.NewMatch, formatAchievements(achievements))
+	})
+
 	return b, nil
 }
 
+func formatAchievements(achievements []domain.Achievement) string {
+	var buf strings.Builder
+	for i, achievement := range achievements {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("🏅 ")
+		buf.WriteString(achievement.Player.Name)
+		buf.WriteString(" получает достижение \"")
+		buf.WriteString(achievement.Title())
+		buf.WriteString("\": ")
+		buf.WriteString(achievement.Description())
+	}
+	return buf.String()
+}
+
 func (b *Bot) Run() {
 	ctx, cancel := context.WithCancel(context.Background())
 	b.cancel = cancel
@@ -0,0 +1,51 @@
+package tgbot
+
+import (
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+// DeactivatePlayerCommand deactivates the player, or activates them if deactivated is false.
+type DeactivatePlayerCommand struct {
+	playerService *service.PlayerService
+	deactivated   bool
+}
+
+func (c *DeactivatePlayerCommand) Reset() {}
+
+func (c *DeactivatePlayerCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	player, err := playerByName(c.playerService, strings.TrimSpace(args))
+	if err != nil {
+		return false, err
+	}
+	err = c.playerService.DeactivatePlayer(player.ID, c.deactivated)
+	if err != nil {
+		return false, err
+	}
+	if c.deactivated {
+		resp.Text = "Игрок " + player.Name + " скрыт из рейтинга"
+	} else {
+		resp.Text = "Игрок " + player.Name + " снова в рейтинге"
+	}
+	return false, nil
+}
+
+func (c *DeactivatePlayerCommand) Help() string {
+	if c.deactivated {
+		return `Скрыть игрока из рейтинга, его игры сохранятся. Использование: /deactivate <имя>`
+	}
+	return `Вернуть скрытого игрока в рейтинг. Использование: /activate <имя>`
+}
+
+func (c *DeactivatePlayerCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *DeactivatePlayerCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
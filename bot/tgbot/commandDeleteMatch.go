@@ -0,0 +1,40 @@
+package tgbot
+
+import (
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type DeleteMatchCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *DeleteMatchCommand) Reset() {}
+
+func (c *DeleteMatchCommand) Run(user model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	match, err := matchByID(c.playerService, args)
+	if err != nil {
+		return false, err
+	}
+	err = c.playerService.DeleteMatch(author(user), match.ID)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = "матч удалён: " + match.Summary()
+	return false, nil
+}
+
+func (c *DeleteMatchCommand) Help() string {
+	return `Удалить матч. Использование: /delete <номер матча>`
+}
+
+func (c *DeleteMatchCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *DeleteMatchCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
@@ -0,0 +1,64 @@
+package tgbot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type EditMatchCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *EditMatchCommand) Reset() {}
+
+func (c *EditMatchCommand) Run(user model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return false, errors.New("укажите номер матча")
+	}
+	old, err := matchByID(c.playerService, fields[0])
+	if err != nil {
+		return false, err
+	}
+	match, err := parseMatch(c.playerService, old.DisciplineID, fields[1:])
+	if err != nil {
+		return false, err
+	}
+	match.ID = old.ID
+	match, err = c.playerService.EditMatch(author(user), match)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = "матч изменён: " + match.Summary()
+	return false, nil
+}
+
+func (c *EditMatchCommand) Help() string {
+	return `Изменить матч. Использование: /edit <номер матча> <игрок1> <игрок2> <победитель / "ничья"> [счёт игрок1:игрок2] [дата ДД.ММ.ГГГГ [время ЧЧ:ММ]]
+Без даты дата матча не меняется`
+}
+
+func (c *EditMatchCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *EditMatchCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+// matchByID finds the match by the number given in the command.
+func matchByID(ps *service.PlayerService, field string) (domain.Match, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(field, "#"))
+	if err != nil {
+		return domain.Match{}, errors.New("неверный номер матча " + field)
+	}
+	return ps.GetMatch(id)
+}
@@ -1,12 +1,13 @@
 package tgbot
 
 import (
-	"log"
+	"sort"
 	"strings"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
 	"github.com/goserg/ratingserver/bot/model"
 	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
@@ -25,23 +26,27 @@ const (
 type EventCommand struct {
 	playerService *service.PlayerService
 	state         EventState
-	players       mapset.Set[domain.Player]
-	winner        string
-	notify        func(msg string)
+	// discipline is given before the names of the players.
+	discipline int
+	players    map[uuid.UUID]domain.Player
+	winner     string
+	notify     func(msg string)
 }
 
 func NewEventCommand(ps *service.PlayerService, notify func(msg string)) *EventCommand {
 	return &EventCommand{
 		playerService: ps,
 		state:         EventStateStart,
-		players:       mapset.NewSet[domain.Player](),
+		discipline:    domain.DefaultDisciplineID,
+		players:       make(map[uuid.UUID]domain.Player),
 		notify:        notify,
 	}
 }
 
 func (c *EventCommand) Reset() {
 	c.state = EventStateStart
-	c.players = mapset.NewSet[domain.Player]()
+	c.discipline = domain.DefaultDisciplineID
+	c.players = make(map[uuid.UUID]domain.Player)
 	c.winner = ""
 }
 
@@ -61,6 +66,10 @@ func (c *EventCommand) Run(
 	case EventStateWaitForPlayers:
 		return c.handleStateWaitForPlayers(text, resp)
 	case EventStateWinner:
+		if text == pairs {
+			c.handleMatchmaking(resp)
+			return true, nil
+		}
 		c.handleStateWinner(text, resp)
 		return true, nil
 	case EventStateLooser:
@@ -78,24 +87,25 @@ func (c *EventCommand) handleStateDraw(text string, resp *tgbotapi.MessageConfig
 		resp.Text = "second:"
 		return true, nil
 	}
-	winner, err := c.playerService.GetByName(c.winner)
+	winner, err := c.playerService.GetByName(c.discipline, c.winner)
 	if err != nil {
 		return true, err
 	}
-	loser, err := c.playerService.GetByName(text)
+	loser, err := c.playerService.GetByName(c.discipline, text)
 	if err != nil {
 		return false, err
 	}
 	match, err := c.playerService.CreateMatch(domain.Match{
-		PlayerA: winner,
-		PlayerB: loser,
-		Winner:  domain.Player{},
-		Date:    time.Now(),
+		DisciplineID: c.discipline,
+		PlayerA:      winner,
+		PlayerB:      loser,
+		Winner:       domain.Player{},
+		Date:         time.Now(),
 	})
 	if err != nil {
 		return true, err
 	}
-	c.sendMatchNotification(match)
+	c.notify(formatMatchResult(match))
 	c.state = EventStateWinner
 	c.winner = ""
 	resp.Text = "draw registered\nwinner:"
@@ -112,30 +122,79 @@ func (c *EventCommand) handleStateLoser(text string, resp *tgbotapi.MessageConfi
 		resp.Text = "second:"
 		return true, nil
 	}
-	winner, err := c.playerService.GetByName(c.winner)
+	winner, err := c.playerService.GetByName(c.discipline, c.winner)
 	if err != nil {
 		return true, err
 	}
-	loser, err := c.playerService.GetByName(text)
+	loser, err := c.playerService.GetByName(c.discipline, text)
 	if err != nil {
 		return false, err
 	}
 	match, err := c.playerService.CreateMatch(domain.Match{
-		PlayerA: winner,
-		PlayerB: loser,
-		Winner:  winner,
-		Date:    time.Now(),
+		DisciplineID: c.discipline,
+		PlayerA:      winner,
+		PlayerB:      loser,
+		Winner:       winner,
+		Date:         time.Now(),
 	})
 	if err != nil {
 		return true, err
 	}
-	c.sendMatchNotification(match)
+	c.notify(formatMatchResult(match))
 	c.state = EventStateWinner
 	c.winner = ""
 	resp.Text = "match registered\nwinner:"
 	return true, nil
 }
 
+// handleMatchmaking proposes the games between the players of the event, the event goes on anyway.
+func (c *EventCommand) handleMatchmaking(resp *tgbotapi.MessageConfig) {
+	players := make([]domain.Player, 0, len(c.players))
+	for _, player := range c.players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+	ids := make([]uuid.UUID, 0, len(players))
+	for _, player := range players {
+		ids = append(ids, player.ID)
+	}
+	matchmaking, err := c.playerService.Matchmaking(c.discipline, ids)
+	if err != nil {
+		resp.Text = err.Error() + "\nwinner:"
+		return
+	}
+	resp.Text = formatMatchmaking(matchmaking) + "\nwinner:"
+}
+
+// formatMatchmaking lists the proposed games with the chances to win, draw and lose in the primary rating system.
+func formatMatchmaking(m domain.Matchmaking) string {
+	var buf strings.Builder
+	for _, pairing := range m.Pairings {
+		outcomes := pairing.Outcomes()
+		buf.WriteString(pairing.Prediction.PlayerA.Name)
+		buf.WriteString(" - ")
+		buf.WriteString(pairing.Prediction.PlayerB.Name)
+		buf.WriteString(": ")
+		buf.WriteString(outcomes.WinA.PercentString())
+		buf.WriteString(" / ")
+		buf.WriteString(outcomes.Draw.PercentString())
+		buf.WriteString(" / ")
+		buf.WriteString(outcomes.WinB.PercentString())
+		if pairing.Rematch() {
+			buf.WriteString(", недавно играли")
+		}
+		buf.WriteString("\n")
+	}
+	if m.Resting != nil {
+		buf.WriteString("Отдыхает: ")
+		buf.WriteString(m.Resting.Name)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
 func (c *EventCommand) handleStateWinner(text string, resp *tgbotapi.MessageConfig) {
 	if text == "" {
 		resp.Text = "winner:"
@@ -156,21 +215,34 @@ func (c *EventCommand) handleStateWaitForPlayers(text string, resp *tgbotapi.Mes
 		resp.Text = "waiting for players names"
 		return true, nil
 	}
-	names := strings.Fields(text)
+	discipline, names := splitDiscipline(c.playerService, strings.Fields(text))
 	if len(names) <= 1 {
 		resp.Text = "need more then 1 player"
 		return true, nil
 	}
+	var deactivated []string
 	for _, name := range names {
-		player, err := c.playerService.GetByName(name)
+		player, err := c.playerService.GetByName(discipline.ID, name)
 		if err != nil {
 			return false, err
 		}
-		c.players.Add(player)
+		if player.Deactivated {
+			deactivated = append(deactivated, player.Name)
+			continue
+		}
+		c.players[player.ID] = player
+	}
+	if len(c.players) <= 1 {
+		resp.Text = "need more then 1 active player"
+		return true, nil
 	}
+	c.discipline = discipline.ID
 	resp.ReplyMarkup = generateKeyboard(c.players)
 	c.state = EventStateWinner
 	resp.Text = "event registered\nwinner:"
+	if len(deactivated) > 0 {
+		resp.Text = "deactivated players skipped: " + strings.Join(deactivated, ", ") + "\n" + resp.Text
+	}
 	return true, nil
 }
 
@@ -183,10 +255,15 @@ func (c *EventCommand) handleStateStart(resp *tgbotapi.MessageConfig) bool {
 
 const rowWidth = 4
 
-func generateKeyboard(players mapset.Set[domain.Player]) tgbotapi.ReplyKeyboardMarkup {
+func generateKeyboard(players map[uuid.UUID]domain.Player) tgbotapi.ReplyKeyboardMarkup {
 	keyboard := tgbotapi.NewReplyKeyboard()
-	addPlayersToKeyboard(players.ToSlice(), &keyboard)
-	addDrawToKeyboard(players.Cardinality(), &keyboard)
+	list := make([]domain.Player, 0, len(players))
+	for _, player := range players {
+		list = append(list, player)
+	}
+	addPlayersToKeyboard(list, &keyboard)
+	addDrawToKeyboard(len(players), &keyboard)
+	keyboard.Keyboard = append(keyboard.Keyboard, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(pairs)))
 	return keyboard
 }
 
@@ -222,7 +299,7 @@ func addDrawToKeyboard(playersLen int, keyboard *tgbotapi.ReplyKeyboardMarkup) {
 }
 
 func (c *EventCommand) Help() string {
-	return `Управление событием`
+	return `Управление событием. Дисциплину можно указать перед именами игроков, кнопка "пары" предлагает равные игры без недавних повторов.`
 }
 
 func (c *EventCommand) Permission() mapset.Set[model.UserRole] {
@@ -232,18 +309,3 @@ func (c *EventCommand) Permission() mapset.Set[model.UserRole] {
 func (c *EventCommand) Visibility() mapset.Set[model.UserRole] {
 	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator)
 }
-
-func (c *EventCommand) sendMatchNotification(match domain.Match) {
-	matches, err := c.playerService.GetMatches()
-	if err != nil {
-		log.Println("ERRRRRR", err.Error())
-		return
-	}
-	for i := range matches {
-		if matches[i].ID == match.ID {
-			match := matches[i]
-			c.notify(formatMatchResult(match))
-			return
-		}
-	}
-}
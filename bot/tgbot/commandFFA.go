@@ -0,0 +1,74 @@
+package tgbot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type FFACommand struct {
+	playerService *service.PlayerService
+	notify        func(msg string)
+}
+
+func (c *FFACommand) Reset() {}
+
+func (c *FFACommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	if len(fields) < domain.MinFFAPlayers {
+		return false, errors.New(`неверный запрос. Пример: "/ffa вася петя=коля миша" - первый вася, петя и коля поделили второе место`)
+	}
+	placements, err := c.playerService.PlacementsByNames(discipline.ID, fields)
+	if err != nil {
+		return false, err
+	}
+	match, err := c.playerService.CreateFFAMatch(domain.FFAMatch{
+		DisciplineID: discipline.ID,
+		Date:         time.Now(),
+		Placements:   placements,
+	})
+	if err != nil {
+		return false, err
+	}
+	c.notify(formatFFAResult(match))
+	resp.Text = "матч создан"
+	return false, nil
+}
+
+func (c *FFACommand) Help() string {
+	return `Добавить игру на несколько игроков. Использование: /ffa [дисциплина] <первое место> <второе место> ...
+Игроки, поделившие место, пишутся через "=": /ffa вася петя=коля миша`
+}
+
+func (c *FFACommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator)
+}
+
+func (c *FFACommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator)
+}
+
+func formatFFAResult(match domain.FFAMatch) string {
+	var buf strings.Builder
+	buf.WriteString("Игра на несколько игроков\n")
+	for _, placement := range match.Placements {
+		r := placement.Player.PrimaryRating()
+		buf.WriteString(strconv.Itoa(placement.Place))
+		buf.WriteString(". ")
+		buf.WriteString(placement.Player.Name)
+		buf.WriteString(": ")
+		buf.WriteString(r.String())
+		buf.WriteString("(")
+		buf.WriteString(r.ChangeString())
+		buf.WriteString(")\n")
+	}
+	return buf.String()
+}
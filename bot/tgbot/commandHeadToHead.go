@@ -0,0 +1,149 @@
+package tgbot
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+// headToHeadMatches is the number of the last matches listed by /h2h.
+const headToHeadMatches = 5
+
+type HeadToHeadCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *HeadToHeadCommand) Reset() {}
+
+func (c *HeadToHeadCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	if len(fields) != 2 {
+		return false, errors.New(`после /h2h нужно указать имена двух игроков. Например "/h2h джон боб"`)
+	}
+	playerA, err := c.playerService.GetByName(discipline.ID, fields[0])
+	if err != nil {
+		return false, errors.New("игрок " + fields[0] + " не найден")
+	}
+	playerB, err := c.playerService.GetByName(discipline.ID, fields[1])
+	if err != nil {
+		return false, errors.New("игрок " + fields[1] + " не найден")
+	}
+	h2h, err := c.playerService.HeadToHead(discipline.ID, playerA.ID, playerB.ID)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = formatHeadToHead(h2h)
+	return false, nil
+}
+
+func formatHeadToHead(h domain.HeadToHead) string {
+	var buf strings.Builder
+	buf.WriteString(h.PlayerA.Name)
+	buf.WriteString(" ")
+	buf.WriteString(strconv.Itoa(h.WinsA))
+	buf.WriteString(" : ")
+	buf.WriteString(strconv.Itoa(h.WinsB))
+	buf.WriteString(" ")
+	buf.WriteString(h.PlayerB.Name)
+	buf.WriteString("\n")
+	buf.WriteString("Встреч: ")
+	buf.WriteString(strconv.Itoa(h.Total()))
+	buf.WriteString(", ничьих: ")
+	buf.WriteString(strconv.Itoa(h.Draws))
+	buf.WriteString("\n")
+
+	if len(h.Prediction.Systems) > 0 {
+		system := h.Prediction.Systems[0]
+		buf.WriteString("\nПрогноз (")
+		buf.WriteString(system.System.Title)
+		buf.WriteString("):\n")
+		writeOutcome(&buf, "Победа "+h.PlayerA.Name, system.WinA)
+		writeOutcome(&buf, "Ничья", system.Draw)
+		writeOutcome(&buf, "Победа "+h.PlayerB.Name, system.WinB)
+	}
+
+	buf.WriteString("\nРейтинг:\n")
+	writeTrend(&buf, h.PlayerA, h.TrendA)
+	writeTrend(&buf, h.PlayerB, h.TrendB)
+
+	if len(h.Matches) > 0 {
+		buf.WriteString("\nПоследние матчи:\n")
+		for i, match := range h.Matches {
+			if i == headToHeadMatches {
+				break
+			}
+			buf.WriteString(match.Summary())
+			if match.Voided {
+				buf.WriteString(" (аннулирован)")
+			}
+			buf.WriteString("\n")
+		}
+	}
+	if len(h.FFAMatches) > 0 {
+		buf.WriteString("\nИгры на несколько игроков:\n")
+		for i, match := range h.FFAMatches {
+			if i == headToHeadMatches {
+				break
+			}
+			buf.WriteString(match.Date.Format(dateLayout))
+			buf.WriteString(" ")
+			buf.WriteString(h.PlayerA.Name)
+			buf.WriteString(" ")
+			buf.WriteString(strconv.Itoa(match.Place(h.PlayerA.ID)))
+			buf.WriteString(" место, ")
+			buf.WriteString(h.PlayerB.Name)
+			buf.WriteString(" ")
+			buf.WriteString(strconv.Itoa(match.Place(h.PlayerB.ID)))
+			buf.WriteString(" место\n")
+		}
+	}
+	return buf.String()
+}
+
+// writeTrend writes the first and the current ratings of the player with the lowest and the highest ones between them.
+func writeTrend(buf *strings.Builder, player domain.Player, trend []domain.RatingPoint) {
+	buf.WriteString(player.Name)
+	buf.WriteString(": ")
+	if len(trend) == 0 {
+		buf.WriteString(player.PrimaryRating().String())
+		buf.WriteString("\n")
+		return
+	}
+	low, high := trend[0].Value, trend[0].Value
+	for _, point := range trend {
+		low = math.Min(low, point.Value)
+		high = math.Max(high, point.Value)
+	}
+	buf.WriteString(formatRating(trend[0].Value))
+	buf.WriteString(" → ")
+	buf.WriteString(formatRating(trend[len(trend)-1].Value))
+	buf.WriteString(" (мин. ")
+	buf.WriteString(formatRating(low))
+	buf.WriteString(", макс. ")
+	buf.WriteString(formatRating(high))
+	buf.WriteString(")\n")
+}
+
+func formatRating(value float64) string {
+	return strconv.FormatFloat(value, 'f', 0, 64)
+}
+
+func (c *HeadToHeadCommand) Help() string {
+	return `Личные встречи двух игроков. Использование: /h2h <игрок 1> <игрок 2>`
+}
+
+func (c *HeadToHeadCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
+
+func (c *HeadToHeadCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
@@ -2,6 +2,7 @@ package tgbot
 
 import (
 	"errors"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -9,23 +10,26 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/chart"
 	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
 )
 
 type InfoCommand struct {
 	playerService *service.PlayerService
+	send          func(c tgbotapi.Chattable)
 }
 
 func (c *InfoCommand) Reset() {}
 
 func (c *InfoCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
 	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
-	text, err := c.processInfo(args)
+	discipline, player, err := c.processInfo(args)
 	if err != nil {
 		return false, err
 	}
-	resp.Text = text
+	sendRatingChart(c.playerService, c.send, resp.ChatID, discipline, player)
+	resp.Text = printPlayer(player, c.playerService.RatingSystems())
 	return false, nil
 }
 
@@ -33,19 +37,36 @@ func (c *InfoCommand) Help() string {
 	return `Информация об игроке. Использование - /info и имя игрока.`
 }
 
-func (c *InfoCommand) processInfo(command string) (string, error) {
-	fields := strings.Fields(command)
+func (c *InfoCommand) processInfo(command string) (domain.Discipline, domain.Player, error) {
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(command))
 	if len(fields) < 1 {
-		return "", errors.New(`после /info имя игрока необходимо указывать в этом же соощении. Например "/info джон"`)
+		return domain.Discipline{}, domain.Player{}, errors.New(`после /info имя игрока необходимо указывать в этом же соощении. Например "/info джон"`)
 	}
-	player, err := c.playerService.GetByName(fields[0])
+	player, err := c.playerService.GetByName(discipline.ID, fields[0])
 	if err != nil {
-		return "", err
+		return domain.Discipline{}, domain.Player{}, err
 	}
-	return printPlayer(player), nil
+	return discipline, player, nil
 }
 
-func printPlayer(player domain.Player) string {
+// sendRatingChart sends the rating chart of the player as a photo with the caption describing it
+// before the reply, nothing is sent if the player has not played yet.
+func sendRatingChart(ps *service.PlayerService, send func(c tgbotapi.Chattable), chatID int64, discipline domain.Discipline, player domain.Player) {
+	series, err := ps.RatingChart(discipline.ID, player.ID)
+	if err != nil {
+		log.Println("BOT ERROR", err.Error())
+		return
+	}
+	image := chart.RatingPNG(series)
+	if image == nil {
+		return
+	}
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "rating.png", Bytes: image})
+	photo.Caption = chart.Caption("График рейтинга "+player.Name+", "+discipline.Name, series)
+	send(photo)
+}
+
+func printPlayer(player domain.Player, systems []domain.RatingSystem) string {
 	var buf strings.Builder
 	buf.WriteString("ID: ")
 	buf.WriteString(player.ID.String())
@@ -56,18 +77,42 @@ func printPlayer(player domain.Player) string {
 	buf.WriteString("Место в рейтинге: ")
 	buf.WriteString(prettifyRank(player))
 	buf.WriteString("\n")
-	buf.WriteString("Рейтинг: ")
-	buf.WriteString(strconv.Itoa(player.EloRating))
-	buf.WriteString("\n")
+	for i := range systems {
+		r, ok := player.Rating(systems[i].Name)
+		if !ok {
+			continue
+		}
+		buf.WriteString("Рейтинг ")
+		buf.WriteString(systems[i].Title)
+		buf.WriteString(": ")
+		buf.WriteString(r.String())
+		buf.WriteString("\n")
+	}
 	buf.WriteString("Сыграно игр: ")
 	buf.WriteString(strconv.Itoa(player.GamesPlayed))
 	buf.WriteString("\n")
+	if !player.LastPlayedAt.IsZero() {
+		buf.WriteString("Последняя игра: ")
+		buf.WriteString(player.LastPlayedAt.Format(time.RFC1123))
+		buf.WriteString("\n")
+	}
 	buf.WriteString("Зарегистрирован: ")
 	buf.WriteString(player.RegisteredAt.Format(time.RFC1123))
+	if player.Deactivated {
+		buf.WriteString("\nСкрыт из рейтинга")
+	} else if player.Inactive {
+		buf.WriteString("\nНеактивен: не показывается в рейтинге до следующей игры")
+	}
 	return buf.String()
 }
 
 func prettifyRank(player domain.Player) string {
+	if player.Inactive {
+		return "—"
+	}
+	if player.Provisional {
+		return "— (рейтинг предварительный)"
+	}
 	if player.RatingRank == 1 {
 		return "🥇"
 	}
@@ -0,0 +1,251 @@
+package tgbot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"github.com/goserg/ratingserver/bot/botstorage"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/normalize"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+const (
+	ladderJoin      = "встать"
+	ladderChallenge = "вызов"
+	ladderAccept    = "принять"
+	ladderCancel    = "отменить"
+	ladderResult    = "результат"
+)
+
+type LadderCommand struct {
+	playerService *service.PlayerService
+	botStorage    botstorage.BotStorage
+	notify        func(msg string)
+}
+
+func (c *LadderCommand) Reset() {}
+
+func (c *LadderCommand) Run(user model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	if len(fields) == 0 {
+		l, err := c.playerService.Ladder(discipline.ID)
+		if err != nil {
+			return false, err
+		}
+		resp.Text = formatLadder(l)
+		return false, nil
+	}
+	var err error
+	switch fields[0] {
+	case ladderJoin:
+		resp.Text, err = c.join(user, discipline.ID)
+	case ladderChallenge:
+		resp.Text, err = c.challenge(user, discipline.ID, strings.Join(fields[1:], " "))
+	case ladderAccept:
+		resp.Text, err = c.accept(user, discipline.ID)
+	case ladderCancel:
+		resp.Text, err = c.cancel(user, discipline.ID)
+	case ladderResult:
+		resp.Text, err = c.result(user, fields[1:])
+	default:
+		err = errors.New("неизвестная команда " + fields[0] + ", см. /help")
+	}
+	return false, err
+}
+
+func (c *LadderCommand) Help() string {
+	return `Лестница вызовов. Использование: /ladder [дисциплина] - позиции и вызовы,
+/ladder [дисциплина] встать - поставить своего игрока внизу лестницы,
+/ladder [дисциплина] вызов <имя игрока> - вызвать игрока выше,
+/ladder [дисциплина] принять - принять вызов до срока, иначе засчитывается техническое поражение; принятый вызов, не сыгранный до срока, снимается,
+/ladder [дисциплина] отменить - отменить свой вызов,
+/ladder результат <номер вызова> <победитель / "ничья"> - записать игру по вызову (для модераторов)
+Свой игрок выбирается командой /me <имя игрока>`
+}
+
+func (c *LadderCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
+
+func (c *LadderCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
+
+// myPlayer returns the player the user has chosen with /me.
+func (c *LadderCommand) myPlayer(user model.User) (uuid.UUID, error) {
+	id, err := c.botStorage.GetMyPlayer(user)
+	if err != nil {
+		return uuid.Nil, errors.New("сначала выберите своего игрока: /me <имя игрока>")
+	}
+	return id, nil
+}
+
+func (c *LadderCommand) join(user model.User, discipline int) (string, error) {
+	me, err := c.myPlayer(user)
+	if err != nil {
+		return "", err
+	}
+	err = c.playerService.JoinLadder(discipline, me)
+	if err != nil {
+		return "", err
+	}
+	l, err := c.playerService.Ladder(discipline)
+	if err != nil {
+		return "", err
+	}
+	return "вы на лестнице, позиция " + strconv.Itoa(l.Position(me)), nil
+}
+
+func (c *LadderCommand) challenge(user model.User, discipline int, name string) (string, error) {
+	if name == "" {
+		return "", errors.New(`после "вызов" нужно указать имя игрока`)
+	}
+	me, err := c.myPlayer(user)
+	if err != nil {
+		return "", err
+	}
+	challenged, err := c.playerService.GetByName(discipline, normalize.Name(name))
+	if err != nil {
+		return "", errors.New("игрок " + name + " не найден")
+	}
+	challenge, err := c.playerService.Challenge(discipline, me, challenged.ID)
+	if err != nil {
+		return "", err
+	}
+	c.notify(formatChallenge(challenge))
+	return "вызов отправлен", nil
+}
+
+func (c *LadderCommand) accept(user model.User, discipline int) (string, error) {
+	me, err := c.myPlayer(user)
+	if err != nil {
+		return "", err
+	}
+	open, err := c.openChallenge(discipline, me)
+	if err != nil {
+		return "", err
+	}
+	if open.Challenged.ID != me {
+		return "", errors.New("принять вызов может только вызванный игрок")
+	}
+	challenge, err := c.playerService.AcceptChallenge(open.ID)
+	if err != nil {
+		return "", err
+	}
+	c.notify(formatChallenge(challenge))
+	return "вызов принят", nil
+}
+
+func (c *LadderCommand) cancel(user model.User, discipline int) (string, error) {
+	me, err := c.myPlayer(user)
+	if err != nil {
+		return "", err
+	}
+	open, err := c.openChallenge(discipline, me)
+	if err != nil {
+		return "", err
+	}
+	challenge, err := c.playerService.CancelChallenge(open.ID)
+	if err != nil {
+		return "", err
+	}
+	c.notify(formatChallenge(challenge))
+	return "вызов отменён", nil
+}
+
+// openChallenge returns the unresolved challenge of the player, there is at most one.
+func (c *LadderCommand) openChallenge(discipline int, player uuid.UUID) (domain.LadderChallenge, error) {
+	l, err := c.playerService.Ladder(discipline)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	for _, challenge := range l.Open() {
+		if challenge.Has(player) {
+			return challenge, nil
+		}
+	}
+	return domain.LadderChallenge{}, errors.New("у вас нет незавершённых вызовов")
+}
+
+func (c *LadderCommand) result(user model.User, fields []string) (string, error) {
+	if user.Role != model.RoleAdmin && user.Role != model.RoleModerator {
+		return "", errors.New("записать игру по вызову может только модератор")
+	}
+	if len(fields) != 2 {
+		return "", errors.New(`после "результат" нужно указать номер вызова и победителя или "ничья"`)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(fields[0], "#"))
+	if err != nil {
+		return "", errors.New("неверный номер вызова " + fields[0])
+	}
+	challenge, err := c.playerService.LadderChallenge(id)
+	if err != nil {
+		return "", err
+	}
+	match := domain.Match{PlayerA: challenge.Challenger, PlayerB: challenge.Challenged}
+	switch winner := normalize.Name(fields[1]); winner {
+	case draw:
+	case normalize.Name(challenge.Challenger.Name):
+		match.Winner = challenge.Challenger
+	case normalize.Name(challenge.Challenged.Name):
+		match.Winner = challenge.Challenged
+	default:
+		return "", errors.New("по вызову играют " + challenge.Challenger.Name + " и " + challenge.Challenged.Name)
+	}
+	created, err := c.playerService.RecordLadderMatch(id, match)
+	if err != nil {
+		return "", err
+	}
+	c.notify(formatMatchResult(created))
+	return "матч создан", nil
+}
+
+// formatLadder lists the positions and the unresolved challenges with their numbers.
+func formatLadder(l domain.Ladder) string {
+	if len(l.Rungs) == 0 {
+		return "На лестнице пока никого нет: /ladder встать"
+	}
+	var buf strings.Builder
+	buf.WriteString("Лестница:\n")
+	for _, rung := range l.Rungs {
+		buf.WriteString(strconv.Itoa(rung.Position))
+		buf.WriteString(". ")
+		buf.WriteString(rung.Player.Name)
+		buf.WriteString(" - ")
+		buf.WriteString(rung.Player.PrimaryRating().String())
+		buf.WriteString("\n")
+	}
+	open := l.Open()
+	if len(open) > 0 {
+		buf.WriteString("\nВызовы:\n")
+	}
+	for _, challenge := range open {
+		buf.WriteString(formatChallenge(challenge))
+		buf.WriteString("\n")
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func formatChallenge(c domain.LadderChallenge) string {
+	var buf strings.Builder
+	buf.WriteString("#")
+	buf.WriteString(strconv.Itoa(c.ID))
+	buf.WriteString(" ")
+	buf.WriteString(c.Challenger.Name)
+	buf.WriteString(" вызывает ")
+	buf.WriteString(c.Challenged.Name)
+	buf.WriteString(": ")
+	buf.WriteString(c.Status.Title())
+	if c.Open() {
+		buf.WriteString(", срок до ")
+		buf.WriteString(c.Deadline.Format(dateLayout))
+	}
+	return buf.String()
+}
@@ -1,31 +1,38 @@
 package tgbot
 
 import (
+	"strconv"
+	"strings"
+
 	mapset "github.com/deckarep/golang-set/v2"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/goserg/ratingserver/bot/botstorage"
 	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
 )
 
 type MeCommand struct {
 	playerService *service.PlayerService
 	botStorage    botstorage.BotStorage
+	send          func(c tgbotapi.Chattable)
 }
 
 func (c *MeCommand) Reset() {}
 
 func (c *MeCommand) Run(user model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
 	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
-	if args == "" {
-		text, err := c.processMe(user)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	if len(fields) == 0 {
+		player, text, err := c.processMe(user, discipline.ID)
 		if err != nil {
 			return false, err
 		}
+		sendRatingChart(c.playerService, c.send, resp.ChatID, discipline, player)
 		resp.Text = text
 		return false, nil
 	}
-	text, err := c.connectMe(user, args)
+	text, err := c.connectMe(user, discipline.ID, strings.Join(fields, " "))
 	if err != nil {
 		return false, err
 	}
@@ -34,19 +41,61 @@ func (c *MeCommand) Run(user model.User, args string, resp *tgbotapi.MessageConf
 }
 
 func (c *MeCommand) Help() string {
-	return `Информация об избранном игроке.`
+	return `Информация об избранном игроке. Использование: /me [дисциплина], выбрать игрока - /me <имя игрока>`
 }
 
-func (c *MeCommand) processMe(user model.User) (string, error) {
+func (c *MeCommand) processMe(user model.User, discipline int) (domain.Player, string, error) {
 	playerID, err := c.botStorage.GetMyPlayer(user)
 	if err != nil {
-		return "", err
+		return domain.Player{}, "", err
 	}
-	player, err := c.playerService.Get(playerID)
+	player, err := c.playerService.Get(discipline, playerID)
 	if err != nil {
-		return "", err
+		return domain.Player{}, "", err
+	}
+	records, err := c.playerService.GetRecords(discipline, playerID)
+	if err != nil {
+		return domain.Player{}, "", err
+	}
+	return player, printPlayer(player, c.playerService.RatingSystems()) + formatRecords(records), nil
+}
+
+func formatRecords(r domain.PlayerRecords) string {
+	if r.PeakRatingDate.IsZero() {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString("\n\nЛучшая серия побед: ")
+	buf.WriteString(strconv.Itoa(r.LongestWinStreak))
+	buf.WriteString("\nТекущая серия: ")
+	buf.WriteString(r.StreakString())
+	buf.WriteString("\nПиковый рейтинг: ")
+	buf.WriteString(r.PeakRating.String())
+	buf.WriteString(" (")
+	buf.WriteString(r.PeakRatingDate.Format(dateLayout))
+	buf.WriteString(")")
+	if r.BiggestUpset.MatchID != 0 {
+		buf.WriteString("\nСамая неожиданная победа: над ")
+		buf.WriteString(r.BiggestUpset.Opponent.Name)
+		buf.WriteString(" при шансах ")
+		buf.WriteString(r.BiggestUpset.PercentString())
+		buf.WriteString(" (")
+		buf.WriteString(r.BiggestUpset.Date.Format(dateLayout))
+		buf.WriteString(")")
+	}
+	if len(r.Achievements) > 0 {
+		buf.WriteString("\n\nДостижения:")
+		for _, achievement := range r.Achievements {
+			buf.WriteString("\n🏅 ")
+			buf.WriteString(achievement.Title())
+			buf.WriteString(" - ")
+			buf.WriteString(achievement.Description())
+			buf.WriteString(" (")
+			buf.WriteString(achievement.Date.Format(dateLayout))
+			buf.WriteString(")")
+		}
 	}
-	return printPlayer(player), nil
+	return buf.String()
 }
 
 func (c *MeCommand) Permission() mapset.Set[model.UserRole] {
@@ -57,8 +106,8 @@ func (c *MeCommand) Visibility() mapset.Set[model.UserRole] {
 	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
 }
 
-func (c *MeCommand) connectMe(user model.User, playerName string) (string, error) {
-	player, err := c.playerService.GetByName(playerName)
+func (c *MeCommand) connectMe(user model.User, discipline int, playerName string) (string, error) {
+	player, err := c.playerService.GetByName(discipline, playerName)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,51 @@
+package tgbot
+
+import (
+	"errors"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type MergePlayersCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *MergePlayersCommand) Reset() {}
+
+func (c *MergePlayersCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return false, errors.New(`неверный запрос. Пример: "васька вася" - игры васьки перейдут к васе`)
+	}
+	from, err := playerByName(c.playerService, fields[0])
+	if err != nil {
+		return false, err
+	}
+	to, err := playerByName(c.playerService, fields[1])
+	if err != nil {
+		return false, err
+	}
+	err = c.playerService.MergePlayers(from.ID, to.ID)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = "Игры " + from.Name + " перенесены к " + to.Name + ", игрок " + from.Name + " удалён"
+	return false, nil
+}
+
+func (c *MergePlayersCommand) Help() string {
+	return `Объединить игрока, созданного по ошибке, с основным. Использование: /merge <дубликат> <игрок>`
+}
+
+func (c *MergePlayersCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *MergePlayersCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
@@ -2,7 +2,6 @@ package tgbot
 
 import (
 	"errors"
-	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -28,13 +27,15 @@ func (c *NewGameCommand) Run(_ model.User, args string, resp *tgbotapi.MessageCo
 	if err != nil {
 		return false, err
 	}
-	c.sendMatchNotification(match)
+	c.notify(formatMatchResult(match))
 	resp.Text = "матч создан"
 	return false, nil
 }
 
 func (c *NewGameCommand) Help() string {
-	return `Добавить игру. Использование: /game <игрок1> <игрок1> <победитель / "ничья">`
+	return `Добавить игру. Использование: /game [дисциплина] <игрок1> <игрок2> <победитель / "ничья"> [счёт игрок1:игрок2] [дата ДД.ММ.ГГГГ [время ЧЧ:ММ]]
+Для командной игры игроки одной стороны пишутся через "+": /game вася+петя коля+миша вася
+Для игры, сыгранной раньше, указывается дата: /game вася петя вася 11:7 01.02.2023 18:30`
 }
 
 func (c *NewGameCommand) Permission() mapset.Set[model.UserRole] {
@@ -49,67 +50,122 @@ const (
 	playerAIndex int = iota
 	playerBIndex
 	winnerIndex
+	scoreIndex
 )
 
 func (c *NewGameCommand) processAddMatch(arguments string) (domain.Match, error) {
-	fields := strings.Fields(arguments)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(arguments))
+	newMatch, err := parseMatch(c.playerService, discipline.ID, fields)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	return c.playerService.CreateMatch(newMatch)
+}
+
+// parseMatch reads the sides, the result, the score and the date of a match of the discipline.
+// The date is zero if it is not given.
+func parseMatch(ps *service.PlayerService, discipline int, fields []string) (domain.Match, error) {
 	if len(fields) < 3 {
 		return domain.Match{}, errors.New(`неверный запрос. Пример: "Вася петя вася" - играли вася и петя, победил вася`)
 	}
-	playerAName := fields[playerAIndex]
-	playerA, err := c.playerService.GetByName(playerAName)
+	sideA, err := team(ps, discipline, fields[playerAIndex])
 	if err != nil {
-		return domain.Match{}, errors.New(playerAName + " не найден")
+		return domain.Match{}, err
 	}
-	playerBName := fields[playerBIndex]
-	playerB, err := c.playerService.GetByName(playerBName)
+	sideB, err := team(ps, discipline, fields[playerBIndex])
 	if err != nil {
-		return domain.Match{}, errors.New(playerBName + " не найден")
+		return domain.Match{}, err
 	}
 
 	newMatch := domain.Match{
-		PlayerA: playerA,
-		PlayerB: playerB,
-		Date:    time.Now(),
-	}
-	switch normalize.Name(fields[winnerIndex]) {
-	case normalize.Name(playerAName):
-		newMatch.Winner = playerA
-	case normalize.Name(playerBName):
-		newMatch.Winner = playerB
-	case draw:
+		DisciplineID: discipline,
+		PlayerA:      sideA[0],
+		PlayerB:      sideB[0],
+		TeammatesA:   sideA[1:],
+		TeammatesB:   sideB[1:],
+	}
+	winner := normalize.Name(fields[winnerIndex])
+	switch {
+	case inTeam(winner, fields[playerAIndex]):
+		newMatch.Winner = newMatch.PlayerA
+	case inTeam(winner, fields[playerBIndex]):
+		newMatch.Winner = newMatch.PlayerB
+	case winner == draw:
 		newMatch.Winner = domain.Player{}
 	default:
 		return domain.Match{}, errors.New("winner unknown")
 	}
-	return c.playerService.CreateMatch(newMatch)
+	for i := scoreIndex; i < len(fields); i++ {
+		if date, err := time.ParseInLocation(dateLayout, fields[i], time.Local); err == nil {
+			if i+1 < len(fields) {
+				if clock, err := time.Parse(timeLayout, fields[i+1]); err == nil {
+					date = time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+					i++
+				}
+			}
+			newMatch.Date = date
+			continue
+		}
+		if newMatch.Score != nil {
+			return domain.Match{}, errors.New("неверный запрос: лишнее " + fields[i])
+		}
+		score, err := domain.ParseScore(fields[i])
+		if err != nil {
+			return domain.Match{}, err
+		}
+		newMatch.Score = &score
+	}
+	return newMatch, nil
 }
 
-func (c *NewGameCommand) sendMatchNotification(match domain.Match) {
-	matches, err := c.playerService.GetMatches()
-	if err != nil {
-		log.Println("ERRRRRR", err.Error())
-		return
-	}
-	for i := range matches {
-		if matches[i].ID == match.ID {
-			match := matches[i]
-			c.notify(formatMatchResult(match))
-			return
+// The formats of the date and the time a match was played at.
+const (
+	dateLayout = "02.01.2006"
+	timeLayout = "15:04"
+)
+
+// teamSeparator joins the names of the players of one side, e.g. "вася+петя".
+const teamSeparator = "+"
+
+func team(ps *service.PlayerService, discipline int, field string) ([]domain.Player, error) {
+	var players []domain.Player
+	for _, name := range strings.Split(field, teamSeparator) {
+		if name == "" {
+			return nil, errors.New(`неверный состав команды "` + field + `"`)
+		}
+		player, err := ps.GetByName(discipline, name)
+		if err != nil {
+			return nil, errors.New(name + " не найден")
 		}
+		players = append(players, player)
 	}
+	return players, nil
+}
+
+// inTeam reports whether the normalized name is one of the names of the side.
+func inTeam(name string, field string) bool {
+	for _, member := range strings.Split(field, teamSeparator) {
+		if normalize.Name(member) == name {
+			return true
+		}
+	}
+	return false
 }
 
 func formatMatchResult(match domain.Match) string {
 	var buf strings.Builder
+	// the number is needed to /edit or /void the match
+	buf.WriteString("#")
+	buf.WriteString(strconv.Itoa(match.ID))
+	buf.WriteString(" ")
 	if match.Winner.ID == match.PlayerA.ID {
 		buf.WriteString("🏆")
 	} else if match.Winner.ID == match.PlayerB.ID {
 		buf.WriteString("😖")
 	}
-	buf.WriteString(match.PlayerA.Name)
+	buf.WriteString(teamNames(match.SideA()))
 	buf.WriteString(" vs ")
-	buf.WriteString(match.PlayerB.Name)
+	buf.WriteString(teamNames(match.SideB()))
 	if match.Winner.ID == match.PlayerB.ID {
 		buf.WriteString("🏆")
 	} else if match.Winner.ID == match.PlayerA.ID {
@@ -119,20 +175,30 @@ func formatMatchResult(match domain.Match) string {
 	if match.Winner.ID != match.PlayerA.ID && match.Winner.ID != match.PlayerB.ID {
 		buf.WriteString("Ничья\n")
 	}
+	if match.Score != nil {
+		buf.WriteString("Счёт: ")
+		buf.WriteString(match.Score.String())
+		buf.WriteString("\n")
+	}
 	buf.WriteString("Рейтинг:\n")
 
-	buf.WriteString(match.PlayerA.Name)
-	buf.WriteString(": ")
-	buf.WriteString(strconv.Itoa(match.PlayerA.EloRating))
-	buf.WriteString("(")
-	buf.WriteString(strconv.Itoa(match.PlayerA.RatingChange))
-	buf.WriteString(")\n")
-	buf.WriteString(match.PlayerB.Name)
-	buf.WriteString(": ")
-	buf.WriteString(strconv.Itoa(match.PlayerB.EloRating))
-	buf.WriteString("(")
-	buf.WriteString(strconv.Itoa(match.PlayerB.RatingChange))
-	buf.WriteString(")\n")
+	for _, player := range append(match.SideA(), match.SideB()...) {
+		r := player.PrimaryRating()
+		buf.WriteString(player.Name)
+		buf.WriteString(": ")
+		buf.WriteString(r.String())
+		buf.WriteString("(")
+		buf.WriteString(r.ChangeString())
+		buf.WriteString(")\n")
+	}
 
 	return buf.String()
 }
+
+func teamNames(players []domain.Player) string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return strings.Join(names, " + ")
+}
@@ -19,22 +19,8 @@ func (c *NewPlayerCommand) Reset() {}
 
 func (c *NewPlayerCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
 	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
-	if args == "" {
-		return false, errors.New("имя должно быть не пустое")
-	}
-	if strings.EqualFold(args, draw) {
-		return false, errors.New("имя " + draw + " запрещено")
-	}
-	for i, r := range args {
-		if i == 0 {
-			if !unicode.IsLetter(r) {
-				return false, errors.New("имя должно начинать с буквы")
-			}
-			continue
-		}
-		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
-			return false, errors.New("имя должно содержать только печатные символы")
-		}
+	if err := validatePlayerName(args); err != nil {
+		return false, err
 	}
 	p, err := c.playerService.CreatePlayer(args)
 	if err != nil {
@@ -55,3 +41,25 @@ func (c *NewPlayerCommand) Permission() mapset.Set[model.UserRole] {
 func (c *NewPlayerCommand) Visibility() mapset.Set[model.UserRole] {
 	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator)
 }
+
+// validatePlayerName checks that the name can be told apart from the other arguments of the commands.
+func validatePlayerName(name string) error {
+	if name == "" {
+		return errors.New("имя должно быть не пустое")
+	}
+	if strings.EqualFold(name, draw) {
+		return errors.New("имя " + draw + " запрещено")
+	}
+	for i, r := range name {
+		if i == 0 {
+			if !unicode.IsLetter(r) {
+				return errors.New("имя должно начинать с буквы")
+			}
+			continue
+		}
+		if !unicode.IsPrint(r) || unicode.IsSpace(r) {
+			return errors.New("имя должно содержать только печатные символы")
+		}
+	}
+	return nil
+}
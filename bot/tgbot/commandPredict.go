@@ -0,0 +1,87 @@
+package tgbot
+
+import (
+	"errors"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type PredictCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *PredictCommand) Reset() {}
+
+func (c *PredictCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	if len(fields) != 2 {
+		return false, errors.New(`после /predict нужно указать имена двух игроков. Например "/predict джон боб"`)
+	}
+	playerA, err := c.playerService.GetByName(discipline.ID, fields[0])
+	if err != nil {
+		return false, errors.New("игрок " + fields[0] + " не найден")
+	}
+	playerB, err := c.playerService.GetByName(discipline.ID, fields[1])
+	if err != nil {
+		return false, errors.New("игрок " + fields[1] + " не найден")
+	}
+	prediction, err := c.playerService.Predict(discipline.ID, playerA.ID, playerB.ID)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = formatPrediction(prediction)
+	return false, nil
+}
+
+func formatPrediction(p domain.Prediction) string {
+	var buf strings.Builder
+	buf.WriteString(p.PlayerA.Name)
+	buf.WriteString(" - ")
+	buf.WriteString(p.PlayerB.Name)
+	buf.WriteString("\n")
+	for _, system := range p.Systems {
+		buf.WriteString("\n")
+		buf.WriteString(system.System.Title)
+		buf.WriteString(":\n")
+		writeOutcome(&buf, "Победа "+p.PlayerA.Name, system.WinA)
+		writeOutcome(&buf, "Ничья", system.Draw)
+		writeOutcome(&buf, "Победа "+p.PlayerB.Name, system.WinB)
+	}
+	return buf.String()
+}
+
+func writeOutcome(buf *strings.Builder, title string, o domain.Outcome) {
+	buf.WriteString(title)
+	buf.WriteString(": ")
+	buf.WriteString(o.PercentString())
+	buf.WriteString(" (")
+	buf.WriteString(signed(o.A.ChangeString()))
+	buf.WriteString(" / ")
+	buf.WriteString(signed(o.B.ChangeString()))
+	buf.WriteString(")\n")
+}
+
+func signed(change string) string {
+	if strings.HasPrefix(change, "-") {
+		return change
+	}
+	return "+" + change
+}
+
+func (c *PredictCommand) Help() string {
+	return `Прогноз игры. Использование: /predict <игрок 1> <игрок 2>`
+}
+
+func (c *PredictCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
+
+func (c *PredictCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
@@ -0,0 +1,60 @@
+package tgbot
+
+import (
+	"errors"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type RenamePlayerCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *RenamePlayerCommand) Reset() {}
+
+func (c *RenamePlayerCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return false, errors.New(`неверный запрос. Пример: "вася василий"`)
+	}
+	player, err := playerByName(c.playerService, fields[0])
+	if err != nil {
+		return false, err
+	}
+	if err = validatePlayerName(fields[1]); err != nil {
+		return false, err
+	}
+	renamed, err := c.playerService.RenamePlayer(player.ID, fields[1])
+	if err != nil {
+		return false, err
+	}
+	resp.Text = "Игрок " + player.Name + " переименован в " + renamed.Name
+	return false, nil
+}
+
+func (c *RenamePlayerCommand) Help() string {
+	return `Переименовать игрока. Использование: /rename <имя> <новое имя>`
+}
+
+func (c *RenamePlayerCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *RenamePlayerCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+// playerByName finds the player in any discipline, all of them have the same players.
+func playerByName(ps *service.PlayerService, name string) (domain.Player, error) {
+	player, err := ps.GetByName(domain.DefaultDisciplineID, name)
+	if err != nil {
+		return domain.Player{}, errors.New(name + " не найден")
+	}
+	return player, nil
+}
@@ -0,0 +1,30 @@
+package tgbot
+
+import (
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/rating"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+// TrueSkillTopCommand is a shortcut for /top trueskill.
+type TrueSkillTopCommand struct {
+	TopCommand
+}
+
+func NewTrueSkillTopCommand(ps *service.PlayerService) *TrueSkillTopCommand {
+	return &TrueSkillTopCommand{
+		TopCommand: TopCommand{
+			playerService: ps,
+			system:        rating.TrueSkill,
+		},
+	}
+}
+
+func (c *TrueSkillTopCommand) Help() string {
+	return `Список лучших в рейтинге TrueSkill. Показывается консервативная оценка (μ - 3σ) и диапазон (μ ± 3σ)`
+}
+
+func (c *TrueSkillTopCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
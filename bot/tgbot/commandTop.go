@@ -7,36 +7,66 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
 )
 
 type TopCommand struct {
 	playerService *service.PlayerService
+	// system is used when no rating system is given in arguments,
+	// empty means the primary one.
+	system string
 }
 
 func (c *TopCommand) Reset() {}
 
-func (c *TopCommand) Run(_ model.User, _ string, resp *tgbotapi.MessageConfig) (bool, error) {
+func (c *TopCommand) Run(_ model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
 	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
-	ratings := c.playerService.GetRatings()
+	discipline, fields := splitDiscipline(c.playerService, strings.Fields(args))
+	system := c.system
+	if len(fields) > 0 {
+		system = fields[0]
+	}
+	if system == "" {
+		system = c.playerService.RatingSystems()[0].Name
+	}
+	entries, err := c.playerService.Leaderboard(discipline.ID, system)
+	if err != nil {
+		return false, err
+	}
+	resp.Text = formatTop(entries)
+	return false, nil
+}
+
+// formatTop lists the ten best established players, the number of the provisional ones is added after them.
+func formatTop(entries []domain.LeaderboardEntry) string {
 	var buffer strings.Builder
-	for i := range ratings {
-		if i > 9 {
-			break
+	var provisional int
+	for _, entry := range entries {
+		if entry.Rank == 0 {
+			provisional++
+			continue
+		}
+		if entry.Rank > 10 {
+			continue
 		}
-		buffer.WriteString(strconv.Itoa(ratings[i].RatingRank))
+		buffer.WriteString(strconv.Itoa(entry.Rank))
 		buffer.WriteString(". ")
-		buffer.WriteString(ratings[i].Name)
-		buffer.WriteString("(")
-		buffer.WriteString(strconv.Itoa(ratings[i].EloRating))
-		buffer.WriteString(")\n")
+		buffer.WriteString(entry.Player.Name)
+		buffer.WriteString(" - ")
+		buffer.WriteString(entry.Score.String())
+		buffer.WriteString("\n")
 	}
-	resp.Text = buffer.String()
-	return false, nil
+	if provisional > 0 {
+		buffer.WriteString("\nС предварительным рейтингом: ")
+		buffer.WriteString(strconv.Itoa(provisional))
+		buffer.WriteString("\n")
+	}
+	return buffer.String()
 }
 
 func (c *TopCommand) Help() string {
-	return `Список лучших в рейтинге`
+	return `Список лучших в рейтинге. Использование: /top [дисциплина] [рейтинг], например "/top glicko2", "/top conservative" или "/top шахматы glicko2"`
 }
 
 func (c *TopCommand) Permission() mapset.Set[model.UserRole] {
@@ -0,0 +1,84 @@
+package tgbot
+
+import (
+	"strconv"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+type TournamentCommand struct {
+	playerService *service.PlayerService
+}
+
+func (c *TournamentCommand) Reset() {}
+
+func (c *TournamentCommand) Run(_ model.User, _ string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	tournaments, err := c.playerService.Tournaments()
+	if err != nil {
+		return false, err
+	}
+	var texts []string
+	for _, t := range tournaments {
+		if !t.Finished() {
+			texts = append(texts, formatTournament(t))
+		}
+	}
+	if len(texts) == 0 {
+		resp.Text = "Сейчас турниров нет"
+		return false, nil
+	}
+	resp.Text = strings.Join(texts, "\n\n")
+	return false, nil
+}
+
+// formatTournament lists the standings and the games waiting for the results.
+func formatTournament(t domain.Tournament) string {
+	var buffer strings.Builder
+	buffer.WriteString(t.Name)
+	buffer.WriteString(" (")
+	buffer.WriteString(t.Format.Title())
+	buffer.WriteString(")\n")
+	for _, standing := range t.Standings {
+		buffer.WriteString(strconv.Itoa(standing.Place))
+		buffer.WriteString(". ")
+		buffer.WriteString(standing.Player.Name)
+		if t.Format.Elimination() {
+			if standing.Eliminated {
+				buffer.WriteString(" - выбыл")
+			}
+		} else {
+			buffer.WriteString(" - ")
+			buffer.WriteString(standing.PointsString())
+		}
+		buffer.WriteString("\n")
+	}
+	pending := t.Pending()
+	if len(pending) > 0 {
+		buffer.WriteString("\nОжидаются игры:\n")
+	}
+	for _, game := range pending {
+		buffer.WriteString(game.PlayerA.Name)
+		buffer.WriteString(" - ")
+		buffer.WriteString(game.PlayerB.Name)
+		buffer.WriteString("\n")
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func (c *TournamentCommand) Help() string {
+	return "Положение в идущих турнирах и их несыгранные игры"
+}
+
+func (c *TournamentCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
+
+func (c *TournamentCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin, model.RoleModerator, model.RoleUser)
+}
@@ -0,0 +1,49 @@
+package tgbot
+
+import (
+	mapset "github.com/deckarep/golang-set/v2"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/service"
+)
+
+// VoidMatchCommand voids the match, or restores it if voided is false.
+type VoidMatchCommand struct {
+	playerService *service.PlayerService
+	voided        bool
+}
+
+func (c *VoidMatchCommand) Reset() {}
+
+func (c *VoidMatchCommand) Run(user model.User, args string, resp *tgbotapi.MessageConfig) (bool, error) {
+	resp.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	match, err := matchByID(c.playerService, args)
+	if err != nil {
+		return false, err
+	}
+	err = c.playerService.VoidMatch(author(user), match.ID, c.voided)
+	if err != nil {
+		return false, err
+	}
+	if c.voided {
+		resp.Text = "матч аннулирован: " + match.Summary()
+	} else {
+		resp.Text = "матч восстановлен: " + match.Summary()
+	}
+	return false, nil
+}
+
+func (c *VoidMatchCommand) Help() string {
+	if c.voided {
+		return `Аннулировать матч, он перестанет влиять на рейтинг. Использование: /void <номер матча>`
+	}
+	return `Восстановить аннулированный матч. Использование: /restore <номер матча>`
+}
+
+func (c *VoidMatchCommand) Permission() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
+
+func (c *VoidMatchCommand) Visibility() mapset.Set[model.UserRole] {
+	return mapset.NewSet[model.UserRole](model.RoleAdmin)
+}
@@ -5,6 +5,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/goserg/ratingserver/bot/botstorage"
 	"github.com/goserg/ratingserver/bot/model"
+	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/service"
 )
 
@@ -29,6 +30,7 @@ func NewCommands(
 	subFn func(id int),
 	unsubFn func(id int),
 	sendNotifFn func(msg string),
+	sendFn func(c tgbotapi.Chattable),
 ) *Commands {
 	hc := &HelpCommand{}
 	uc := Commands{
@@ -38,15 +40,30 @@ func NewCommands(
 			"top": &TopCommand{
 				playerService: ps,
 			},
-			"gtop": &Glicko2TopCommand{
-				playerService: ps,
-			},
+			"gtop": NewGlicko2TopCommand(ps),
+			"ttop": NewTrueSkillTopCommand(ps),
 			"me": &MeCommand{
 				playerService: ps,
 				botStorage:    bs,
+				send:          sendFn,
 			},
 			"info": &InfoCommand{
 				playerService: ps,
+				send:          sendFn,
+			},
+			"predict": &PredictCommand{
+				playerService: ps,
+			},
+			"h2h": &HeadToHeadCommand{
+				playerService: ps,
+			},
+			"tournament": &TournamentCommand{
+				playerService: ps,
+			},
+			"ladder": &LadderCommand{
+				playerService: ps,
+				botStorage:    bs,
+				notify:        sendNotifFn,
 			},
 			"role": &RoleCommand{
 				adminPassword: adminPass,
@@ -56,9 +73,39 @@ func NewCommands(
 				playerService: ps,
 				notify:        sendNotifFn,
 			},
+			"ffa": &FFACommand{
+				playerService: ps,
+				notify:        sendNotifFn,
+			},
+			"edit": &EditMatchCommand{
+				playerService: ps,
+			},
+			"void": &VoidMatchCommand{
+				playerService: ps,
+				voided:        true,
+			},
+			"restore": &VoidMatchCommand{
+				playerService: ps,
+			},
+			"delete": &DeleteMatchCommand{
+				playerService: ps,
+			},
 			"new_player": &NewPlayerCommand{
 				playerService: ps,
 			},
+			"rename": &RenamePlayerCommand{
+				playerService: ps,
+			},
+			"merge": &MergePlayersCommand{
+				playerService: ps,
+			},
+			"deactivate": &DeactivatePlayerCommand{
+				playerService: ps,
+				deactivated:   true,
+			},
+			"activate": &DeactivatePlayerCommand{
+				playerService: ps,
+			},
 			"sub": &SubCommand{
 				botStorage: bs,
 				sub:        subFn,
@@ -119,3 +166,24 @@ func (uc *Commands) runCommand(user model.User, args string, resp *tgbotapi.Mess
 	}
 	return nil
 }
+
+// splitDiscipline returns the discipline named by the first field and the rest of the fields.
+// If the first field is not a discipline name, the default discipline and all the fields are returned.
+func splitDiscipline(ps *service.PlayerService, fields []string) (domain.Discipline, []string) {
+	if len(fields) > 0 {
+		if discipline, err := ps.Discipline(fields[0]); err == nil {
+			return discipline, fields[1:]
+		}
+	}
+	discipline, _ := ps.Discipline("")
+	return discipline, fields
+}
+
+// author names the user in the audit trail of the matches.
+func author(user model.User) string {
+	name := user.Username
+	if name == "" {
+		name = user.FirstName
+	}
+	return "telegram:" + name
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/goserg/ratingserver/internal/cache/mem"
 	"github.com/goserg/ratingserver/internal/config"
 	"github.com/goserg/ratingserver/internal/logger"
+	"github.com/goserg/ratingserver/internal/rating"
 	"github.com/goserg/ratingserver/internal/service"
 	"github.com/goserg/ratingserver/internal/storage/sqlite"
 	"github.com/goserg/ratingserver/internal/web"
@@ -44,11 +45,17 @@ func run() error {
 		return err
 	}
 
-	playerService, err := service.New(storage, storage, mem.New())
+	systems, err := rating.New(cfg.Server.Rating)
 	if err != nil {
 		return err
 	}
 
+	playerService, err := service.New(storage, storage, storage, storage, storage, storage, mem.New(), systems, cfg.Server.Rating)
+	if err != nil {
+		return err
+	}
+	playerService.OnPlayersMerged(botStorage.RelinkPlayer)
+
 	if !cfg.Server.TgBotDisable {
 		bot, err := tgbot.New(playerService, botStorage, cfg, log)
 		if err != nil {
@@ -0,0 +1,19 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type FfaMatches struct {
+	ID           int32 `sql:"primary_key"`
+	CreatedAt    time.Time
+	DisciplineID int32
+	PlayedAt     time.Time
+}
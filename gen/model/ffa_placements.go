@@ -0,0 +1,15 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type FfaPlacements struct {
+	MatchID  int32  `sql:"primary_key"`
+	PlayerID string `sql:"primary_key"`
+	Place    int32
+	Position int32
+}
@@ -0,0 +1,24 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type LadderChallenges struct {
+	ID           int32 `sql:"primary_key"`
+	DisciplineID int32
+	ChallengerID string
+	ChallengedID string
+	CreatedAt    time.Time
+	Deadline     time.Time
+	AcceptedAt   *time.Time
+	CancelledAt  *time.Time
+	MatchID      *int32
+}
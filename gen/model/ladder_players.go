@@ -0,0 +1,18 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type LadderPlayers struct {
+	DisciplineID int32  `sql:"primary_key"`
+	PlayerID     string `sql:"primary_key"`
+	JoinedAt     time.Time
+}
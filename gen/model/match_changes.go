@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type MatchChanges struct {
+	ID        int32 `sql:"primary_key"`
+	MatchID   int32
+	Action    string
+	Author    string
+	OldValue  string
+	NewValue  string
+	CreatedAt time.Time
+}
@@ -12,9 +12,14 @@ import (
 )
 
 type Matches struct {
-	ID        int32 `sql:"primary_key"`
-	PlayerA   string
-	PlayerB   string
-	Winner    *string
-	CreatedAt time.Time
+	ID           int32 `sql:"primary_key"`
+	PlayerA      string
+	PlayerB      string
+	Winner       *string
+	CreatedAt    time.Time
+	ScoreA       *int32
+	ScoreB       *int32
+	DisciplineID int32
+	Voided       bool
+	PlayedAt     time.Time
 }
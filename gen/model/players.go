@@ -12,7 +12,8 @@ import (
 )
 
 type Players struct {
-	ID        string `sql:"primary_key"`
-	Name      string
-	CreatedAt time.Time
+	ID          string `sql:"primary_key"`
+	Name        string
+	CreatedAt   time.Time
+	Deactivated bool
 }
@@ -0,0 +1,26 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type RatingHistory struct {
+	ID              int32 `sql:"primary_key"`
+	MatchID         *int32
+	FfaMatchID      *int32
+	PlayerID        string
+	System          string
+	RatingBefore    float64
+	RatingAfter     float64
+	DeviationBefore float64
+	DeviationAfter  float64
+	PlayedAt        time.Time
+	DisciplineID    int32
+}
@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type SeasonStandings struct {
+	SeasonID     int32  `sql:"primary_key"`
+	DisciplineID int32  `sql:"primary_key"`
+	PlayerID     string `sql:"primary_key"`
+	Place        int32
+	GamesPlayed  int32
+	System       string `sql:"primary_key"`
+	Rating       float64
+	Deviation    float64
+	IntervalMin  float64
+	IntervalMax  float64
+	Precision    int32
+}
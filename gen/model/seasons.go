@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type Seasons struct {
+	ID         int32 `sql:"primary_key"`
+	Name       string
+	StartAt    time.Time
+	EndAt      time.Time
+	Carryover  float64
+	ArchivedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,18 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type TournamentGames struct {
+	TournamentID int32  `sql:"primary_key"`
+	Bracket      string `sql:"primary_key"`
+	Round        int32  `sql:"primary_key"`
+	Position     int32  `sql:"primary_key"`
+	PlayerA      string
+	PlayerB      string
+	MatchID      *int32
+}
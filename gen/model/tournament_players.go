@@ -0,0 +1,14 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+type TournamentPlayers struct {
+	TournamentID int32  `sql:"primary_key"`
+	PlayerID     string `sql:"primary_key"`
+	Seed         int32
+}
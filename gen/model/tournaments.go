@@ -0,0 +1,22 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package model
+
+import (
+	"time"
+)
+
+type Tournaments struct {
+	ID           int32 `sql:"primary_key"`
+	Name         string
+	DisciplineID int32
+	Format       string
+	Rounds       int32
+	CreatedAt    time.Time
+	FinishedAt   *time.Time
+}
@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var Disciplines = newDisciplinesTable("", "disciplines", "")
+
+type disciplinesTable struct {
+	sqlite.Table
+
+	// Columns
+	ID        sqlite.ColumnInteger
+	Name      sqlite.ColumnString
+	CreatedAt sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type DisciplinesTable struct {
+	disciplinesTable
+
+	EXCLUDED disciplinesTable
+}
+
+// AS creates new DisciplinesTable with assigned alias
+func (a DisciplinesTable) AS(alias string) *DisciplinesTable {
+	return newDisciplinesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new DisciplinesTable with assigned schema name
+func (a DisciplinesTable) FromSchema(schemaName string) *DisciplinesTable {
+	return newDisciplinesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new DisciplinesTable with assigned table prefix
+func (a DisciplinesTable) WithPrefix(prefix string) *DisciplinesTable {
+	return newDisciplinesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new DisciplinesTable with assigned table suffix
+func (a DisciplinesTable) WithSuffix(suffix string) *DisciplinesTable {
+	return newDisciplinesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newDisciplinesTable(schemaName, tableName, alias string) *DisciplinesTable {
+	return &DisciplinesTable{
+		disciplinesTable: newDisciplinesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:         newDisciplinesTableImpl("", "excluded", ""),
+	}
+}
+
+func newDisciplinesTableImpl(schemaName, tableName, alias string) disciplinesTable {
+	var (
+		IDColumn        = sqlite.IntegerColumn("id")
+		NameColumn      = sqlite.StringColumn("name")
+		CreatedAtColumn = sqlite.TimestampColumn("created_at")
+		allColumns      = sqlite.ColumnList{IDColumn, NameColumn, CreatedAtColumn}
+		mutableColumns  = sqlite.ColumnList{NameColumn, CreatedAtColumn}
+	)
+
+	return disciplinesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:        IDColumn,
+		Name:      NameColumn,
+		CreatedAt: CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
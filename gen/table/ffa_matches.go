@@ -0,0 +1,84 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var FfaMatches = newFfaMatchesTable("", "ffa_matches", "")
+
+type ffaMatchesTable struct {
+	sqlite.Table
+
+	// Columns
+	ID           sqlite.ColumnInteger
+	CreatedAt    sqlite.ColumnTimestamp
+	DisciplineID sqlite.ColumnInteger
+	PlayedAt     sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type FfaMatchesTable struct {
+	ffaMatchesTable
+
+	EXCLUDED ffaMatchesTable
+}
+
+// AS creates new FfaMatchesTable with assigned alias
+func (a FfaMatchesTable) AS(alias string) *FfaMatchesTable {
+	return newFfaMatchesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new FfaMatchesTable with assigned schema name
+func (a FfaMatchesTable) FromSchema(schemaName string) *FfaMatchesTable {
+	return newFfaMatchesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new FfaMatchesTable with assigned table prefix
+func (a FfaMatchesTable) WithPrefix(prefix string) *FfaMatchesTable {
+	return newFfaMatchesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new FfaMatchesTable with assigned table suffix
+func (a FfaMatchesTable) WithSuffix(suffix string) *FfaMatchesTable {
+	return newFfaMatchesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newFfaMatchesTable(schemaName, tableName, alias string) *FfaMatchesTable {
+	return &FfaMatchesTable{
+		ffaMatchesTable: newFfaMatchesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:        newFfaMatchesTableImpl("", "excluded", ""),
+	}
+}
+
+func newFfaMatchesTableImpl(schemaName, tableName, alias string) ffaMatchesTable {
+	var (
+		IDColumn           = sqlite.IntegerColumn("id")
+		CreatedAtColumn    = sqlite.TimestampColumn("created_at")
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		PlayedAtColumn     = sqlite.TimestampColumn("played_at")
+		allColumns         = sqlite.ColumnList{IDColumn, CreatedAtColumn, DisciplineIDColumn, PlayedAtColumn}
+		mutableColumns     = sqlite.ColumnList{CreatedAtColumn, DisciplineIDColumn, PlayedAtColumn}
+	)
+
+	return ffaMatchesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:           IDColumn,
+		CreatedAt:    CreatedAtColumn,
+		DisciplineID: DisciplineIDColumn,
+		PlayedAt:     PlayedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,84 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var FfaPlacements = newFfaPlacementsTable("", "ffa_placements", "")
+
+type ffaPlacementsTable struct {
+	sqlite.Table
+
+	// Columns
+	MatchID  sqlite.ColumnInteger
+	PlayerID sqlite.ColumnString
+	Place    sqlite.ColumnInteger
+	Position sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type FfaPlacementsTable struct {
+	ffaPlacementsTable
+
+	EXCLUDED ffaPlacementsTable
+}
+
+// AS creates new FfaPlacementsTable with assigned alias
+func (a FfaPlacementsTable) AS(alias string) *FfaPlacementsTable {
+	return newFfaPlacementsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new FfaPlacementsTable with assigned schema name
+func (a FfaPlacementsTable) FromSchema(schemaName string) *FfaPlacementsTable {
+	return newFfaPlacementsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new FfaPlacementsTable with assigned table prefix
+func (a FfaPlacementsTable) WithPrefix(prefix string) *FfaPlacementsTable {
+	return newFfaPlacementsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new FfaPlacementsTable with assigned table suffix
+func (a FfaPlacementsTable) WithSuffix(suffix string) *FfaPlacementsTable {
+	return newFfaPlacementsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newFfaPlacementsTable(schemaName, tableName, alias string) *FfaPlacementsTable {
+	return &FfaPlacementsTable{
+		ffaPlacementsTable: newFfaPlacementsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:           newFfaPlacementsTableImpl("", "excluded", ""),
+	}
+}
+
+func newFfaPlacementsTableImpl(schemaName, tableName, alias string) ffaPlacementsTable {
+	var (
+		MatchIDColumn  = sqlite.IntegerColumn("match_id")
+		PlayerIDColumn = sqlite.StringColumn("player_id")
+		PlaceColumn    = sqlite.IntegerColumn("place")
+		PositionColumn = sqlite.IntegerColumn("position")
+		allColumns     = sqlite.ColumnList{MatchIDColumn, PlayerIDColumn, PlaceColumn, PositionColumn}
+		mutableColumns = sqlite.ColumnList{PlaceColumn, PositionColumn}
+	)
+
+	return ffaPlacementsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		MatchID:  MatchIDColumn,
+		PlayerID: PlayerIDColumn,
+		Place:    PlaceColumn,
+		Position: PositionColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
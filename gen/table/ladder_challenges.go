@@ -0,0 +1,99 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var LadderChallenges = newLadderChallengesTable("", "ladder_challenges", "")
+
+type ladderChallengesTable struct {
+	sqlite.Table
+
+	// Columns
+	ID           sqlite.ColumnInteger
+	DisciplineID sqlite.ColumnInteger
+	ChallengerID sqlite.ColumnString
+	ChallengedID sqlite.ColumnString
+	CreatedAt    sqlite.ColumnTimestamp
+	Deadline     sqlite.ColumnTimestamp
+	AcceptedAt   sqlite.ColumnTimestamp
+	CancelledAt  sqlite.ColumnTimestamp
+	MatchID      sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type LadderChallengesTable struct {
+	ladderChallengesTable
+
+	EXCLUDED ladderChallengesTable
+}
+
+// AS creates new LadderChallengesTable with assigned alias
+func (a LadderChallengesTable) AS(alias string) *LadderChallengesTable {
+	return newLadderChallengesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new LadderChallengesTable with assigned schema name
+func (a LadderChallengesTable) FromSchema(schemaName string) *LadderChallengesTable {
+	return newLadderChallengesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new LadderChallengesTable with assigned table prefix
+func (a LadderChallengesTable) WithPrefix(prefix string) *LadderChallengesTable {
+	return newLadderChallengesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new LadderChallengesTable with assigned table suffix
+func (a LadderChallengesTable) WithSuffix(suffix string) *LadderChallengesTable {
+	return newLadderChallengesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newLadderChallengesTable(schemaName, tableName, alias string) *LadderChallengesTable {
+	return &LadderChallengesTable{
+		ladderChallengesTable: newLadderChallengesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:              newLadderChallengesTableImpl("", "excluded", ""),
+	}
+}
+
+func newLadderChallengesTableImpl(schemaName, tableName, alias string) ladderChallengesTable {
+	var (
+		IDColumn           = sqlite.IntegerColumn("id")
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		ChallengerIDColumn = sqlite.StringColumn("challenger_id")
+		ChallengedIDColumn = sqlite.StringColumn("challenged_id")
+		CreatedAtColumn    = sqlite.TimestampColumn("created_at")
+		DeadlineColumn     = sqlite.TimestampColumn("deadline")
+		AcceptedAtColumn   = sqlite.TimestampColumn("accepted_at")
+		CancelledAtColumn  = sqlite.TimestampColumn("cancelled_at")
+		MatchIDColumn      = sqlite.IntegerColumn("match_id")
+		allColumns         = sqlite.ColumnList{IDColumn, DisciplineIDColumn, ChallengerIDColumn, ChallengedIDColumn, CreatedAtColumn, DeadlineColumn, AcceptedAtColumn, CancelledAtColumn, MatchIDColumn}
+		mutableColumns     = sqlite.ColumnList{DisciplineIDColumn, ChallengerIDColumn, ChallengedIDColumn, CreatedAtColumn, DeadlineColumn, AcceptedAtColumn, CancelledAtColumn, MatchIDColumn}
+	)
+
+	return ladderChallengesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:           IDColumn,
+		DisciplineID: DisciplineIDColumn,
+		ChallengerID: ChallengerIDColumn,
+		ChallengedID: ChallengedIDColumn,
+		CreatedAt:    CreatedAtColumn,
+		Deadline:     DeadlineColumn,
+		AcceptedAt:   AcceptedAtColumn,
+		CancelledAt:  CancelledAtColumn,
+		MatchID:      MatchIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
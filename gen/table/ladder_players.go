@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var LadderPlayers = newLadderPlayersTable("", "ladder_players", "")
+
+type ladderPlayersTable struct {
+	sqlite.Table
+
+	// Columns
+	DisciplineID sqlite.ColumnInteger
+	PlayerID     sqlite.ColumnString
+	JoinedAt     sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type LadderPlayersTable struct {
+	ladderPlayersTable
+
+	EXCLUDED ladderPlayersTable
+}
+
+// AS creates new LadderPlayersTable with assigned alias
+func (a LadderPlayersTable) AS(alias string) *LadderPlayersTable {
+	return newLadderPlayersTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new LadderPlayersTable with assigned schema name
+func (a LadderPlayersTable) FromSchema(schemaName string) *LadderPlayersTable {
+	return newLadderPlayersTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new LadderPlayersTable with assigned table prefix
+func (a LadderPlayersTable) WithPrefix(prefix string) *LadderPlayersTable {
+	return newLadderPlayersTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new LadderPlayersTable with assigned table suffix
+func (a LadderPlayersTable) WithSuffix(suffix string) *LadderPlayersTable {
+	return newLadderPlayersTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newLadderPlayersTable(schemaName, tableName, alias string) *LadderPlayersTable {
+	return &LadderPlayersTable{
+		ladderPlayersTable: newLadderPlayersTableImpl(schemaName, tableName, alias),
+		EXCLUDED:           newLadderPlayersTableImpl("", "excluded", ""),
+	}
+}
+
+func newLadderPlayersTableImpl(schemaName, tableName, alias string) ladderPlayersTable {
+	var (
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		PlayerIDColumn     = sqlite.StringColumn("player_id")
+		JoinedAtColumn     = sqlite.TimestampColumn("joined_at")
+		allColumns         = sqlite.ColumnList{DisciplineIDColumn, PlayerIDColumn, JoinedAtColumn}
+		mutableColumns     = sqlite.ColumnList{JoinedAtColumn}
+	)
+
+	return ladderPlayersTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		DisciplineID: DisciplineIDColumn,
+		PlayerID:     PlayerIDColumn,
+		JoinedAt:     JoinedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
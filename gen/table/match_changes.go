@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var MatchChanges = newMatchChangesTable("", "match_changes", "")
+
+type matchChangesTable struct {
+	sqlite.Table
+
+	// Columns
+	ID        sqlite.ColumnInteger
+	MatchID   sqlite.ColumnInteger
+	Action    sqlite.ColumnString
+	Author    sqlite.ColumnString
+	OldValue  sqlite.ColumnString
+	NewValue  sqlite.ColumnString
+	CreatedAt sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type MatchChangesTable struct {
+	matchChangesTable
+
+	EXCLUDED matchChangesTable
+}
+
+// AS creates new MatchChangesTable with assigned alias
+func (a MatchChangesTable) AS(alias string) *MatchChangesTable {
+	return newMatchChangesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new MatchChangesTable with assigned schema name
+func (a MatchChangesTable) FromSchema(schemaName string) *MatchChangesTable {
+	return newMatchChangesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new MatchChangesTable with assigned table prefix
+func (a MatchChangesTable) WithPrefix(prefix string) *MatchChangesTable {
+	return newMatchChangesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new MatchChangesTable with assigned table suffix
+func (a MatchChangesTable) WithSuffix(suffix string) *MatchChangesTable {
+	return newMatchChangesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newMatchChangesTable(schemaName, tableName, alias string) *MatchChangesTable {
+	return &MatchChangesTable{
+		matchChangesTable: newMatchChangesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:          newMatchChangesTableImpl("", "excluded", ""),
+	}
+}
+
+func newMatchChangesTableImpl(schemaName, tableName, alias string) matchChangesTable {
+	var (
+		IDColumn        = sqlite.IntegerColumn("id")
+		MatchIDColumn   = sqlite.IntegerColumn("match_id")
+		ActionColumn    = sqlite.StringColumn("action")
+		AuthorColumn    = sqlite.StringColumn("author")
+		OldValueColumn  = sqlite.StringColumn("old_value")
+		NewValueColumn  = sqlite.StringColumn("new_value")
+		CreatedAtColumn = sqlite.TimestampColumn("created_at")
+		allColumns      = sqlite.ColumnList{IDColumn, MatchIDColumn, ActionColumn, AuthorColumn, OldValueColumn, NewValueColumn, CreatedAtColumn}
+		mutableColumns  = sqlite.ColumnList{MatchIDColumn, ActionColumn, AuthorColumn, OldValueColumn, NewValueColumn, CreatedAtColumn}
+	)
+
+	return matchChangesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:        IDColumn,
+		MatchID:   MatchIDColumn,
+		Action:    ActionColumn,
+		Author:    AuthorColumn,
+		OldValue:  OldValueColumn,
+		NewValue:  NewValueColumn,
+		CreatedAt: CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
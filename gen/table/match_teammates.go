@@ -0,0 +1,84 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var MatchTeammates = newMatchTeammatesTable("", "match_teammates", "")
+
+type matchTeammatesTable struct {
+	sqlite.Table
+
+	// Columns
+	MatchID  sqlite.ColumnInteger
+	PlayerID sqlite.ColumnString
+	Side     sqlite.ColumnString
+	Position sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type MatchTeammatesTable struct {
+	matchTeammatesTable
+
+	EXCLUDED matchTeammatesTable
+}
+
+// AS creates new MatchTeammatesTable with assigned alias
+func (a MatchTeammatesTable) AS(alias string) *MatchTeammatesTable {
+	return newMatchTeammatesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new MatchTeammatesTable with assigned schema name
+func (a MatchTeammatesTable) FromSchema(schemaName string) *MatchTeammatesTable {
+	return newMatchTeammatesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new MatchTeammatesTable with assigned table prefix
+func (a MatchTeammatesTable) WithPrefix(prefix string) *MatchTeammatesTable {
+	return newMatchTeammatesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new MatchTeammatesTable with assigned table suffix
+func (a MatchTeammatesTable) WithSuffix(suffix string) *MatchTeammatesTable {
+	return newMatchTeammatesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newMatchTeammatesTable(schemaName, tableName, alias string) *MatchTeammatesTable {
+	return &MatchTeammatesTable{
+		matchTeammatesTable: newMatchTeammatesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:            newMatchTeammatesTableImpl("", "excluded", ""),
+	}
+}
+
+func newMatchTeammatesTableImpl(schemaName, tableName, alias string) matchTeammatesTable {
+	var (
+		MatchIDColumn  = sqlite.IntegerColumn("match_id")
+		PlayerIDColumn = sqlite.StringColumn("player_id")
+		SideColumn     = sqlite.StringColumn("side")
+		PositionColumn = sqlite.IntegerColumn("position")
+		allColumns     = sqlite.ColumnList{MatchIDColumn, PlayerIDColumn, SideColumn, PositionColumn}
+		mutableColumns = sqlite.ColumnList{SideColumn, PositionColumn}
+	)
+
+	return matchTeammatesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		MatchID:  MatchIDColumn,
+		PlayerID: PlayerIDColumn,
+		Side:     SideColumn,
+		Position: PositionColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
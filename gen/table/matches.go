@@ -17,11 +17,16 @@ type matchesTable struct {
 	sqlite.Table
 
 	// Columns
-	ID        sqlite.ColumnInteger
-	PlayerA   sqlite.ColumnString
-	PlayerB   sqlite.ColumnString
-	Winner    sqlite.ColumnString
-	CreatedAt sqlite.ColumnTimestamp
+	ID           sqlite.ColumnInteger
+	PlayerA      sqlite.ColumnString
+	PlayerB      sqlite.ColumnString
+	Winner       sqlite.ColumnString
+	CreatedAt    sqlite.ColumnTimestamp
+	ScoreA       sqlite.ColumnInteger
+	ScoreB       sqlite.ColumnInteger
+	DisciplineID sqlite.ColumnInteger
+	Voided       sqlite.ColumnBool
+	PlayedAt     sqlite.ColumnTimestamp
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -62,24 +67,34 @@ func newMatchesTable(schemaName, tableName, alias string) *MatchesTable {
 
 func newMatchesTableImpl(schemaName, tableName, alias string) matchesTable {
 	var (
-		IDColumn        = sqlite.IntegerColumn("id")
-		PlayerAColumn   = sqlite.StringColumn("player_a")
-		PlayerBColumn   = sqlite.StringColumn("player_b")
-		WinnerColumn    = sqlite.StringColumn("winner")
-		CreatedAtColumn = sqlite.TimestampColumn("created_at")
-		allColumns      = sqlite.ColumnList{IDColumn, PlayerAColumn, PlayerBColumn, WinnerColumn, CreatedAtColumn}
-		mutableColumns  = sqlite.ColumnList{PlayerAColumn, PlayerBColumn, WinnerColumn, CreatedAtColumn}
+		IDColumn           = sqlite.IntegerColumn("id")
+		PlayerAColumn      = sqlite.StringColumn("player_a")
+		PlayerBColumn      = sqlite.StringColumn("player_b")
+		WinnerColumn       = sqlite.StringColumn("winner")
+		CreatedAtColumn    = sqlite.TimestampColumn("created_at")
+		ScoreAColumn       = sqlite.IntegerColumn("score_a")
+		ScoreBColumn       = sqlite.IntegerColumn("score_b")
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		VoidedColumn       = sqlite.BoolColumn("voided")
+		PlayedAtColumn     = sqlite.TimestampColumn("played_at")
+		allColumns         = sqlite.ColumnList{IDColumn, PlayerAColumn, PlayerBColumn, WinnerColumn, CreatedAtColumn, ScoreAColumn, ScoreBColumn, DisciplineIDColumn, VoidedColumn, PlayedAtColumn}
+		mutableColumns     = sqlite.ColumnList{PlayerAColumn, PlayerBColumn, WinnerColumn, CreatedAtColumn, ScoreAColumn, ScoreBColumn, DisciplineIDColumn, VoidedColumn, PlayedAtColumn}
 	)
 
 	return matchesTable{
 		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		PlayerA:   PlayerAColumn,
-		PlayerB:   PlayerBColumn,
-		Winner:    WinnerColumn,
-		CreatedAt: CreatedAtColumn,
+		ID:           IDColumn,
+		PlayerA:      PlayerAColumn,
+		PlayerB:      PlayerBColumn,
+		Winner:       WinnerColumn,
+		CreatedAt:    CreatedAtColumn,
+		ScoreA:       ScoreAColumn,
+		ScoreB:       ScoreBColumn,
+		DisciplineID: DisciplineIDColumn,
+		Voided:       VoidedColumn,
+		PlayedAt:     PlayedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
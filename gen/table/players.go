@@ -17,9 +17,10 @@ type playersTable struct {
 	sqlite.Table
 
 	// Columns
-	ID        sqlite.ColumnString
-	Name      sqlite.ColumnString
-	CreatedAt sqlite.ColumnTimestamp
+	ID          sqlite.ColumnString
+	Name        sqlite.ColumnString
+	CreatedAt   sqlite.ColumnTimestamp
+	Deactivated sqlite.ColumnBool
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -60,20 +61,22 @@ func newPlayersTable(schemaName, tableName, alias string) *PlayersTable {
 
 func newPlayersTableImpl(schemaName, tableName, alias string) playersTable {
 	var (
-		IDColumn        = sqlite.StringColumn("id")
-		NameColumn      = sqlite.StringColumn("name")
-		CreatedAtColumn = sqlite.TimestampColumn("created_at")
-		allColumns      = sqlite.ColumnList{IDColumn, NameColumn, CreatedAtColumn}
-		mutableColumns  = sqlite.ColumnList{NameColumn, CreatedAtColumn}
+		IDColumn          = sqlite.StringColumn("id")
+		NameColumn        = sqlite.StringColumn("name")
+		CreatedAtColumn   = sqlite.TimestampColumn("created_at")
+		DeactivatedColumn = sqlite.BoolColumn("deactivated")
+		allColumns        = sqlite.ColumnList{IDColumn, NameColumn, CreatedAtColumn, DeactivatedColumn}
+		mutableColumns    = sqlite.ColumnList{NameColumn, CreatedAtColumn, DeactivatedColumn}
 	)
 
 	return playersTable{
 		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		Name:      NameColumn,
-		CreatedAt: CreatedAtColumn,
+		ID:          IDColumn,
+		Name:        NameColumn,
+		CreatedAt:   CreatedAtColumn,
+		Deactivated: DeactivatedColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
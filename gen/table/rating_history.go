@@ -0,0 +1,105 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var RatingHistory = newRatingHistoryTable("", "rating_history", "")
+
+type ratingHistoryTable struct {
+	sqlite.Table
+
+	// Columns
+	ID              sqlite.ColumnInteger
+	MatchID         sqlite.ColumnInteger
+	FfaMatchID      sqlite.ColumnInteger
+	PlayerID        sqlite.ColumnString
+	System          sqlite.ColumnString
+	RatingBefore    sqlite.ColumnFloat
+	RatingAfter     sqlite.ColumnFloat
+	DeviationBefore sqlite.ColumnFloat
+	DeviationAfter  sqlite.ColumnFloat
+	PlayedAt        sqlite.ColumnTimestamp
+	DisciplineID    sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type RatingHistoryTable struct {
+	ratingHistoryTable
+
+	EXCLUDED ratingHistoryTable
+}
+
+// AS creates new RatingHistoryTable with assigned alias
+func (a RatingHistoryTable) AS(alias string) *RatingHistoryTable {
+	return newRatingHistoryTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new RatingHistoryTable with assigned schema name
+func (a RatingHistoryTable) FromSchema(schemaName string) *RatingHistoryTable {
+	return newRatingHistoryTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new RatingHistoryTable with assigned table prefix
+func (a RatingHistoryTable) WithPrefix(prefix string) *RatingHistoryTable {
+	return newRatingHistoryTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new RatingHistoryTable with assigned table suffix
+func (a RatingHistoryTable) WithSuffix(suffix string) *RatingHistoryTable {
+	return newRatingHistoryTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newRatingHistoryTable(schemaName, tableName, alias string) *RatingHistoryTable {
+	return &RatingHistoryTable{
+		ratingHistoryTable: newRatingHistoryTableImpl(schemaName, tableName, alias),
+		EXCLUDED:           newRatingHistoryTableImpl("", "excluded", ""),
+	}
+}
+
+func newRatingHistoryTableImpl(schemaName, tableName, alias string) ratingHistoryTable {
+	var (
+		IDColumn              = sqlite.IntegerColumn("id")
+		MatchIDColumn         = sqlite.IntegerColumn("match_id")
+		FfaMatchIDColumn      = sqlite.IntegerColumn("ffa_match_id")
+		PlayerIDColumn        = sqlite.StringColumn("player_id")
+		SystemColumn          = sqlite.StringColumn("system")
+		RatingBeforeColumn    = sqlite.FloatColumn("rating_before")
+		RatingAfterColumn     = sqlite.FloatColumn("rating_after")
+		DeviationBeforeColumn = sqlite.FloatColumn("deviation_before")
+		DeviationAfterColumn  = sqlite.FloatColumn("deviation_after")
+		PlayedAtColumn        = sqlite.TimestampColumn("played_at")
+		DisciplineIDColumn    = sqlite.IntegerColumn("discipline_id")
+		allColumns            = sqlite.ColumnList{IDColumn, MatchIDColumn, FfaMatchIDColumn, PlayerIDColumn, SystemColumn, RatingBeforeColumn, RatingAfterColumn, DeviationBeforeColumn, DeviationAfterColumn, PlayedAtColumn, DisciplineIDColumn}
+		mutableColumns        = sqlite.ColumnList{MatchIDColumn, FfaMatchIDColumn, PlayerIDColumn, SystemColumn, RatingBeforeColumn, RatingAfterColumn, DeviationBeforeColumn, DeviationAfterColumn, PlayedAtColumn, DisciplineIDColumn}
+	)
+
+	return ratingHistoryTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:              IDColumn,
+		MatchID:         MatchIDColumn,
+		FfaMatchID:      FfaMatchIDColumn,
+		PlayerID:        PlayerIDColumn,
+		System:          SystemColumn,
+		RatingBefore:    RatingBeforeColumn,
+		RatingAfter:     RatingAfterColumn,
+		DeviationBefore: DeviationBeforeColumn,
+		DeviationAfter:  DeviationAfterColumn,
+		PlayedAt:        PlayedAtColumn,
+		DisciplineID:    DisciplineIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,105 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var SeasonStandings = newSeasonStandingsTable("", "season_standings", "")
+
+type seasonStandingsTable struct {
+	sqlite.Table
+
+	// Columns
+	SeasonID     sqlite.ColumnInteger
+	DisciplineID sqlite.ColumnInteger
+	PlayerID     sqlite.ColumnString
+	Place        sqlite.ColumnInteger
+	GamesPlayed  sqlite.ColumnInteger
+	System       sqlite.ColumnString
+	Rating       sqlite.ColumnFloat
+	Deviation    sqlite.ColumnFloat
+	IntervalMin  sqlite.ColumnFloat
+	IntervalMax  sqlite.ColumnFloat
+	Precision    sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type SeasonStandingsTable struct {
+	seasonStandingsTable
+
+	EXCLUDED seasonStandingsTable
+}
+
+// AS creates new SeasonStandingsTable with assigned alias
+func (a SeasonStandingsTable) AS(alias string) *SeasonStandingsTable {
+	return newSeasonStandingsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new SeasonStandingsTable with assigned schema name
+func (a SeasonStandingsTable) FromSchema(schemaName string) *SeasonStandingsTable {
+	return newSeasonStandingsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new SeasonStandingsTable with assigned table prefix
+func (a SeasonStandingsTable) WithPrefix(prefix string) *SeasonStandingsTable {
+	return newSeasonStandingsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new SeasonStandingsTable with assigned table suffix
+func (a SeasonStandingsTable) WithSuffix(suffix string) *SeasonStandingsTable {
+	return newSeasonStandingsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newSeasonStandingsTable(schemaName, tableName, alias string) *SeasonStandingsTable {
+	return &SeasonStandingsTable{
+		seasonStandingsTable: newSeasonStandingsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:             newSeasonStandingsTableImpl("", "excluded", ""),
+	}
+}
+
+func newSeasonStandingsTableImpl(schemaName, tableName, alias string) seasonStandingsTable {
+	var (
+		SeasonIDColumn     = sqlite.IntegerColumn("season_id")
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		PlayerIDColumn     = sqlite.StringColumn("player_id")
+		PlaceColumn        = sqlite.IntegerColumn("place")
+		GamesPlayedColumn  = sqlite.IntegerColumn("games_played")
+		SystemColumn       = sqlite.StringColumn("system")
+		RatingColumn       = sqlite.FloatColumn("rating")
+		DeviationColumn    = sqlite.FloatColumn("deviation")
+		IntervalMinColumn  = sqlite.FloatColumn("interval_min")
+		IntervalMaxColumn  = sqlite.FloatColumn("interval_max")
+		PrecisionColumn    = sqlite.IntegerColumn("precision")
+		allColumns         = sqlite.ColumnList{SeasonIDColumn, DisciplineIDColumn, PlayerIDColumn, PlaceColumn, GamesPlayedColumn, SystemColumn, RatingColumn, DeviationColumn, IntervalMinColumn, IntervalMaxColumn, PrecisionColumn}
+		mutableColumns     = sqlite.ColumnList{PlaceColumn, GamesPlayedColumn, RatingColumn, DeviationColumn, IntervalMinColumn, IntervalMaxColumn, PrecisionColumn}
+	)
+
+	return seasonStandingsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		SeasonID:     SeasonIDColumn,
+		DisciplineID: DisciplineIDColumn,
+		PlayerID:     PlayerIDColumn,
+		Place:        PlaceColumn,
+		GamesPlayed:  GamesPlayedColumn,
+		System:       SystemColumn,
+		Rating:       RatingColumn,
+		Deviation:    DeviationColumn,
+		IntervalMin:  IntervalMinColumn,
+		IntervalMax:  IntervalMaxColumn,
+		Precision:    PrecisionColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var Seasons = newSeasonsTable("", "seasons", "")
+
+type seasonsTable struct {
+	sqlite.Table
+
+	// Columns
+	ID         sqlite.ColumnInteger
+	Name       sqlite.ColumnString
+	StartAt    sqlite.ColumnTimestamp
+	EndAt      sqlite.ColumnTimestamp
+	Carryover  sqlite.ColumnFloat
+	ArchivedAt sqlite.ColumnTimestamp
+	CreatedAt  sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type SeasonsTable struct {
+	seasonsTable
+
+	EXCLUDED seasonsTable
+}
+
+// AS creates new SeasonsTable with assigned alias
+func (a SeasonsTable) AS(alias string) *SeasonsTable {
+	return newSeasonsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new SeasonsTable with assigned schema name
+func (a SeasonsTable) FromSchema(schemaName string) *SeasonsTable {
+	return newSeasonsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new SeasonsTable with assigned table prefix
+func (a SeasonsTable) WithPrefix(prefix string) *SeasonsTable {
+	return newSeasonsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new SeasonsTable with assigned table suffix
+func (a SeasonsTable) WithSuffix(suffix string) *SeasonsTable {
+	return newSeasonsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newSeasonsTable(schemaName, tableName, alias string) *SeasonsTable {
+	return &SeasonsTable{
+		seasonsTable: newSeasonsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:     newSeasonsTableImpl("", "excluded", ""),
+	}
+}
+
+func newSeasonsTableImpl(schemaName, tableName, alias string) seasonsTable {
+	var (
+		IDColumn         = sqlite.IntegerColumn("id")
+		NameColumn       = sqlite.StringColumn("name")
+		StartAtColumn    = sqlite.TimestampColumn("start_at")
+		EndAtColumn      = sqlite.TimestampColumn("end_at")
+		CarryoverColumn  = sqlite.FloatColumn("carryover")
+		ArchivedAtColumn = sqlite.TimestampColumn("archived_at")
+		CreatedAtColumn  = sqlite.TimestampColumn("created_at")
+		allColumns       = sqlite.ColumnList{IDColumn, NameColumn, StartAtColumn, EndAtColumn, CarryoverColumn, ArchivedAtColumn, CreatedAtColumn}
+		mutableColumns   = sqlite.ColumnList{NameColumn, StartAtColumn, EndAtColumn, CarryoverColumn, ArchivedAtColumn, CreatedAtColumn}
+	)
+
+	return seasonsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:         IDColumn,
+		Name:       NameColumn,
+		StartAt:    StartAtColumn,
+		EndAt:      EndAtColumn,
+		Carryover:  CarryoverColumn,
+		ArchivedAt: ArchivedAtColumn,
+		CreatedAt:  CreatedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -10,7 +10,20 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	Disciplines = Disciplines.FromSchema(schema)
+	FfaMatches = FfaMatches.FromSchema(schema)
+	FfaPlacements = FfaPlacements.FromSchema(schema)
+	LadderChallenges = LadderChallenges.FromSchema(schema)
+	LadderPlayers = LadderPlayers.FromSchema(schema)
+	MatchChanges = MatchChanges.FromSchema(schema)
+	MatchTeammates = MatchTeammates.FromSchema(schema)
 	Matches = Matches.FromSchema(schema)
 	Players = Players.FromSchema(schema)
+	RatingHistory = RatingHistory.FromSchema(schema)
 	SchemaMigrations = SchemaMigrations.FromSchema(schema)
+	SeasonStandings = SeasonStandings.FromSchema(schema)
+	Seasons = Seasons.FromSchema(schema)
+	TournamentGames = TournamentGames.FromSchema(schema)
+	TournamentPlayers = TournamentPlayers.FromSchema(schema)
+	Tournaments = Tournaments.FromSchema(schema)
 }
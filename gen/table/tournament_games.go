@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var TournamentGames = newTournamentGamesTable("", "tournament_games", "")
+
+type tournamentGamesTable struct {
+	sqlite.Table
+
+	// Columns
+	TournamentID sqlite.ColumnInteger
+	Bracket      sqlite.ColumnString
+	Round        sqlite.ColumnInteger
+	Position     sqlite.ColumnInteger
+	PlayerA      sqlite.ColumnString
+	PlayerB      sqlite.ColumnString
+	MatchID      sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type TournamentGamesTable struct {
+	tournamentGamesTable
+
+	EXCLUDED tournamentGamesTable
+}
+
+// AS creates new TournamentGamesTable with assigned alias
+func (a TournamentGamesTable) AS(alias string) *TournamentGamesTable {
+	return newTournamentGamesTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new TournamentGamesTable with assigned schema name
+func (a TournamentGamesTable) FromSchema(schemaName string) *TournamentGamesTable {
+	return newTournamentGamesTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new TournamentGamesTable with assigned table prefix
+func (a TournamentGamesTable) WithPrefix(prefix string) *TournamentGamesTable {
+	return newTournamentGamesTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new TournamentGamesTable with assigned table suffix
+func (a TournamentGamesTable) WithSuffix(suffix string) *TournamentGamesTable {
+	return newTournamentGamesTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newTournamentGamesTable(schemaName, tableName, alias string) *TournamentGamesTable {
+	return &TournamentGamesTable{
+		tournamentGamesTable: newTournamentGamesTableImpl(schemaName, tableName, alias),
+		EXCLUDED:             newTournamentGamesTableImpl("", "excluded", ""),
+	}
+}
+
+func newTournamentGamesTableImpl(schemaName, tableName, alias string) tournamentGamesTable {
+	var (
+		TournamentIDColumn = sqlite.IntegerColumn("tournament_id")
+		BracketColumn      = sqlite.StringColumn("bracket")
+		RoundColumn        = sqlite.IntegerColumn("round")
+		PositionColumn     = sqlite.IntegerColumn("position")
+		PlayerAColumn      = sqlite.StringColumn("player_a")
+		PlayerBColumn      = sqlite.StringColumn("player_b")
+		MatchIDColumn      = sqlite.IntegerColumn("match_id")
+		allColumns         = sqlite.ColumnList{TournamentIDColumn, BracketColumn, RoundColumn, PositionColumn, PlayerAColumn, PlayerBColumn, MatchIDColumn}
+		mutableColumns     = sqlite.ColumnList{PlayerAColumn, PlayerBColumn, MatchIDColumn}
+	)
+
+	return tournamentGamesTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		TournamentID: TournamentIDColumn,
+		Bracket:      BracketColumn,
+		Round:        RoundColumn,
+		Position:     PositionColumn,
+		PlayerA:      PlayerAColumn,
+		PlayerB:      PlayerBColumn,
+		MatchID:      MatchIDColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,81 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var TournamentPlayers = newTournamentPlayersTable("", "tournament_players", "")
+
+type tournamentPlayersTable struct {
+	sqlite.Table
+
+	// Columns
+	TournamentID sqlite.ColumnInteger
+	PlayerID     sqlite.ColumnString
+	Seed         sqlite.ColumnInteger
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type TournamentPlayersTable struct {
+	tournamentPlayersTable
+
+	EXCLUDED tournamentPlayersTable
+}
+
+// AS creates new TournamentPlayersTable with assigned alias
+func (a TournamentPlayersTable) AS(alias string) *TournamentPlayersTable {
+	return newTournamentPlayersTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new TournamentPlayersTable with assigned schema name
+func (a TournamentPlayersTable) FromSchema(schemaName string) *TournamentPlayersTable {
+	return newTournamentPlayersTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new TournamentPlayersTable with assigned table prefix
+func (a TournamentPlayersTable) WithPrefix(prefix string) *TournamentPlayersTable {
+	return newTournamentPlayersTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new TournamentPlayersTable with assigned table suffix
+func (a TournamentPlayersTable) WithSuffix(suffix string) *TournamentPlayersTable {
+	return newTournamentPlayersTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newTournamentPlayersTable(schemaName, tableName, alias string) *TournamentPlayersTable {
+	return &TournamentPlayersTable{
+		tournamentPlayersTable: newTournamentPlayersTableImpl(schemaName, tableName, alias),
+		EXCLUDED:               newTournamentPlayersTableImpl("", "excluded", ""),
+	}
+}
+
+func newTournamentPlayersTableImpl(schemaName, tableName, alias string) tournamentPlayersTable {
+	var (
+		TournamentIDColumn = sqlite.IntegerColumn("tournament_id")
+		PlayerIDColumn     = sqlite.StringColumn("player_id")
+		SeedColumn         = sqlite.IntegerColumn("seed")
+		allColumns         = sqlite.ColumnList{TournamentIDColumn, PlayerIDColumn, SeedColumn}
+		mutableColumns     = sqlite.ColumnList{SeedColumn}
+	)
+
+	return tournamentPlayersTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		TournamentID: TournamentIDColumn,
+		PlayerID:     PlayerIDColumn,
+		Seed:         SeedColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Code generated by go-jet DO NOT EDIT.
+//
+// WARNING: Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated
+//
+
+package table
+
+import (
+	"github.com/go-jet/jet/v2/sqlite"
+)
+
+var Tournaments = newTournamentsTable("", "tournaments", "")
+
+type tournamentsTable struct {
+	sqlite.Table
+
+	// Columns
+	ID           sqlite.ColumnInteger
+	Name         sqlite.ColumnString
+	DisciplineID sqlite.ColumnInteger
+	Format       sqlite.ColumnString
+	Rounds       sqlite.ColumnInteger
+	CreatedAt    sqlite.ColumnTimestamp
+	FinishedAt   sqlite.ColumnTimestamp
+
+	AllColumns     sqlite.ColumnList
+	MutableColumns sqlite.ColumnList
+}
+
+type TournamentsTable struct {
+	tournamentsTable
+
+	EXCLUDED tournamentsTable
+}
+
+// AS creates new TournamentsTable with assigned alias
+func (a TournamentsTable) AS(alias string) *TournamentsTable {
+	return newTournamentsTable(a.SchemaName(), a.TableName(), alias)
+}
+
+// Schema creates new TournamentsTable with assigned schema name
+func (a TournamentsTable) FromSchema(schemaName string) *TournamentsTable {
+	return newTournamentsTable(schemaName, a.TableName(), a.Alias())
+}
+
+// WithPrefix creates new TournamentsTable with assigned table prefix
+func (a TournamentsTable) WithPrefix(prefix string) *TournamentsTable {
+	return newTournamentsTable(a.SchemaName(), prefix+a.TableName(), a.TableName())
+}
+
+// WithSuffix creates new TournamentsTable with assigned table suffix
+func (a TournamentsTable) WithSuffix(suffix string) *TournamentsTable {
+	return newTournamentsTable(a.SchemaName(), a.TableName()+suffix, a.TableName())
+}
+
+func newTournamentsTable(schemaName, tableName, alias string) *TournamentsTable {
+	return &TournamentsTable{
+		tournamentsTable: newTournamentsTableImpl(schemaName, tableName, alias),
+		EXCLUDED:         newTournamentsTableImpl("", "excluded", ""),
+	}
+}
+
+func newTournamentsTableImpl(schemaName, tableName, alias string) tournamentsTable {
+	var (
+		IDColumn           = sqlite.IntegerColumn("id")
+		NameColumn         = sqlite.StringColumn("name")
+		DisciplineIDColumn = sqlite.IntegerColumn("discipline_id")
+		FormatColumn       = sqlite.StringColumn("format")
+		RoundsColumn       = sqlite.IntegerColumn("rounds")
+		CreatedAtColumn    = sqlite.TimestampColumn("created_at")
+		FinishedAtColumn   = sqlite.TimestampColumn("finished_at")
+		allColumns         = sqlite.ColumnList{IDColumn, NameColumn, DisciplineIDColumn, FormatColumn, RoundsColumn, CreatedAtColumn, FinishedAtColumn}
+		mutableColumns     = sqlite.ColumnList{NameColumn, DisciplineIDColumn, FormatColumn, RoundsColumn, CreatedAtColumn, FinishedAtColumn}
+	)
+
+	return tournamentsTable{
+		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
+
+		//Columns
+		ID:           IDColumn,
+		Name:         NameColumn,
+		DisciplineID: DisciplineIDColumn,
+		Format:       FormatColumn,
+		Rounds:       RoundsColumn,
+		CreatedAt:    CreatedAtColumn,
+		FinishedAt:   FinishedAtColumn,
+
+		AllColumns:     allColumns,
+		MutableColumns: mutableColumns,
+	}
+}
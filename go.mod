@@ -17,7 +17,6 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/sirupsen/logrus v1.9.0
 	github.com/stretchr/testify v1.8.1
-	github.com/zelenin/go-glicko2 v0.0.1
 	golang.org/x/text v0.8.0
 )
 
@@ -1,56 +1,94 @@
 package mem
 
 import (
-	"sort"
 	"sync"
 
 	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/normalize"
+
+	"github.com/google/uuid"
 )
 
+// Cache keeps the players with their ratings in a separate partition per discipline.
 type Cache struct {
-	mu      sync.RWMutex
-	valid   bool
+	mu         sync.RWMutex
+	partitions map[int]*partition
+}
+
+type partition struct {
 	players map[string]domain.Player
+	byID    map[uuid.UUID]domain.Player
+	// ordered keeps the players in the order they were given to Update.
+	ordered []domain.Player
 }
 
 func New() *Cache {
 	return &Cache{
-		players: make(map[string]domain.Player),
+		partitions: make(map[int]*partition),
 	}
 }
 
-func (c *Cache) Update(players []domain.Player) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.players = make(map[string]domain.Player)
+// Update replaces the players of the discipline.
+func (c *Cache) Update(discipline int, players []domain.Player) {
+	p := partition{
+		players: make(map[string]domain.Player, len(players)),
+		byID:    make(map[uuid.UUID]domain.Player, len(players)),
+		ordered: make([]domain.Player, len(players)),
+	}
 	for i := range players {
 		name := normalize.Name(players[i].Name)
-		c.players[name] = players[i]
+		p.players[name] = players[i]
+		p.byID[players[i].ID] = players[i]
 	}
-	c.valid = true
+	copy(p.ordered, players)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.partitions[discipline] = &p
 }
 
-func (c *Cache) GetPlayerByName(name string) (domain.Player, bool) {
+func (c *Cache) partition(discipline int) (*partition, bool) {
+	p, ok := c.partitions[discipline]
+	return p, ok
+}
+
+func (c *Cache) GetPlayerByName(discipline int, name string) (domain.Player, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	name = normalize.Name(name)
-	player, ok := c.players[name]
+	p, ok := c.partition(discipline)
+	if !ok {
+		return domain.Player{}, false
+	}
+	player, ok := p.players[normalize.Name(name)]
 	if !ok {
 		return domain.Player{}, false
 	}
 	return player, true
 }
 
-func (c *Cache) GetRatings() []domain.Player {
-	players := make([]domain.Player, 0, len(c.players))
-	for _, player := range c.players {
-		players = append(players, player)
+func (c *Cache) GetPlayer(discipline int, id uuid.UUID) (domain.Player, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, ok := c.partition(discipline)
+	if !ok {
+		return domain.Player{}, false
+	}
+	player, ok := p.byID[id]
+	return player, ok
+}
+
+func (c *Cache) GetRatings(discipline int) []domain.Player {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, ok := c.partition(discipline)
+	if !ok {
+		return nil
 	}
-	sort.SliceStable(players, func(i, j int) bool {
-		return players[i].EloRating > players[j].EloRating
-	})
+	players := make([]domain.Player, len(p.ordered))
+	copy(players, p.ordered)
 	return players
 }
@@ -0,0 +1,223 @@
+// Package chart draws the charts as standalone SVG images for the site and PNG images the bot sends as photos.
+package chart
+
+import (
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+)
+
+const (
+	width   = 640
+	height  = 320
+	padding = 48
+	// gridLines is the number of the horizontal lines of the rating axis.
+	gridLines = 5
+	// bandDeviations is the half width of the confidence band, Glicko-2 reports the same interval.
+	bandDeviations = 2
+)
+
+// colors are the colors of the series in their order, the last one is repeated.
+var colors = []string{"#1f8dd6", "#d32f2f", "#2a9d3c"}
+
+// ContentType is the MIME type of the charts.
+const ContentType = "image/svg+xml"
+
+// Rating draws the rating series sharing the time and the rating axes. The series with
+// a deviation get the confidence band around the line. Nil is returned if there are no points.
+func Rating(title string, series []domain.RatingSeries) []byte {
+	s, ok := seriesScale(series)
+	if !ok {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + strconv.Itoa(width) + `" height="` +
+		strconv.Itoa(height) + `" viewBox="0 0 ` + strconv.Itoa(width) + ` ` + strconv.Itoa(height) + `"` +
+		` font-family="sans-serif" font-size="12">`)
+	b.WriteString(`<rect width="100%" height="100%" fill="#fff"/>`)
+	b.WriteString(`<text x="` + strconv.Itoa(padding) + `" y="20" font-size="14" font-weight="bold">` +
+		html.EscapeString(title) + `</text>`)
+	s.writeAxes(&b)
+	for i, rs := range series {
+		color := colors[colorIndex(i)]
+		if band := s.band(rs.Points); band != "" {
+			b.WriteString(`<polygon fill="` + color + `" fill-opacity="0.15" stroke="none" points="` + band + `"/>`)
+		}
+		points := make([]string, 0, len(rs.Points))
+		for _, point := range rs.Points {
+			points = append(points, s.point(point.Date, point.Value))
+		}
+		b.WriteString(`<polyline fill="none" stroke="` + color + `" stroke-width="2" points="` +
+			strings.Join(points, " ") + `"/>`)
+		b.WriteString(`<text x="` + strconv.Itoa(width-padding) + `" y="` + strconv.Itoa(20+16*i) +
+			`" text-anchor="end" fill="` + color + `">` + html.EscapeString(legend(rs)) + `</text>`)
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// colorIndex returns the index of the color of the series, the last color is repeated.
+func colorIndex(i int) int {
+	if i < len(colors) {
+		return i
+	}
+	return len(colors) - 1
+}
+
+// legend names the series, the confidence band is mentioned if there is one.
+func legend(rs domain.RatingSeries) string {
+	if hasDeviation(rs.Points) {
+		return rs.System.Title + " ±" + strconv.Itoa(bandDeviations) + "σ"
+	}
+	return rs.System.Title
+}
+
+// seriesScale returns the scale fitting all the points of the series with their confidence bands,
+// false if there are no points.
+func seriesScale(series []domain.RatingSeries) (scale, bool) {
+	s := newScale()
+	for _, rs := range series {
+		for _, point := range rs.Points {
+			s.add(point.Date, point.Value-bandDeviations*point.Deviation)
+			s.add(point.Date, point.Value+bandDeviations*point.Deviation)
+		}
+	}
+	if s.from.IsZero() {
+		return scale{}, false
+	}
+	s.round()
+	return s, true
+}
+
+// scale maps the dates and the ratings to the coordinates of the chart.
+type scale struct {
+	from, to  time.Time
+	low, high float64
+}
+
+func newScale() scale {
+	return scale{low: math.Inf(1), high: math.Inf(-1)}
+}
+
+func (s *scale) add(date time.Time, value float64) {
+	if s.from.IsZero() || date.Before(s.from) {
+		s.from = date
+	}
+	if date.After(s.to) {
+		s.to = date
+	}
+	s.low = math.Min(s.low, value)
+	s.high = math.Max(s.high, value)
+}
+
+// round widens the rating axis so the grid lines are on the round values 1, 2 or 5 times a power of ten apart.
+func (s *scale) round() {
+	low, high := s.low, s.high
+	for power := 10.0; ; power *= 10 {
+		for _, step := range []float64{power, 2 * power, 5 * power} {
+			s.low = math.Floor(low/step) * step
+			s.high = s.low + step*(gridLines-1)
+			if s.high >= high {
+				return
+			}
+		}
+	}
+}
+
+func (s scale) x(date time.Time) float64 {
+	span := s.to.Sub(s.from)
+	if span == 0 {
+		return width / 2
+	}
+	return padding + float64(date.Sub(s.from))/float64(span)*(width-2*padding)
+}
+
+func (s scale) y(value float64) float64 {
+	return height - padding - (value-s.low)/(s.high-s.low)*(height-2*padding)
+}
+
+func (s scale) point(date time.Time, value float64) string {
+	return coord(s.x(date)) + "," + coord(s.y(value))
+}
+
+// gridValue returns the rating of the grid line i, 0 is the bottom one.
+func (s scale) gridValue(i int) float64 {
+	return s.low + (s.high-s.low)*float64(i)/(gridLines-1)
+}
+
+func (s scale) writeAxes(b *strings.Builder) {
+	for i := 0; i < gridLines; i++ {
+		value := s.gridValue(i)
+		y := coord(s.y(value))
+		b.WriteString(`<line stroke="#ccc" stroke-dasharray="4" x1="` + strconv.Itoa(padding) + `" x2="` + strconv.Itoa(width-padding) +
+			`" y1="` + y + `" y2="` + y + `"/>`)
+		b.WriteString(`<text x="` + strconv.Itoa(padding-6) + `" y="` + coord(s.y(value)+4) + `" text-anchor="end">` +
+			strconv.FormatFloat(value, 'f', 0, 64) + `</text>`)
+	}
+	dateY := strconv.Itoa(height - padding/2)
+	b.WriteString(`<text x="` + strconv.Itoa(padding) + `" y="` + dateY + `">` + formatDate(s.from) + `</text>`)
+	b.WriteString(`<text x="` + strconv.Itoa(width-padding) + `" y="` + dateY + `" text-anchor="end">` +
+		formatDate(s.to) + `</text>`)
+}
+
+// band returns the points of the polygon of the confidence band, empty if the series has no deviation.
+func (s scale) band(points []domain.RatingPoint) string {
+	outline := s.bandOutline(points)
+	coords := make([]string, 0, len(outline))
+	for _, v := range outline {
+		coords = append(coords, coord(v.x)+","+coord(v.y))
+	}
+	return strings.Join(coords, " ")
+}
+
+// vertex is a point of the chart in its coordinates.
+type vertex struct {
+	x, y float64
+}
+
+// line returns the vertices of the rating line of the series.
+func (s scale) line(points []domain.RatingPoint) []vertex {
+	vertices := make([]vertex, 0, len(points))
+	for _, point := range points {
+		vertices = append(vertices, vertex{s.x(point.Date), s.y(point.Value)})
+	}
+	return vertices
+}
+
+// bandOutline returns the polygon of the confidence band from the upper bound forward
+// to the lower bound backward, nil if the series has no deviation.
+func (s scale) bandOutline(points []domain.RatingPoint) []vertex {
+	if !hasDeviation(points) {
+		return nil
+	}
+	outline := make([]vertex, 0, 2*len(points))
+	for _, point := range points {
+		outline = append(outline, vertex{s.x(point.Date), s.y(point.Value + bandDeviations*point.Deviation)})
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		outline = append(outline, vertex{s.x(points[i].Date), s.y(points[i].Value - bandDeviations*points[i].Deviation)})
+	}
+	return outline
+}
+
+func hasDeviation(points []domain.RatingPoint) bool {
+	for _, point := range points {
+		if point.Deviation != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func coord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02.01.2006")
+}
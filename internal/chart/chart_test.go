@@ -0,0 +1,141 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+)
+
+func TestRating(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	elo := domain.RatingSeries{
+		System: domain.RatingSystem{Name: "elo", Title: "Эло"},
+		Points: []domain.RatingPoint{
+			{Date: start, Value: 1000},
+			{Date: start.Add(time.Hour), Value: 1020},
+		},
+	}
+	glicko := domain.RatingSeries{
+		System: domain.RatingSystem{Name: "glicko2", Title: "Глико-2"},
+		Points: []domain.RatingPoint{
+			{Date: start, Value: 1500, Deviation: 350},
+			{Date: start.Add(time.Hour), Value: 1660, Deviation: 290},
+		},
+	}
+	tests := []struct {
+		name   string
+		series []domain.RatingSeries
+		want   []string
+		skip   []string
+	}{
+		{
+			name:   "no matches",
+			series: []domain.RatingSeries{{System: elo.System}},
+		},
+		{
+			name:   "elo without the band",
+			series: []domain.RatingSeries{elo},
+			want:   []string{"<polyline", ">Эло</text>", ">01.01.2024</text>"},
+			skip:   []string{"<polygon"},
+		},
+		{
+			name:   "glicko-2 with the band",
+			series: []domain.RatingSeries{elo, glicko},
+			want:   []string{"<polygon", ">Глико-2 ±2σ</text>", "&lt;b&gt;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Rating("<b>", tt.series))
+			if tt.want == nil && got != "" {
+				t.Fatalf("Rating() = %s, want nothing", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Rating() = %s, want %s", got, want)
+				}
+			}
+			for _, skip := range tt.skip {
+				if strings.Contains(got, skip) {
+					t.Errorf("Rating() = %s, must not have %s", got, skip)
+				}
+			}
+		})
+	}
+}
+
+func Test_scale_round(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high float64
+		wantLow   float64
+		wantHigh  float64
+	}{
+		{name: "a single value", low: 1000, high: 1000, wantLow: 1000, wantHigh: 1040},
+		{name: "inside the steps", low: 995, high: 1035, wantLow: 980, wantHigh: 1060},
+		{name: "the wide band", low: 800, high: 2200, wantLow: 500, wantHigh: 2500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := scale{low: tt.low, high: tt.high}
+			s.round()
+			if s.low != tt.wantLow || s.high != tt.wantHigh {
+				t.Errorf("round() = %v..%v, want %v..%v", s.low, s.high, tt.wantLow, tt.wantHigh)
+			}
+		})
+	}
+}
+
+func TestRatingPNG(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	elo := domain.RatingSeries{
+		System: domain.RatingSystem{Name: "elo", Title: "Эло"},
+		Points: []domain.RatingPoint{
+			{Date: start, Value: 1000},
+			{Date: start.Add(time.Hour), Value: 1020},
+		},
+	}
+	if got := RatingPNG([]domain.RatingSeries{{System: elo.System}}); got != nil {
+		t.Fatalf("RatingPNG() without points = %d bytes, want nothing", len(got))
+	}
+	img, err := png.Decode(bytes.NewReader(RatingPNG([]domain.RatingSeries{elo})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != width || size.Y != height {
+		t.Errorf("size = %v, want %dx%d", size, width, height)
+	}
+	s, _ := seriesScale([]domain.RatingSeries{elo})
+	// the middle of the line between the points
+	x, y := int((s.x(start)+s.x(start.Add(time.Hour)))/2), int(s.y(1010))
+	if r, g, b, _ := img.At(x, y).RGBA(); r>>8 != 0x1f || g>>8 != 0x8d || b>>8 != 0xd6 {
+		t.Errorf("color at %d,%d = %x%x%x, want the color of the first series", x, y, r>>8, g>>8, b>>8)
+	}
+}
+
+func TestCaption(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := []domain.RatingSeries{
+		{
+			System: domain.RatingSystem{Name: "elo", Title: "Эло"},
+			Points: []domain.RatingPoint{{Date: start, Value: 1000}, {Date: start.AddDate(0, 0, 1), Value: 1020}},
+		},
+		{
+			System: domain.RatingSystem{Name: "glicko2", Title: "Глико-2"},
+			Points: []domain.RatingPoint{{Date: start, Value: 1500, Deviation: 350}, {Date: start.AddDate(0, 0, 1), Value: 1660, Deviation: 290}},
+		},
+	}
+	got := Caption("title", series)
+	for _, want := range []string{"title\n", "01.01.2024 — 02.01.2024", "🔵 Эло: 1000 → 1020", "🔴 Глико-2 ±2σ: 1500 → 1660"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Caption() = %s, want %s", got, want)
+		}
+	}
+	if got := Caption("title", nil); got != "title" {
+		t.Errorf("Caption() without points = %s, want the title", got)
+	}
+}
@@ -0,0 +1,171 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goserg/ratingserver/internal/domain"
+)
+
+// markers are the emoji of the colors of the series in their order, Caption names the series by them.
+var markers = []string{"🔵", "🔴", "🟢"}
+
+const (
+	// lineWidth is the width of the rating lines in pixels.
+	lineWidth = 2
+	// bandOpacity is the opacity of the confidence band over the white background.
+	bandOpacity = 0.15
+	// dash is the length of the dashes and the gaps of the grid lines in pixels.
+	dash = 4
+)
+
+var gridColor = color.RGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+
+// RatingPNG draws the rating series like Rating as a PNG image, the messengers show only raster images
+// inline. There is no font to draw the texts with, Caption describes the image instead.
+// Nil is returned if there are no points.
+func RatingPNG(series []domain.RatingSeries) []byte {
+	s, ok := seriesScale(series)
+	if !ok {
+		return nil
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for i := 0; i < gridLines; i++ {
+		y := int(math.Round(s.y(s.gridValue(i))))
+		for x := padding; x < width-padding; x++ {
+			if (x-padding)/dash%2 == 0 {
+				img.SetRGBA(x, y, gridColor)
+			}
+		}
+	}
+	for i, rs := range series {
+		c := parseColor(colors[colorIndex(i)])
+		fillPolygon(img, s.bandOutline(rs.Points), c, bandOpacity)
+		line := s.line(rs.Points)
+		for j := range line {
+			from := line[j]
+			if j > 0 {
+				from = line[j-1]
+			}
+			drawLine(img, from, line[j], c)
+		}
+	}
+	var buf bytes.Buffer
+	// encoding to the memory fails only for an invalid image
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// Caption describes the image of RatingPNG: the title, the dates, the ratings of the grid lines
+// and every series by the marker of its color with the first and the last ratings.
+func Caption(title string, series []domain.RatingSeries) string {
+	s, ok := seriesScale(series)
+	if !ok {
+		return title
+	}
+	var b strings.Builder
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(formatDate(s.from))
+	b.WriteString(" — ")
+	b.WriteString(formatDate(s.to))
+	b.WriteString("\nЛинии сетки снизу вверх: ")
+	for i := 0; i < gridLines; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.FormatFloat(s.gridValue(i), 'f', 0, 64))
+	}
+	for i, rs := range series {
+		if len(rs.Points) == 0 {
+			continue
+		}
+		first, last := rs.Points[0], rs.Points[len(rs.Points)-1]
+		b.WriteString("\n")
+		b.WriteString(markers[colorIndex(i)])
+		b.WriteString(" ")
+		b.WriteString(legend(rs))
+		b.WriteString(": ")
+		b.WriteString(strconv.FormatFloat(first.Value, 'f', 0, 64))
+		b.WriteString(" → ")
+		b.WriteString(strconv.FormatFloat(last.Value, 'f', 0, 64))
+	}
+	return b.String()
+}
+
+// parseColor parses the #rrggbb colors.
+func parseColor(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// drawLine draws the segment lineWidth pixels wide by the pixels within half of the width from it.
+func drawLine(img *image.RGBA, from, to vertex, c color.RGBA) {
+	const r = lineWidth / 2.0
+	minX, maxX := math.Min(from.x, to.x)-r, math.Max(from.x, to.x)+r
+	minY, maxY := math.Min(from.y, to.y)-r, math.Max(from.y, to.y)+r
+	for y := int(math.Floor(minY)); y <= int(math.Ceil(maxY)); y++ {
+		for x := int(math.Floor(minX)); x <= int(math.Ceil(maxX)); x++ {
+			if distance(vertex{float64(x) + 0.5, float64(y) + 0.5}, from, to) <= r {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// distance returns the distance from the point p to the segment from a to b.
+func distance(p, a, b vertex) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((p.x-a.x)*dx+(p.y-a.y)*dy)/length))
+	}
+	return math.Hypot(p.x-a.x-t*dx, p.y-a.y-t*dy)
+}
+
+// fillPolygon blends the color with the given opacity into the pixels inside the polygon,
+// the centers of the pixels are tested by the even-odd rule.
+func fillPolygon(img *image.RGBA, polygon []vertex, c color.RGBA, opacity float64) {
+	if len(polygon) < 3 {
+		return
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		cy := float64(y) + 0.5
+		var crossings []float64
+		for i := range polygon {
+			a, b := polygon[i], polygon[(i+1)%len(polygon)]
+			if (a.y <= cy) != (b.y <= cy) {
+				crossings = append(crossings, a.x+(cy-a.y)/(b.y-a.y)*(b.x-a.x))
+			}
+		}
+		sort.Float64s(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			for x := int(math.Ceil(crossings[i] - 0.5)); float64(x)+0.5 <= crossings[i+1]; x++ {
+				if x < bounds.Min.X || x >= bounds.Max.X {
+					continue
+				}
+				dst := img.RGBAAt(x, y)
+				img.SetRGBA(x, y, color.RGBA{
+					R: blend(dst.R, c.R, opacity),
+					G: blend(dst.G, c.G, opacity),
+					B: blend(dst.B, c.B, opacity),
+					A: 0xff,
+				})
+			}
+		}
+	}
+}
+
+func blend(dst, src uint8, opacity float64) uint8 {
+	return uint8(math.Round(float64(dst)*(1-opacity) + float64(src)*opacity))
+}
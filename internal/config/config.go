@@ -31,8 +31,72 @@ type Server struct {
 	SqliteFile   string             `toml:"sqlite_file"`
 	Host         string             `toml:"host"`
 	Port         int                `toml:"port"`
-	TLS          bool               `toml"tls"`
+	TLS          bool               `toml:"tls"`
 	Auth         authservice.Config `toml:"auth"`
+	Rating       Rating             `toml:"rating"`
+}
+
+type Rating struct {
+	// Systems lists enabled rating systems, the first one is used for ranking.
+	Systems []string `toml:"systems"`
+	// InactiveAfterDays hides players without matches from leaderboards, 0 disables.
+	InactiveAfterDays int `toml:"inactive_after_days"`
+	// ProvisionalGames is the number of games a player needs to be ranked, 0 disables.
+	ProvisionalGames int `toml:"provisional_games"`
+	// ProvisionalDeviation is the highest Glicko-2 deviation of a ranked player, 0 disables.
+	ProvisionalDeviation float64 `toml:"provisional_deviation"`
+	Elo                  Elo     `toml:"elo"`
+	Glicko2              Glicko2 `toml:"glicko2"`
+	Ladder               Ladder  `toml:"ladder"`
+}
+
+// Elo parameters, zero values are replaced with the defaults.
+type Elo struct {
+	InitialRating int     `toml:"initial_rating"`
+	Scale         float64 `toml:"scale"`
+	// KFactor rules are checked in order, the first matching one is used.
+	KFactor []EloKRule `toml:"k_factor"`
+	// Decay lowers the rating of inactive players, disabled if DecayPerWeek is 0.
+	DecayAfterDays int `toml:"decay_after_days"`
+	DecayPerWeek   int `toml:"decay_per_week"`
+	// DecayFloor is the rating the decay stops at.
+	DecayFloor int `toml:"decay_floor"`
+	// MarginOfVictory scales K by the score difference of the matches with a score.
+	MarginOfVictory bool `toml:"margin_of_victory"`
+}
+
+// EloKRule sets K for the players matching all the given conditions.
+// Games are counted before the match.
+type EloKRule struct {
+	MinGames  *int `toml:"min_games"`
+	MaxGames  *int `toml:"max_games"`
+	MinRating *int `toml:"min_rating"`
+	MaxRating *int `toml:"max_rating"`
+	K         int  `toml:"k"`
+	Order     int  `toml:"order"`
+}
+
+// Glicko2 parameters, zero values are replaced with the defaults.
+type Glicko2 struct {
+	InitialRating     float64 `toml:"initial_rating"`
+	InitialDeviation  float64 `toml:"initial_deviation"`
+	InitialVolatility float64 `toml:"initial_volatility"`
+	Tau               float64 `toml:"tau"`
+	// Period is "day", "week", "month", "event" or a duration like "72h".
+	Period string `toml:"period"`
+	// EventGap is the pause between matches that ends an event.
+	EventGap string `toml:"event_gap"`
+	// Timezone the calendar periods are aligned in, the local one by default.
+	Timezone string `toml:"timezone"`
+}
+
+// Ladder parameters, zero values are replaced with the defaults.
+type Ladder struct {
+	Enabled bool `toml:"enabled"`
+	// Reach is how many positions above a player can challenge.
+	Reach int `toml:"reach"`
+	// DeadlineDays is the time the challenged player has to accept or play the challenge.
+	DeadlineDays int `toml:"deadline_days"`
 }
 
 type Config struct {
@@ -94,9 +158,7 @@ func serverConfig(path string) (Server, error) {
 		if err != nil {
 			return Server{}, err
 		}
-		sort.SliceStable(serverCfg.Auth.Rules, func(i, j int) bool {
-			return serverCfg.Auth.Rules[i].Order < serverCfg.Auth.Rules[j].Order
-		})
+		sortRules(&serverCfg)
 		return serverCfg, nil
 	}
 	err := createConfigFileIfNotExists(serverCfgName)
@@ -108,10 +170,17 @@ func serverConfig(path string) (Server, error) {
 	if err != nil {
 		return Server{}, err
 	}
+	sortRules(&serverCfg)
+	return serverCfg, nil
+}
+
+func sortRules(serverCfg *Server) {
 	sort.SliceStable(serverCfg.Auth.Rules, func(i, j int) bool {
 		return serverCfg.Auth.Rules[i].Order < serverCfg.Auth.Rules[j].Order
 	})
-	return serverCfg, nil
+	sort.SliceStable(serverCfg.Rating.Elo.KFactor, func(i, j int) bool {
+		return serverCfg.Rating.Elo.KFactor[i].Order < serverCfg.Rating.Elo.KFactor[j].Order
+	})
 }
 
 func tgBotConfig(path string) (TgBot, error) {
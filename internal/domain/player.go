@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,24 +14,257 @@ type Player struct {
 	Name         string
 	RegisteredAt time.Time
 
-	RatingRank    int
-	EloRating     int
-	GamesPlayed   int
-	RatingChange  int
-	Glicko2Rating Glicko2Rating
+	// RatingRank is the position by the primary rating among active established players,
+	// 0 for inactive and provisional ones.
+	RatingRank  int
+	GamesPlayed int
+	// LastPlayedAt is the date of the last match, zero if there were none.
+	LastPlayedAt time.Time
+	// Inactive players have not played for a long time or are deactivated,
+	// they are hidden from leaderboards.
+	Inactive bool
+	// Deactivated players are hidden from leaderboards until activated again, their matches are kept.
+	Deactivated bool
+	// Provisional players have played too few games or have too uncertain a rating to be ranked,
+	// leaderboards list them after the established players.
+	Provisional bool
+	// Ratings holds one rating per configured rating system, in configured order.
+	// The first one is the primary rating used for ranking.
+	Ratings []Rating
 }
 
-type Glicko2Rating struct {
-	Rating          float64
-	RatingDeviation float64
-	Sigma           float64
-	Interval        Glicko2Interval
+// PrimaryRating returns the rating of the first configured rating system.
+func (p Player) PrimaryRating() Rating {
+	if len(p.Ratings) == 0 {
+		return Rating{}
+	}
+	return p.Ratings[0]
+}
+
+// Rating returns the player's rating in the given rating system.
+func (p Player) Rating(system string) (Rating, bool) {
+	for i := range p.Ratings {
+		if p.Ratings[i].System == system {
+			return p.Ratings[i], true
+		}
+	}
+	return Rating{}, false
+}
+
+type Rating struct {
+	System string
+	Value  float64
+	// Deviation is zero for the systems without uncertainty (like Elo).
+	Deviation  float64
+	Volatility float64
+	// Interval is the range the true rating most likely lies in.
+	Interval Interval
+	// Precision is the number of decimals shown to users.
+	Precision int
+	// Change is the difference caused by the last applied match.
+	Change float64
+}
+
+func (r Rating) String() string {
+	value := r.format(r.Value)
+	if r.Deviation == 0 {
+		return value
+	}
+	return value + " (" + r.format(r.Interval.Min) + "–" + r.format(r.Interval.Max) + ")"
+}
+
+// ChangeString returns the rating change rounded to the rating precision.
+func (r Rating) ChangeString() string {
+	return r.format(r.Change)
+}
+
+func (r Rating) format(v float64) string {
+	return strconv.FormatFloat(v, 'f', r.Precision, 64)
 }
 
-type Glicko2Interval struct {
+type Interval struct {
 	Max, Min float64
 }
 
+// DefaultDisciplineID is the discipline the matches recorded before
+// disciplines were introduced belong to.
+const DefaultDisciplineID = 1
+
+// Discipline is a game with its own ratings, the players are shared between disciplines.
+type Discipline struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+}
+
+// Season is a date range with its own ratings, the seasons are shared between disciplines.
+// The ratings of a season start from the all-time ratings at its start moved toward
+// the initial ones, Carryover is the share of the difference kept: 0 is a full reset.
+type Season struct {
+	ID    int
+	Name  string
+	Start time.Time
+	// End is the first moment after the season.
+	End       time.Time
+	Carryover float64
+	// ArchivedAt is set when the final standings of the finished season are saved.
+	ArchivedAt time.Time
+}
+
+func (s Season) Contains(date time.Time) bool {
+	return !date.Before(s.Start) && date.Before(s.End)
+}
+
+func (s Season) Finished(now time.Time) bool {
+	return !now.Before(s.End)
+}
+
+// Last returns the last moment of the season.
+func (s Season) Last() time.Time {
+	return s.End.Add(-time.Nanosecond)
+}
+
+// CarryoverString returns the carryover in percent.
+func (s Season) CarryoverString() string {
+	return strconv.FormatFloat(s.Carryover*100, 'f', 0, 64) + "%"
+}
+
+func (s Season) Archived() bool {
+	return !s.ArchivedAt.IsZero()
+}
+
+// Overlaps reports whether the seasons have common dates.
+func (s Season) Overlaps(other Season) bool {
+	return s.Start.Before(other.End) && other.Start.Before(s.End)
+}
+
+func (s Season) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("у сезона должно быть название")
+	}
+	if !s.Start.Before(s.End) {
+		return errors.New("сезон должен заканчиваться после начала")
+	}
+	if s.Carryover < 0 || s.Carryover > 1 {
+		return errors.New("доля сохраняемого рейтинга должна быть от 0 до 1")
+	}
+	return nil
+}
+
+// SeasonStanding is the player's place in a discipline at the end of a season.
+type SeasonStanding struct {
+	SeasonID     int
+	DisciplineID int
+	Player       Player
+	Place        int
+	GamesPlayed  int
+	Ratings      []Rating
+}
+
+// RatingSystem describes a configured rating system.
+type RatingSystem struct {
+	Name  string
+	Title string
+}
+
+// LeaderboardEntry is a player's place on a leaderboard ranking by one rating system.
+type LeaderboardEntry struct {
+	Player Player
+	// Rank is the position among established players, 0 for provisional ones.
+	Rank int
+	// Score is the rating the leaderboard is ordered by.
+	Score Rating
+}
+
+// RatingHistoryEntry is a player's rating in one rating system before and after a match.
+// MatchID is zero for free-for-all matches, FFAMatchID is zero for the others.
+type RatingHistoryEntry struct {
+	MatchID         int
+	FFAMatchID      int
+	DisciplineID    int
+	PlayerID        uuid.UUID
+	System          string
+	RatingBefore    float64
+	RatingAfter     float64
+	DeviationBefore float64
+	DeviationAfter  float64
+	Date            time.Time
+}
+
+// RatingHistoryFilter selects rating history entries, empty fields match everything.
+type RatingHistoryFilter struct {
+	PlayerID     uuid.UUID
+	DisciplineID int
+	System       string
+	// To is the inclusive upper bound of the match date.
+	To time.Time
+}
+
+// Prediction is the expected outcome of a match between two players.
+type Prediction struct {
+	PlayerA Player
+	PlayerB Player
+	// Systems holds one prediction per configured rating system, in configured order.
+	Systems []SystemPrediction
+}
+
+// SystemPrediction is the expected outcome of a match in one rating system.
+type SystemPrediction struct {
+	System RatingSystem
+	WinA   Outcome
+	Draw   Outcome
+	WinB   Outcome
+}
+
+// Outcome is a possible match result with its probability and the ratings of the players after it.
+type Outcome struct {
+	Probability float64
+	A           Rating
+	B           Rating
+}
+
+// PercentString returns the probability in percent.
+func (o Outcome) PercentString() string {
+	return strconv.FormatFloat(o.Probability*100, 'f', 0, 64) + "%"
+}
+
+// Matchmaking is the proposed games between the present players, the best ones first.
+// With an odd number of players one of them rests.
+type Matchmaking struct {
+	Pairings []Pairing
+	Resting  *Player
+}
+
+// Pairing is a proposed game with its prediction in the primary rating system.
+type Pairing struct {
+	Prediction Prediction
+	// Quality is 1 for the equal chances to win and 0 for a certain result.
+	Quality float64
+	// Since is the number of the games the players have played since they met, -1 if they have never met.
+	Since int
+}
+
+// Outcomes returns the prediction of the primary rating system.
+func (p Pairing) Outcomes() SystemPrediction {
+	if len(p.Prediction.Systems) == 0 {
+		return SystemPrediction{}
+	}
+	return p.Prediction.Systems[0]
+}
+
+// QualityString returns the quality in percent.
+func (p Pairing) QualityString() string {
+	return strconv.FormatFloat(p.Quality*100, 'f', 0, 64) + "%"
+}
+
+// Rematch reports whether the players have met recently.
+func (p Pairing) Rematch() bool {
+	return p.Since >= 0 && p.Since < RecentRematch
+}
+
+// RecentRematch is the number of the games since the last meeting the players are considered to have met recently.
+const RecentRematch = 3
+
 type PlayerStats struct {
 	Player Player
 	Wins   int
@@ -37,22 +272,871 @@ type PlayerStats struct {
 	Loses  int
 }
 
+// The actions recorded in the audit trail of the matches.
+const (
+	MatchEdited   = "edit"
+	MatchVoided   = "void"
+	MatchRestored = "restore"
+	MatchDeleted  = "delete"
+)
+
+// MatchChange is a change of a recorded match: who changed it, when and how.
+// Before and After are the summaries of the match, After is empty for a deleted match.
+type MatchChange struct {
+	ID      int
+	MatchID int
+	Action  string
+	Author  string
+	Before  string
+	After   string
+	Date    time.Time
+}
+
+var matchActionTitles = map[string]string{
+	MatchEdited:   "изменён",
+	MatchVoided:   "аннулирован",
+	MatchRestored: "восстановлен",
+	MatchDeleted:  "удалён",
+}
+
+// ActionTitle returns the action shown to users.
+func (c MatchChange) ActionTitle() string {
+	if title, ok := matchActionTitles[c.Action]; ok {
+		return title
+	}
+	return c.Action
+}
+
 type PlayerCardData struct {
 	Player  Player
 	Results map[uuid.UUID]PlayerStats
+	Records PlayerRecords
 }
 
+// Match is played by side A and side B. PlayerA and PlayerB lead the sides,
+// in team matches the other members are in TeammatesA and TeammatesB.
+// Winner is the leader of the winning side, empty for a draw.
 type Match struct {
-	ID      int
-	PlayerA Player
-	PlayerB Player
-	Winner  Player
-	Date    time.Time
+	ID           int
+	DisciplineID int
+	PlayerA      Player
+	PlayerB      Player
+	TeammatesA   []Player
+	TeammatesB   []Player
+	Winner       Player
+	Date         time.Time
+	// Score is nil if the match was recorded without it.
+	Score *Score
+	// Voided matches are listed, but do not change the ratings.
+	Voided bool
+}
+
+// Summary describes the match for users, e.g. "вася + петя - коля, победа вася, 3:1, 02.01.2023 15:04".
+func (m Match) Summary() string {
+	var buf strings.Builder
+	buf.WriteString(playerNames(m.SideA()))
+	buf.WriteString(" - ")
+	buf.WriteString(playerNames(m.SideB()))
+	if m.Winner.ID == uuid.Nil {
+		buf.WriteString(", ничья")
+	} else {
+		buf.WriteString(", победа ")
+		buf.WriteString(m.Winner.Name)
+	}
+	if m.Score != nil {
+		buf.WriteString(", ")
+		buf.WriteString(m.Score.String())
+	}
+	buf.WriteString(", ")
+	buf.WriteString(m.Date.Format("02.01.2006 15:04"))
+	if m.Voided {
+		buf.WriteString(", аннулирован")
+	}
+	return buf.String()
+}
+
+func playerNames(players []Player) string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return strings.Join(names, " + ")
+}
+
+// SideA returns all the players of side A, the leader first.
+func (m Match) SideA() []Player {
+	return append([]Player{m.PlayerA}, m.TeammatesA...)
+}
+
+// SideB returns all the players of side B, the leader first.
+func (m Match) SideB() []Player {
+	return append([]Player{m.PlayerB}, m.TeammatesB...)
+}
+
+// IsTeamMatch reports whether any side has more than one player.
+func (m Match) IsTeamMatch() bool {
+	return len(m.TeammatesA) > 0 || len(m.TeammatesB) > 0
+}
+
+// Played reports whether the player played in the match on any side.
+func (m Match) Played(id uuid.UUID) bool {
+	return containsPlayer(m.SideA(), id) || containsPlayer(m.SideB(), id)
+}
+
+func containsPlayer(players []Player, id uuid.UUID) bool {
+	for _, player := range players {
+		if player.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Opponents returns the players of the side the player did not play for.
+func (m Match) Opponents(id uuid.UUID) []Player {
+	for _, player := range m.SideA() {
+		if player.ID == id {
+			return m.SideB()
+		}
+	}
+	return m.SideA()
+}
+
+// Won reports whether the side of the player won the match.
+func (m Match) Won(id uuid.UUID) bool {
+	if m.Winner.ID == uuid.Nil {
+		return false
+	}
+	for _, player := range m.Opponents(id) {
+		if player.ID == m.Winner.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// Score is the points of player A and player B.
+type Score struct {
+	A, B int
+}
+
+func (s Score) String() string {
+	return strconv.Itoa(s.A) + ":" + strconv.Itoa(s.B)
+}
+
+// ParseScore parses the score in the "A:B" format.
+func ParseScore(s string) (Score, error) {
+	a, b, ok := strings.Cut(s, ":")
+	if !ok {
+		return Score{}, errors.New("счёт должен быть в формате 11:7")
+	}
+	scoreA, errA := strconv.Atoi(strings.TrimSpace(a))
+	scoreB, errB := strconv.Atoi(strings.TrimSpace(b))
+	if errA != nil || errB != nil {
+		return Score{}, errors.New("счёт должен быть в формате 11:7")
+	}
+	return Score{A: scoreA, B: scoreB}, nil
+}
+
+// Margin returns the absolute points difference.
+func (s Score) Margin() int {
+	if s.A > s.B {
+		return s.A - s.B
+	}
+	return s.B - s.A
 }
 
 func (m Match) Validate() error {
 	if m.Winner.ID != uuid.Nil && m.Winner.ID != m.PlayerA.ID && m.Winner.ID != m.PlayerB.ID {
 		return errors.New("winner must be empty or one of the players")
 	}
+	seen := make(map[uuid.UUID]bool)
+	for _, player := range append(m.SideA(), m.SideB()...) {
+		if seen[player.ID] {
+			return errors.New("player " + player.Name + " must not play twice in a match")
+		}
+		seen[player.ID] = true
+	}
+	if m.Score == nil {
+		return nil
+	}
+	if m.Score.A < 0 || m.Score.B < 0 {
+		return errors.New("score must not be negative")
+	}
+	var ok bool
+	switch m.Winner.ID {
+	case uuid.Nil:
+		ok = m.Score.A == m.Score.B
+	case m.PlayerA.ID:
+		ok = m.Score.A > m.Score.B
+	default:
+		ok = m.Score.B > m.Score.A
+	}
+	if !ok {
+		return errors.New("score does not match the winner")
+	}
+	return nil
+}
+
+// MinFFAPlayers is the smallest number of players of a free-for-all match,
+// a match of two players is recorded as a regular one.
+const MinFFAPlayers = 3
+
+// FFAMatch is a free-for-all match of several players
+// that ends with a finishing order instead of a winner.
+type FFAMatch struct {
+	ID           int
+	DisciplineID int
+	Date         time.Time
+	// Placements are ordered by place, players with equal places are tied.
+	Placements []Placement
+}
+
+// Placement is the finishing place of a player, the first place is 1.
+type Placement struct {
+	Player Player
+	Place  int
+}
+
+// Players returns the players in the finishing order.
+func (m FFAMatch) Players() []Player {
+	players := make([]Player, 0, len(m.Placements))
+	for _, placement := range m.Placements {
+		players = append(players, placement.Player)
+	}
+	return players
+}
+
+// Played reports whether the player took a place in the match.
+func (m FFAMatch) Played(id uuid.UUID) bool {
+	return containsPlayer(m.Players(), id)
+}
+
+// Pairwise converts the finishing order to a match for every pair of players:
+// the one placed higher wins, players with equal places draw.
+func (m FFAMatch) Pairwise() []Match {
+	matches := make([]Match, 0, len(m.Placements)*(len(m.Placements)-1)/2)
+	for i := range m.Placements {
+		for j := i + 1; j < len(m.Placements); j++ {
+			match := Match{
+				ID:      m.ID,
+				PlayerA: m.Placements[i].Player,
+				PlayerB: m.Placements[j].Player,
+				Date:    m.Date,
+			}
+			if m.Placements[i].Place < m.Placements[j].Place {
+				match.Winner = match.PlayerA
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+func (m FFAMatch) Validate() error {
+	if len(m.Placements) < MinFFAPlayers {
+		return errors.New("в игре на несколько игроков должно участвовать не меньше " + strconv.Itoa(MinFFAPlayers) + " игроков")
+	}
+	seen := make(map[uuid.UUID]bool)
+	for i, placement := range m.Placements {
+		if placement.Place < 1 {
+			return errors.New("place must be positive")
+		}
+		if i > 0 && placement.Place < m.Placements[i-1].Place {
+			return errors.New("placements must be ordered by place")
+		}
+		if seen[placement.Player.ID] {
+			return errors.New("player " + placement.Player.Name + " must not play twice in a match")
+		}
+		seen[placement.Player.ID] = true
+	}
+	return nil
+}
+
+// Place returns the place of the player in the match, 0 if they did not play.
+func (m FFAMatch) Place(id uuid.UUID) int {
+	for _, placement := range m.Placements {
+		if placement.Player.ID == id {
+			return placement.Place
+		}
+	}
+	return 0
+}
+
+// HeadToHead compares two players by the matches they played against each other.
+type HeadToHead struct {
+	PlayerA Player
+	PlayerB Player
+	// Matches are the matches the players played on the opposite sides, the latest first.
+	Matches []Match
+	// FFAMatches are the free-for-all matches both players took a place in, the latest first.
+	FFAMatches []FFAMatch
+	// WinsA, Draws and WinsB count the matches of both kinds, voided matches are skipped.
+	// In a free-for-all match the player placed higher wins.
+	WinsA int
+	Draws int
+	WinsB int
+	// Prediction is the outcome of a match between the players played now.
+	Prediction Prediction
+	// TrendA and TrendB are the primary ratings of the players after every match they played.
+	TrendA []RatingPoint
+	TrendB []RatingPoint
+}
+
+// Total returns the number of the counted matches.
+func (h HeadToHead) Total() int {
+	return h.WinsA + h.Draws + h.WinsB
+}
+
+// RatingPoint is a rating at the date.
+type RatingPoint struct {
+	Date  time.Time
+	Value float64
+	// Deviation is zero for the systems without uncertainty (like Elo).
+	Deviation float64
+}
+
+// RatingSeries is the rating of a player in one system before the first match and after every match.
+type RatingSeries struct {
+	System RatingSystem
+	Points []RatingPoint
+}
+
+// AchievementKind identifies an achievement, a player unlocks every kind once per discipline.
+type AchievementKind string
+
+const (
+	AchievementFirstWin    AchievementKind = "first_win"
+	AchievementWinStreak5  AchievementKind = "win_streak_5"
+	AchievementWinStreak10 AchievementKind = "win_streak_10"
+	AchievementUpset       AchievementKind = "upset"
+	AchievementBeatLeader  AchievementKind = "beat_leader"
+	AchievementGames100    AchievementKind = "games_100"
+)
+
+// UpsetProbability is the highest win probability by the primary rating system a win is an upset with.
+const UpsetProbability = 0.25
+
+// AchievementDefinition describes an achievement for users.
+type AchievementDefinition struct {
+	Kind        AchievementKind
+	Title       string
+	Description string
+}
+
+// AchievementDefinitions lists all the achievements in the order they are shown.
+var AchievementDefinitions = []AchievementDefinition{
+	{Kind: AchievementFirstWin, Title: "Первая победа", Description: "Выиграть матч"},
+	{Kind: AchievementWinStreak5, Title: "Серия", Description: "Выиграть 5 матчей подряд"},
+	{Kind: AchievementWinStreak10, Title: "Непобедимый", Description: "Выиграть 10 матчей подряд"},
+	{Kind: AchievementUpset, Title: "Сенсация", Description: "Выиграть матч, в котором шансы на победу были не больше 25%"},
+	{Kind: AchievementBeatLeader, Title: "Царь горы", Description: "Победить игрока, занимающего первое место в рейтинге"},
+	{Kind: AchievementGames100, Title: "Ветеран", Description: "Сыграть 100 матчей"},
+}
+
+// Definition returns the description of the achievement kind.
+func (k AchievementKind) Definition() AchievementDefinition {
+	for _, definition := range AchievementDefinitions {
+		if definition.Kind == k {
+			return definition
+		}
+	}
+	return AchievementDefinition{Kind: k, Title: string(k)}
+}
+
+// Achievement is unlocked by the player in the match at its date.
+type Achievement struct {
+	Kind         AchievementKind
+	Player       Player
+	DisciplineID int
+	Date         time.Time
+	// MatchID or FFAMatchID is the match the achievement was unlocked in.
+	MatchID    int
+	FFAMatchID int
+}
+
+// Title returns the title of the achievement.
+func (a Achievement) Title() string {
+	return a.Kind.Definition().Title
+}
+
+// Description returns what the achievement is awarded for.
+func (a Achievement) Description() string {
+	return a.Kind.Definition().Description
+}
+
+// PlayerRecords are the streaks, the records and the achievements of a player in a discipline.
+// Free-for-all matches count only as played games.
+type PlayerRecords struct {
+	LongestWinStreak int
+	// CurrentStreak is the number of the last matches won, negative for the matches lost, zero after a draw.
+	CurrentStreak int
+	// BiggestUpset is the win with the lowest probability by the primary rating system,
+	// zero if the player has not won a one on one match.
+	BiggestUpset Upset
+	// PeakRating is the highest primary rating after a match, zero if the player has not played.
+	PeakRating     Rating
+	PeakRatingDate time.Time
+	// Achievements are ordered by the date they were unlocked.
+	Achievements []Achievement
+}
+
+// StreakString describes the current streak, e.g. "побед подряд: 3".
+func (r PlayerRecords) StreakString() string {
+	switch {
+	case r.CurrentStreak > 0:
+		return "побед подряд: " + strconv.Itoa(r.CurrentStreak)
+	case r.CurrentStreak < 0:
+		return "поражений подряд: " + strconv.Itoa(-r.CurrentStreak)
+	default:
+		return "нет"
+	}
+}
+
+// Upset is a win over a stronger opponent.
+type Upset struct {
+	MatchID  int
+	Opponent Player
+	Date     time.Time
+	// Probability is the win probability of the player before the match.
+	Probability float64
+}
+
+// PercentString returns the probability in percent.
+func (u Upset) PercentString() string {
+	return strconv.FormatFloat(u.Probability*100, 'f', 0, 64) + "%"
+}
+
+// Simulation is the leaderboard after hypothetical matches, nothing of it is saved.
+type Simulation struct {
+	// Matches are the hypothetical matches with the rating changes they cause.
+	Matches []Match
+	// Players are the active players after the matches ordered by rank.
+	Players []SimulatedPlayer
+}
+
+// SimulatedPlayer is a player before and after the hypothetical matches.
+type SimulatedPlayer struct {
+	Before Player
+	After  Player
+}
+
+// Played reports whether the player took part in the hypothetical matches.
+func (p SimulatedPlayer) Played() bool {
+	return p.After.GamesPlayed != p.Before.GamesPlayed
+}
+
+// Ratings returns the ratings after the matches, Change is the total change caused by them.
+func (p SimulatedPlayer) Ratings() []Rating {
+	ratings := make([]Rating, 0, len(p.After.Ratings))
+	for i, r := range p.After.Ratings {
+		r.Change = 0
+		if i < len(p.Before.Ratings) {
+			r.Change = r.Value - p.Before.Ratings[i].Value
+		}
+		ratings = append(ratings, r)
+	}
+	return ratings
+}
+
+// RankChange returns how many places the player moved up, 0 if they were not ranked before or after.
+func (p SimulatedPlayer) RankChange() int {
+	if p.Before.RatingRank == 0 || p.After.RatingRank == 0 {
+		return 0
+	}
+	return p.Before.RatingRank - p.After.RatingRank
+}
+
+// TournamentFormat is the way the games of a tournament are paired.
+type TournamentFormat string
+
+const (
+	TournamentRoundRobin        TournamentFormat = "round_robin"
+	TournamentSwiss             TournamentFormat = "swiss"
+	TournamentSingleElimination TournamentFormat = "single_elimination"
+	TournamentDoubleElimination TournamentFormat = "double_elimination"
+)
+
+// TournamentFormats lists the formats in the order they are offered.
+var TournamentFormats = []TournamentFormat{
+	TournamentRoundRobin,
+	TournamentSwiss,
+	TournamentSingleElimination,
+	TournamentDoubleElimination,
+}
+
+func (f TournamentFormat) Title() string {
+	switch f {
+	case TournamentRoundRobin:
+		return "Круговой"
+	case TournamentSwiss:
+		return "Швейцарская система"
+	case TournamentSingleElimination:
+		return "Олимпийская система"
+	case TournamentDoubleElimination:
+		return "Двойное выбывание"
+	}
+	return string(f)
+}
+
+// Elimination reports whether the players are knocked out instead of scoring points.
+func (f TournamentFormat) Elimination() bool {
+	return f == TournamentSingleElimination || f == TournamentDoubleElimination
+}
+
+// TournamentBracket is the part of a tournament a game belongs to.
+type TournamentBracket string
+
+const (
+	// BracketMain holds all the games of the round robin and swiss tournaments.
+	BracketMain    TournamentBracket = "main"
+	BracketWinners TournamentBracket = "winners"
+	// BracketLosers is the second chance of the players of a double elimination.
+	BracketLosers TournamentBracket = "losers"
+	// BracketFinal is the grand final of a double elimination, the second game is
+	// played only if the winner of the losers bracket wins the first one.
+	BracketFinal TournamentBracket = "final"
+)
+
+func (b TournamentBracket) Title() string {
+	switch b {
+	case BracketWinners:
+		return "Верхняя сетка"
+	case BracketLosers:
+		return "Нижняя сетка"
+	case BracketFinal:
+		return "Финал"
+	}
+	return "Туры"
+}
+
+type Tournament struct {
+	ID           int
+	Name         string
+	DisciplineID int
+	Format       TournamentFormat
+	// Rounds is the number of the rounds of a swiss tournament.
+	Rounds int
+	// Players are ordered by seed, the strongest first.
+	Players []Player
+	// Games are ordered by bracket, round and position.
+	Games []TournamentGame
+	// Standings are ordered by place.
+	Standings []TournamentStanding
+	CreatedAt time.Time
+	// FinishedAt is set when the last game is played.
+	FinishedAt time.Time
+}
+
+func (t Tournament) Finished() bool {
+	return !t.FinishedAt.IsZero()
+}
+
+func (t Tournament) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("у турнира должно быть название")
+	}
+	known := false
+	for _, format := range TournamentFormats {
+		known = known || format == t.Format
+	}
+	if !known {
+		return errors.New("неизвестный формат турнира")
+	}
+	if len(t.Players) < 2 {
+		return errors.New("в турнире должно быть хотя бы два игрока")
+	}
+	ids := make(map[uuid.UUID]bool, len(t.Players))
+	for _, player := range t.Players {
+		if ids[player.ID] {
+			return errors.New("игрок " + player.Name + " указан дважды")
+		}
+		ids[player.ID] = true
+	}
+	if t.Format == TournamentSwiss && (t.Rounds < 1 || t.Rounds >= len(t.Players)) {
+		return errors.New("в швейцарском турнире должно быть от 1 до " + strconv.Itoa(len(t.Players)-1) + " туров")
+	}
 	return nil
 }
+
+// Round returns the number of the last round with games, 0 before the first one.
+func (t Tournament) Round() int {
+	round := 0
+	for _, game := range t.Games {
+		if game.Bracket == BracketMain && game.Round > round {
+			round = game.Round
+		}
+	}
+	return round
+}
+
+// Pending returns the games waiting for the result.
+func (t Tournament) Pending() []TournamentGame {
+	var pending []TournamentGame
+	for _, game := range t.Games {
+		if !game.Played() {
+			pending = append(pending, game)
+		}
+	}
+	return pending
+}
+
+// Champion returns the winner of the finished tournament.
+func (t Tournament) Champion() (Player, bool) {
+	if !t.Finished() || len(t.Standings) == 0 || t.Standings[0].Place != 1 {
+		return Player{}, false
+	}
+	if len(t.Standings) > 1 && t.Standings[1].Place == 1 {
+		return Player{}, false
+	}
+	return t.Standings[0].Player, true
+}
+
+// TournamentRound is the games of a round of a bracket.
+type TournamentRound struct {
+	Bracket TournamentBracket
+	Number  int
+	Games   []TournamentGame
+}
+
+// Title returns the name of the round shown to users.
+func (r TournamentRound) Title() string {
+	if r.Bracket == BracketFinal {
+		if r.Number > 1 {
+			return "Переигровка финала"
+		}
+		return "Финал"
+	}
+	return strconv.Itoa(r.Number) + " тур"
+}
+
+// Schedule groups the games by bracket and round.
+func (t Tournament) Schedule() []TournamentRound {
+	var rounds []TournamentRound
+	for _, game := range t.Games {
+		n := len(rounds)
+		if n == 0 || rounds[n-1].Bracket != game.Bracket || rounds[n-1].Number != game.Round {
+			rounds = append(rounds, TournamentRound{Bracket: game.Bracket, Number: game.Round})
+			n++
+		}
+		rounds[n-1].Games = append(rounds[n-1].Games, game)
+	}
+	return rounds
+}
+
+// Brackets returns the brackets having games in the order they are shown.
+func (t Tournament) Brackets() []TournamentBracket {
+	var brackets []TournamentBracket
+	for _, round := range t.Schedule() {
+		if n := len(brackets); n == 0 || brackets[n-1] != round.Bracket {
+			brackets = append(brackets, round.Bracket)
+		}
+	}
+	return brackets
+}
+
+// TournamentGame is a pairing of a tournament. A bracket, a round and a position identify it.
+type TournamentGame struct {
+	Bracket  TournamentBracket
+	Round    int
+	Position int
+	PlayerA  Player
+	// PlayerB is empty for a bye, PlayerA advances without a match.
+	PlayerB Player
+	// MatchID is the recorded match, 0 until the game is played.
+	MatchID int
+	// Match is the recorded match with the rating changes, nil until the game is played.
+	Match *Match
+}
+
+func (g TournamentGame) Bye() bool {
+	return g.PlayerB.ID == uuid.Nil
+}
+
+func (g TournamentGame) Played() bool {
+	return g.Bye() || g.Match != nil
+}
+
+// Has reports whether the game is between the players in any order.
+func (g TournamentGame) Has(a, b uuid.UUID) bool {
+	return g.PlayerA.ID == a && g.PlayerB.ID == b || g.PlayerA.ID == b && g.PlayerB.ID == a
+}
+
+// Winner returns the winner of the played game, empty for a draw.
+func (g TournamentGame) Winner() Player {
+	if g.Bye() {
+		return g.PlayerA
+	}
+	if g.Match == nil {
+		return Player{}
+	}
+	if g.Match.Winner.ID == g.PlayerA.ID {
+		return g.PlayerA
+	}
+	if g.Match.Winner.ID == g.PlayerB.ID {
+		return g.PlayerB
+	}
+	return Player{}
+}
+
+// Loser returns the loser of the played game, empty for a draw or a bye.
+func (g TournamentGame) Loser() Player {
+	switch winner := g.Winner(); {
+	case winner.ID == uuid.Nil || g.Bye():
+		return Player{}
+	case winner.ID == g.PlayerA.ID:
+		return g.PlayerB
+	default:
+		return g.PlayerA
+	}
+}
+
+// TournamentStanding is the place of a player in a tournament.
+type TournamentStanding struct {
+	Place  int
+	Player Player
+	// Points are 1 for a win or a bye and 0.5 for a draw.
+	Points float64
+	Wins   int
+	Draws  int
+	Losses int
+	// Buchholz is the sum of the points of the opponents, the first tiebreak.
+	Buchholz float64
+	// SonnebornBerger is the sum of the points of the beaten opponents and
+	// half the points of the drawn ones, the second tiebreak.
+	SonnebornBerger float64
+	// Eliminated is set for the players knocked out of an elimination tournament.
+	Eliminated bool
+}
+
+func (s TournamentStanding) PointsString() string {
+	return strconv.FormatFloat(s.Points, 'f', -1, 64)
+}
+
+func (s TournamentStanding) BuchholzString() string {
+	return strconv.FormatFloat(s.Buchholz, 'f', -1, 64)
+}
+
+func (s TournamentStanding) SonnebornBergerString() string {
+	return strconv.FormatFloat(s.SonnebornBerger, 'f', -1, 64)
+}
+
+// LadderEntry is a player who has joined the ladder of a discipline.
+type LadderEntry struct {
+	DisciplineID int
+	Player       Player
+	JoinedAt     time.Time
+}
+
+// LadderStatus is the state of a ladder challenge, it depends on the time.
+type LadderStatus string
+
+const (
+	LadderOpen      LadderStatus = "open"
+	LadderAccepted  LadderStatus = "accepted"
+	LadderPlayed    LadderStatus = "played"
+	LadderForfeit   LadderStatus = "forfeit"
+	LadderExpired   LadderStatus = "expired"
+	LadderCancelled LadderStatus = "cancelled"
+)
+
+func (s LadderStatus) Title() string {
+	switch s {
+	case LadderOpen:
+		return "ждёт ответа"
+	case LadderAccepted:
+		return "принят"
+	case LadderPlayed:
+		return "сыгран"
+	case LadderForfeit:
+		return "техническое поражение"
+	case LadderExpired:
+		return "не сыгран в срок"
+	case LadderCancelled:
+		return "отменён"
+	}
+	return string(s)
+}
+
+// LadderChallenge is a challenge of a player to a player above on the ladder.
+// The challenged player has to accept or play it before the deadline, otherwise
+// they forfeit. The accepted challenge not played before the deadline expires without changing the ladder.
+// Match is set if the recorded match is not voided, Status is set by the service.
+type LadderChallenge struct {
+	ID           int
+	DisciplineID int
+	Challenger   Player
+	Challenged   Player
+	CreatedAt    time.Time
+	Deadline     time.Time
+	AcceptedAt   time.Time
+	CancelledAt  time.Time
+	MatchID      int
+	Match        *Match
+	Status       LadderStatus
+}
+
+// Open reports whether the challenge is not resolved yet.
+func (c LadderChallenge) Open() bool {
+	return c.Status == LadderOpen || c.Status == LadderAccepted
+}
+
+// Has reports whether the player takes part in the challenge.
+func (c LadderChallenge) Has(id uuid.UUID) bool {
+	return c.Challenger.ID == id || c.Challenged.ID == id
+}
+
+// ChallengerWon reports whether the challenger takes the position of the challenged player.
+func (c LadderChallenge) ChallengerWon() bool {
+	switch c.Status {
+	case LadderForfeit:
+		return true
+	case LadderPlayed:
+		return c.Match.Winner.ID == c.Challenger.ID
+	}
+	return false
+}
+
+// ResolvedAt returns the time the challenge changed the ladder: the date of the match or the deadline of a forfeit.
+func (c LadderChallenge) ResolvedAt() time.Time {
+	if c.Status == LadderPlayed {
+		return c.Match.Date
+	}
+	return c.Deadline
+}
+
+// LadderRung is the position of a player on the ladder, 1 is the top.
+type LadderRung struct {
+	Position int
+	Player   Player
+}
+
+// Ladder is the players of a discipline by their positions and the challenges, the latest first.
+type Ladder struct {
+	DisciplineID int
+	// Reach is how many positions above a player can challenge.
+	Reach      int
+	Rungs      []LadderRung
+	Challenges []LadderChallenge
+}
+
+// Position returns the position of the player, 0 if they are not on the ladder.
+func (l Ladder) Position(id uuid.UUID) int {
+	for _, rung := range l.Rungs {
+		if rung.Player.ID == id {
+			return rung.Position
+		}
+	}
+	return 0
+}
+
+// Open returns the unresolved challenges.
+func (l Ladder) Open() []LadderChallenge {
+	var open []LadderChallenge
+	for _, c := range l.Challenges {
+		if c.Open() {
+			open = append(open, c)
+		}
+	}
+	return open
+}
@@ -10,17 +10,44 @@ const (
 	Lose Points = 0
 )
 
-// Calculate new rating.
+// DefaultScale is the rating difference at which the stronger player is
+// expected to score 10 times more than the weaker one.
+const DefaultScale = 400
+
+// maxUpset is the biggest rating difference the underdog's win is scaled by,
+// the multiplier grows without a bound as the difference approaches 2200.
+const maxUpset = 1000
+
+// Calculate new rating with the default scale.
 // Ra - player A rating.
 // Rb - player B rating.
 // K - coefficient: if >= 2400 then 10; if < 2400 then 20; if first 40 games then 40.
 // Sa - points: 1 for win; 0.5 for draw; 0 for lose.
 func Calculate(Ra int, Rb int, K int, Sa Points) int {
+	return CalculateScaled(Ra, Rb, float64(K), Sa, DefaultScale)
+}
+
+// CalculateScaled calculates new rating like Calculate with the given scale and fractional K.
+func CalculateScaled(Ra int, Rb int, K float64, Sa Points, scale float64) int {
 	ra := float64(Ra)
-	rb := float64(Rb)
-	k := float64(K)
+	k := K
 
-	Ea := 1.0 / (1.0 + math.Pow(10, (rb-ra)/400.0))
+	Ea := Expected(Ra, Rb, scale)
 	ra = ra + k*(float64(Sa)-Ea)
 	return int(math.Round(ra))
 }
+
+// MarginMultiplier scales K by the margin of victory, so big wins count more.
+// The multiplier is smaller when the favourite wins, which prevents rating inflation.
+// It is 1 for a one point win and never less, so a close win counts as much as a match without a score.
+// Rw and Rl are the ratings of the winner and the loser, an upset bigger than maxUpset counts as maxUpset.
+func MarginMultiplier(margin int, Rw int, Rl int) float64 {
+	difference := math.Max(float64(Rw-Rl), -maxUpset)
+	multiplier := math.Log(float64(margin)+1) / math.Ln2 * 2.2 / (difference*0.001 + 2.2)
+	return math.Max(1, multiplier)
+}
+
+// Expected returns the expected score of player A against player B.
+func Expected(Ra int, Rb int, scale float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10, float64(Rb-Ra)/scale))
+}
@@ -1,6 +1,9 @@
 package elo
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestCalculate(t *testing.T) {
 	type args struct {
@@ -123,3 +126,60 @@ func TestCalculate(t *testing.T) {
 		})
 	}
 }
+
+func TestMarginMultiplier(t *testing.T) {
+	type args struct {
+		margin int
+		Rw     int
+		Rl     int
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "equal players, one point",
+			args: args{margin: 1, Rw: 1000, Rl: 1000},
+			want: 1,
+		},
+		{
+			name: "equal players, ten points",
+			args: args{margin: 10, Rw: 1000, Rl: 1000},
+			want: 3.459,
+		},
+		{
+			name: "favourite wins",
+			args: args{margin: 10, Rw: 1200, Rl: 1000},
+			want: 3.171,
+		},
+		{
+			name: "underdog wins",
+			args: args{margin: 10, Rw: 1000, Rl: 1200},
+			want: 3.805,
+		},
+		{
+			name: "extreme underdog wins",
+			args: args{margin: 10, Rw: 1000, Rl: 3200},
+			want: 6.342,
+		},
+		{
+			name: "underdog beyond the biggest upset wins",
+			args: args{margin: 10, Rw: 1000, Rl: 5000},
+			want: 6.342,
+		},
+		{
+			name: "favourite wins by one point",
+			args: args{margin: 1, Rw: 1400, Rl: 1000},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarginMultiplier(tt.args.margin, tt.args.Rw, tt.args.Rl)
+			if math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("MarginMultiplier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
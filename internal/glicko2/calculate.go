@@ -0,0 +1,117 @@
+package glicko2
+
+import "math"
+
+// Scale converts ratings to the Glicko-2 internal scale.
+const Scale = 173.7178
+
+const convergence = 0.000001
+
+// Rating is a player's rating, rating deviation and volatility.
+type Rating struct {
+	R     float64
+	RD    float64
+	Sigma float64
+}
+
+// ConfidenceInterval returns the 95% confidence interval of the rating.
+func (r Rating) ConfidenceInterval() (float64, float64) {
+	return r.R - 2*r.RD, r.R + 2*r.RD
+}
+
+// Result is the score of a match against the opponent: 1 for win, 0.5 for draw, 0 for lose.
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Calculate new rating of the player after a rating period.
+// Tau constrains the change in volatility over time, reasonable values are between 0.3 and 1.2.
+func Calculate(player Rating, results []Result, tau float64) Rating {
+	if len(results) == 0 {
+		return Inflate(player, 1)
+	}
+	mu := player.R / Scale
+	phi := player.RD / Scale
+
+	var vInv, sum float64
+	for _, result := range results {
+		muJ := result.Opponent.R / Scale
+		gJ := g(result.Opponent.RD / Scale)
+		eJ := e(mu, muJ, gJ)
+		vInv += gJ * gJ * eJ * (1 - eJ)
+		sum += gJ * (result.Score - eJ)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma := volatility(delta, phi, v, player.Sigma, tau)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phiNew := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	muNew := mu + phiNew*phiNew*sum
+	return Rating{
+		R:     muNew * Scale,
+		RD:    phiNew * Scale,
+		Sigma: sigma,
+	}
+}
+
+// Inflate increases the rating deviation of the player who did not play
+// for the given number of rating periods.
+func Inflate(player Rating, periods int) Rating {
+	if periods <= 0 {
+		return player
+	}
+	phi := player.RD / Scale
+	phi = math.Sqrt(phi*phi + float64(periods)*player.Sigma*player.Sigma)
+	player.RD = phi * Scale
+	return player
+}
+
+// Expected returns the expected score of player A against player B.
+func Expected(a, b Rating) float64 {
+	phi := math.Sqrt(a.RD*a.RD+b.RD*b.RD) / Scale
+	return e(a.R/Scale, b.R/Scale, g(phi))
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func e(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
+
+// volatility finds the new volatility with the Illinois algorithm (step 5 of the Glicko-2 paper).
+func volatility(delta, phi, v, sigma, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
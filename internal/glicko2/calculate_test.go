@@ -0,0 +1,69 @@
+package glicko2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		player  Rating
+		results []Result
+		tau     float64
+		want    Rating
+	}{
+		{
+			name:   "example from the Glicko-2 paper",
+			player: Rating{R: 1500, RD: 200, Sigma: 0.06},
+			results: []Result{
+				{Opponent: Rating{R: 1400, RD: 30, Sigma: 0.06}, Score: 1},
+				{Opponent: Rating{R: 1550, RD: 100, Sigma: 0.06}, Score: 0},
+				{Opponent: Rating{R: 1700, RD: 300, Sigma: 0.06}, Score: 0},
+			},
+			tau:  0.5,
+			want: Rating{R: 1464.051, RD: 151.517, Sigma: 0.059996},
+		},
+		{
+			name:   "the rating does not depend on the base",
+			player: Rating{R: 1000, RD: 200, Sigma: 0.06},
+			results: []Result{
+				{Opponent: Rating{R: 900, RD: 30, Sigma: 0.06}, Score: 1},
+				{Opponent: Rating{R: 1050, RD: 100, Sigma: 0.06}, Score: 0},
+				{Opponent: Rating{R: 1200, RD: 300, Sigma: 0.06}, Score: 0},
+			},
+			tau:  0.5,
+			want: Rating{R: 964.051, RD: 151.517, Sigma: 0.059996},
+		},
+		{
+			name:   "no games",
+			player: Rating{R: 1500, RD: 200, Sigma: 0.06},
+			tau:    0.5,
+			want:   Rating{R: 1500, RD: 200.271, Sigma: 0.06},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.player, tt.results, tt.tau)
+			if !isEqual(got, tt.want) {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInflate(t *testing.T) {
+	player := Rating{R: 1500, RD: 50, Sigma: 0.06}
+	once := Inflate(Inflate(player, 1), 1)
+	twice := Inflate(player, 2)
+	if !isEqual(once, twice) {
+		t.Errorf("Inflate() for 2 periods = %v, want %v", twice, once)
+	}
+	if got := Inflate(player, 0); got != player {
+		t.Errorf("Inflate() for 0 periods = %v, want %v", got, player)
+	}
+}
+
+func isEqual(a, b Rating) bool {
+	return math.Abs(a.R-b.R) < 0.001 && math.Abs(a.RD-b.RD) < 0.001 && math.Abs(a.Sigma-b.Sigma) < 0.000001
+}
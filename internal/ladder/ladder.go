@@ -0,0 +1,84 @@
+package ladder
+
+import (
+	"sort"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+)
+
+// Status returns the status of the challenge at the time. Once the deadline has passed without a match
+// the challenge is forfeited, or expires if the challenged player has accepted it, so neither player stays blocked.
+func Status(c domain.LadderChallenge, now time.Time) domain.LadderStatus {
+	accepted := !c.AcceptedAt.IsZero()
+	switch {
+	case !c.CancelledAt.IsZero():
+		return domain.LadderCancelled
+	case c.Match != nil:
+		return domain.LadderPlayed
+	case now.After(c.Deadline) && accepted:
+		return domain.LadderExpired
+	case now.After(c.Deadline):
+		return domain.LadderForfeit
+	case accepted:
+		return domain.LadderAccepted
+	}
+	return domain.LadderOpen
+}
+
+// event is a change of the ladder: a player joins the bottom or a challenge is resolved.
+type event struct {
+	date      time.Time
+	entry     *domain.LadderEntry
+	challenge *domain.LadderChallenge
+}
+
+// Rungs returns the players by their positions. The players join the bottom in the order they joined.
+// The challenger who wins or whose opponent forfeits takes the position of the challenged player,
+// who moves one position down with everyone in between. A draw or a loss changes nothing.
+// The statuses of the challenges must be set.
+func Rungs(entries []domain.LadderEntry, challenges []domain.LadderChallenge) []domain.LadderRung {
+	events := make([]event, 0, len(entries)+len(challenges))
+	for i := range entries {
+		events = append(events, event{date: entries[i].JoinedAt, entry: &entries[i]})
+	}
+	for i := range challenges {
+		if challenges[i].ChallengerWon() {
+			events = append(events, event{date: challenges[i].ResolvedAt(), challenge: &challenges[i]})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].date.Before(events[j].date)
+	})
+
+	var order []domain.Player
+	position := func(p domain.Player) int {
+		for i := range order {
+			if order[i].ID == p.ID {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, e := range events {
+		if e.entry != nil {
+			if position(e.entry.Player) < 0 {
+				order = append(order, e.entry.Player)
+			}
+			continue
+		}
+		from, to := position(e.challenge.Challenger), position(e.challenge.Challenged)
+		if from < 0 || to < 0 || from < to {
+			continue
+		}
+		challenger := order[from]
+		copy(order[to+1:from+1], order[to:from])
+		order[to] = challenger
+	}
+
+	rungs := make([]domain.LadderRung, 0, len(order))
+	for i, player := range order {
+		rungs = append(rungs, domain.LadderRung{Position: i + 1, Player: player})
+	}
+	return rungs
+}
@@ -0,0 +1,143 @@
+package ladder
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestStatus(t *testing.T) {
+	deadline := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		challenge domain.LadderChallenge
+		now       time.Time
+		want      domain.LadderStatus
+	}{
+		{
+			name:      "open",
+			challenge: domain.LadderChallenge{Deadline: deadline},
+			now:       deadline.Add(-time.Hour),
+			want:      domain.LadderOpen,
+		},
+		{
+			name:      "forfeit",
+			challenge: domain.LadderChallenge{Deadline: deadline},
+			now:       deadline.Add(time.Hour),
+			want:      domain.LadderForfeit,
+		},
+		{
+			name:      "accepted before the deadline",
+			challenge: domain.LadderChallenge{Deadline: deadline, AcceptedAt: deadline.Add(-time.Hour)},
+			now:       deadline.Add(-time.Minute),
+			want:      domain.LadderAccepted,
+		},
+		{
+			name:      "accepted and not played in time",
+			challenge: domain.LadderChallenge{Deadline: deadline, AcceptedAt: deadline.Add(-time.Hour)},
+			now:       deadline.Add(time.Hour),
+			want:      domain.LadderExpired,
+		},
+		{
+			name:      "played",
+			challenge: domain.LadderChallenge{Deadline: deadline, Match: &domain.Match{}},
+			now:       deadline.Add(time.Hour),
+			want:      domain.LadderPlayed,
+		},
+		{
+			name:      "cancelled",
+			challenge: domain.LadderChallenge{Deadline: deadline, CancelledAt: deadline.Add(-time.Hour), Match: &domain.Match{}},
+			now:       deadline,
+			want:      domain.LadderCancelled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Status(tt.challenge, tt.now); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRungs(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	players := make([]domain.Player, 5)
+	entries := make([]domain.LadderEntry, len(players))
+	for i := range players {
+		players[i] = domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i+1)}
+		entries[i] = domain.LadderEntry{Player: players[i], JoinedAt: start.Add(time.Duration(i) * time.Minute)}
+	}
+	challenge := func(challenger, challenged int, status domain.LadderStatus, winner int, date time.Time) domain.LadderChallenge {
+		c := domain.LadderChallenge{Challenger: players[challenger], Challenged: players[challenged], Deadline: date, Status: status}
+		if status == domain.LadderPlayed {
+			c.Match = &domain.Match{PlayerA: players[challenger], PlayerB: players[challenged], Date: date}
+			if winner >= 0 {
+				c.Match.Winner = players[winner]
+			}
+		}
+		return c
+	}
+	names := func(rungs []domain.LadderRung) []string {
+		var names []string
+		for i, rung := range rungs {
+			if rung.Position != i+1 {
+				t.Errorf("rung %d has the position %d", i, rung.Position)
+			}
+			names = append(names, rung.Player.Name)
+		}
+		return names
+	}
+	day := 24 * time.Hour
+	tests := []struct {
+		name       string
+		challenges []domain.LadderChallenge
+		want       []string
+	}{
+		{
+			name: "the order of joining",
+			want: []string{"player1", "player2", "player3", "player4", "player5"},
+		},
+		{
+			name:       "the winner takes the position",
+			challenges: []domain.LadderChallenge{challenge(4, 1, domain.LadderPlayed, 4, start.Add(day))},
+			want:       []string{"player1", "player5", "player2", "player3", "player4"},
+		},
+		{
+			name:       "the forfeit counts as a win",
+			challenges: []domain.LadderChallenge{challenge(2, 0, domain.LadderForfeit, -1, start.Add(day))},
+			want:       []string{"player3", "player1", "player2", "player4", "player5"},
+		},
+		{
+			name: "a loss, a draw and the unresolved challenges change nothing",
+			challenges: []domain.LadderChallenge{
+				challenge(4, 3, domain.LadderPlayed, 3, start.Add(day)),
+				challenge(2, 1, domain.LadderPlayed, -1, start.Add(day)),
+				challenge(1, 0, domain.LadderOpen, -1, start.Add(day)),
+				challenge(3, 2, domain.LadderCancelled, -1, start.Add(day)),
+			},
+			want: []string{"player1", "player2", "player3", "player4", "player5"},
+		},
+		{
+			name: "in the order of the results",
+			challenges: []domain.LadderChallenge{
+				challenge(2, 0, domain.LadderPlayed, 2, start.Add(2*day)),
+				challenge(4, 2, domain.LadderPlayed, 4, start.Add(day)),
+			},
+			want: []string{"player3", "player1", "player2", "player5", "player4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(Rungs(entries, tt.challenges))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rungs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package rating
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/elo"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eloDefaultInitialRating = 1000
+	eloDefaultScale         = elo.DefaultScale
+
+	week = time.Hour * 24 * 7
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// eloDefaultKFactor: 40 for the first 30 games, 10 for the masters, 20 for others.
+var eloDefaultKFactor = []config.EloKRule{
+	{MaxGames: intPtr(30), K: 40},
+	{MinRating: intPtr(2400), K: 10},
+	{K: 20},
+}
+
+type eloSystem struct {
+	initialRating int
+	scale         float64
+	kFactor       []config.EloKRule
+	decayAfter    time.Duration
+	decayPerWeek  int
+	decayFloor    int
+	// marginOfVictory scales K by the score difference.
+	marginOfVictory bool
+}
+
+// NewElo creates Elo rating system, zero config values are replaced with the defaults.
+func NewElo(cfg config.Elo) (System, error) {
+	s := eloSystem{
+		initialRating: cfg.InitialRating,
+		scale:         cfg.Scale,
+		kFactor:       cfg.KFactor,
+		decayAfter:    time.Duration(cfg.DecayAfterDays) * time.Hour * 24,
+		decayPerWeek:  cfg.DecayPerWeek,
+		decayFloor:    cfg.DecayFloor,
+
+		marginOfVictory: cfg.MarginOfVictory,
+	}
+	if s.initialRating == 0 {
+		s.initialRating = eloDefaultInitialRating
+	}
+	if s.scale == 0 {
+		s.scale = eloDefaultScale
+	}
+	if len(s.kFactor) == 0 {
+		s.kFactor = eloDefaultKFactor
+	}
+	if s.decayFloor == 0 {
+		s.decayFloor = s.initialRating
+	}
+	return s, s.validate()
+}
+
+func (s eloSystem) validate() error {
+	var err error
+	if s.initialRating < 0 {
+		err = errors.Join(err, errors.New("initial_rating must be positive"))
+	}
+	if s.scale < 0 {
+		err = errors.Join(err, errors.New("scale must be positive"))
+	}
+	if s.decayAfter < 0 || s.decayPerWeek < 0 || s.decayFloor < 0 {
+		err = errors.Join(err, errors.New("decay parameters must be positive"))
+	}
+	for i, rule := range s.kFactor {
+		if rule.K <= 0 {
+			err = errors.Join(err, errors.New("k_factor rule "+strconv.Itoa(i+1)+": k must be positive"))
+		}
+		if rule.MinGames != nil && rule.MaxGames != nil && *rule.MinGames > *rule.MaxGames {
+			err = errors.Join(err, errors.New("k_factor rule "+strconv.Itoa(i+1)+": min_games is greater than max_games"))
+		}
+		if rule.MinRating != nil && rule.MaxRating != nil && *rule.MinRating > *rule.MaxRating {
+			err = errors.Join(err, errors.New("k_factor rule "+strconv.Itoa(i+1)+": min_rating is greater than max_rating"))
+		}
+	}
+	if last := s.kFactor[len(s.kFactor)-1]; !isUnconditional(last) {
+		err = errors.Join(err, errors.New("the last k_factor rule must have no conditions"))
+	}
+	return err
+}
+
+func isUnconditional(rule config.EloKRule) bool {
+	return rule.MinGames == nil && rule.MaxGames == nil && rule.MinRating == nil && rule.MaxRating == nil
+}
+
+func (eloSystem) Name() string {
+	return Elo
+}
+
+func (eloSystem) Title() string {
+	return "Elo"
+}
+
+func (s eloSystem) NewState() State {
+	return &eloState{
+		system:  s,
+		players: make(map[uuid.UUID]eloPlayer),
+	}
+}
+
+type eloPlayer struct {
+	rating      int
+	change      int
+	gamesPlayed int
+	lastPlayed  time.Time
+}
+
+type eloState struct {
+	system  eloSystem
+	players map[uuid.UUID]eloPlayer
+}
+
+func (s *eloState) player(id uuid.UUID) eloPlayer {
+	p, ok := s.players[id]
+	if !ok {
+		return eloPlayer{rating: s.system.initialRating}
+	}
+	return p
+}
+
+// Apply updates the ratings of all the players of the match. In team matches
+// the expected score is calculated from the average ratings of the sides and
+// every player's rating changes with the player's own K.
+func (s *eloState) Apply(match domain.Match) {
+	sideA := s.side(match.SideA(), match.Date)
+	sideB := s.side(match.SideB(), match.Date)
+	ratingA := averageRating(sideA)
+	ratingB := averageRating(sideB)
+
+	pointsA, pointsB := calculatePoints(match.PlayerA.ID, match.Winner.ID)
+	multiplier := s.system.marginMultiplier(match, ratingA, ratingB)
+	s.update(sideA, ratingB-ratingA, pointsA, multiplier, match.Date)
+	s.update(sideB, ratingA-ratingB, pointsB, multiplier, match.Date)
+}
+
+// ApplyFFA updates the ratings as if every pair of the players played
+// a match, all of them with the ratings before the free-for-all match.
+// The player's K is split between the opponents, so the rating changes
+// as much as in a single match between two players.
+func (s *eloState) ApplyFFA(match domain.FFAMatch) {
+	players := s.side(match.Players(), match.Date)
+	ratings := make(map[uuid.UUID]int, len(players))
+	for _, p := range players {
+		ratings[p.id] = p.rating
+	}
+	surplus := make(map[uuid.UUID]float64, len(players))
+	for _, pair := range match.Pairwise() {
+		pointsA, pointsB := calculatePoints(pair.PlayerA.ID, pair.Winner.ID)
+		expectedA := elo.Expected(ratings[pair.PlayerA.ID], ratings[pair.PlayerB.ID], s.system.scale)
+		surplus[pair.PlayerA.ID] += float64(pointsA) - expectedA
+		surplus[pair.PlayerB.ID] += float64(pointsB) - (1 - expectedA)
+	}
+	opponents := float64(len(players) - 1)
+	for _, p := range players {
+		k := float64(s.system.playerCoefficient(p.gamesPlayed, p.rating)) / opponents
+		p.change = int(math.Round(k * surplus[p.id]))
+		p.rating += p.change
+		p.gamesPlayed++
+		p.lastPlayed = match.Date
+		s.players[p.id] = p.eloPlayer
+	}
+}
+
+type eloSidePlayer struct {
+	id uuid.UUID
+	eloPlayer
+}
+
+func (s *eloState) side(players []domain.Player, date time.Time) []eloSidePlayer {
+	side := make([]eloSidePlayer, 0, len(players))
+	for _, player := range players {
+		side = append(side, eloSidePlayer{
+			id:        player.ID,
+			eloPlayer: s.system.decay(s.player(player.ID), date),
+		})
+	}
+	return side
+}
+
+func averageRating(side []eloSidePlayer) int {
+	var sum int
+	for _, p := range side {
+		sum += p.rating
+	}
+	return int(math.Round(float64(sum) / float64(len(side))))
+}
+
+// update applies the match result to the players of a side,
+// diff is the rating advantage of the opposing side.
+func (s *eloState) update(side []eloSidePlayer, diff int, points elo.Points, multiplier float64, date time.Time) {
+	for _, p := range side {
+		k := float64(s.system.playerCoefficient(p.gamesPlayed, p.rating)) * multiplier
+		newRating := elo.CalculateScaled(p.rating, p.rating+diff, k, points, s.system.scale)
+		p.change = newRating - p.rating
+		p.rating = newRating
+		p.gamesPlayed++
+		p.lastPlayed = date
+		s.players[p.id] = p.eloPlayer
+	}
+}
+
+func (s *eloState) Rating(id uuid.UUID) domain.Rating {
+	return s.player(id).toDomain()
+}
+
+func (s *eloState) RatingAt(id uuid.UUID, date time.Time) domain.Rating {
+	return s.system.decay(s.player(id), date).toDomain()
+}
+
+func (s *eloState) Expected(a, b uuid.UUID, date time.Time) float64 {
+	playerA := s.system.decay(s.player(a), date)
+	playerB := s.system.decay(s.player(b), date)
+	return elo.Expected(playerA.rating, playerB.rating, s.system.scale)
+}
+
+func (s *eloState) Clone() State {
+	players := make(map[uuid.UUID]eloPlayer, len(s.players))
+	for id, p := range s.players {
+		players[id] = p
+	}
+	return &eloState{
+		system:  s.system,
+		players: players,
+	}
+}
+
+func (s *eloState) Regress(carryover float64) {
+	for id, p := range s.players {
+		p.rating = s.system.initialRating + int(math.Round(float64(p.rating-s.system.initialRating)*carryover))
+		p.change = 0
+		s.players[id] = p
+	}
+}
+
+// decay lowers the rating by decayPerWeek for every full week of inactivity
+// after decayAfter, but not below the floor.
+func (s eloSystem) decay(p eloPlayer, date time.Time) eloPlayer {
+	if s.decayPerWeek == 0 || p.gamesPlayed == 0 || p.rating <= s.decayFloor {
+		return p
+	}
+	weeks := int((date.Sub(p.lastPlayed) - s.decayAfter) / week)
+	if weeks <= 0 {
+		return p
+	}
+	p.rating -= weeks * s.decayPerWeek
+	if p.rating < s.decayFloor {
+		p.rating = s.decayFloor
+	}
+	return p
+}
+
+func (p eloPlayer) toDomain() domain.Rating {
+	return domain.Rating{
+		System: Elo,
+		Value:  float64(p.rating),
+		Change: float64(p.change),
+	}
+}
+
+// marginMultiplier returns the K multiplier for the match score,
+// 1 if it is disabled or the match has no score or ended in a draw.
+func (s eloSystem) marginMultiplier(match domain.Match, ratingA, ratingB int) float64 {
+	if !s.marginOfVictory || match.Score == nil {
+		return 1
+	}
+	switch match.Winner.ID {
+	case match.PlayerA.ID:
+		return elo.MarginMultiplier(match.Score.Margin(), ratingA, ratingB)
+	case match.PlayerB.ID:
+		return elo.MarginMultiplier(match.Score.Margin(), ratingB, ratingA)
+	}
+	return 1
+}
+
+// playerCoefficient returns K of the first matching rule.
+func (s eloSystem) playerCoefficient(n int, rating int) int {
+	for _, rule := range s.kFactor {
+		if ruleMatches(rule, n, rating) {
+			return rule.K
+		}
+	}
+	return s.kFactor[len(s.kFactor)-1].K
+}
+
+func ruleMatches(rule config.EloKRule, n int, rating int) bool {
+	switch {
+	case rule.MinGames != nil && n < *rule.MinGames:
+		return false
+	case rule.MaxGames != nil && n > *rule.MaxGames:
+		return false
+	case rule.MinRating != nil && rating < *rule.MinRating:
+		return false
+	case rule.MaxRating != nil && rating > *rule.MaxRating:
+		return false
+	}
+	return true
+}
+
+func calculatePoints(a, winner uuid.UUID) (elo.Points, elo.Points) {
+	if winner == uuid.Nil {
+		return elo.Draw, elo.Draw
+	}
+	if winner == a {
+		return elo.Win, elo.Lose
+	}
+	return elo.Lose, elo.Win
+}
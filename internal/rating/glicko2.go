@@ -0,0 +1,290 @@
+package rating
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/glicko2"
+
+	"github.com/google/uuid"
+)
+
+const (
+	glicko2DefaultRating     = 1500
+	glicko2DefaultDeviation  = 350
+	glicko2DefaultVolatility = 0.06
+	glicko2DefaultTau        = 0.5
+)
+
+type glicko2System struct {
+	initial glicko2.Rating
+	tau     float64
+	periods glicko2Periods
+}
+
+// NewGlicko2 creates Glicko-2 rating system, zero config values are replaced with the defaults.
+func NewGlicko2(cfg config.Glicko2) (System, error) {
+	s := glicko2System{
+		initial: glicko2.Rating{
+			R:     cfg.InitialRating,
+			RD:    cfg.InitialDeviation,
+			Sigma: cfg.InitialVolatility,
+		},
+		tau: cfg.Tau,
+	}
+	if s.initial.R == 0 {
+		s.initial.R = glicko2DefaultRating
+	}
+	if s.initial.RD == 0 {
+		s.initial.RD = glicko2DefaultDeviation
+	}
+	if s.initial.Sigma == 0 {
+		s.initial.Sigma = glicko2DefaultVolatility
+	}
+	if s.tau == 0 {
+		s.tau = glicko2DefaultTau
+	}
+	var err error
+	if s.initial.R < 0 {
+		err = errors.Join(err, errors.New("initial_rating must be positive"))
+	}
+	if s.initial.RD < 0 {
+		err = errors.Join(err, errors.New("initial_deviation must be positive"))
+	}
+	if s.initial.Sigma < 0 {
+		err = errors.Join(err, errors.New("initial_volatility must be positive"))
+	}
+	if s.tau < 0 {
+		err = errors.Join(err, errors.New("tau must be positive"))
+	}
+	periods, periodsErr := newGlicko2Periods(cfg)
+	s.periods = periods
+	return s, errors.Join(err, periodsErr)
+}
+
+func (glicko2System) Name() string {
+	return Glicko2
+}
+
+func (glicko2System) Title() string {
+	return "Glicko-2"
+}
+
+func (s glicko2System) NewState() State {
+	return &glicko2State{
+		system:  s,
+		base:    make(map[uuid.UUID]glicko2.Rating),
+		current: make(map[uuid.UUID]domain.Rating),
+	}
+}
+
+// inflate increases the deviation of an idle player, but not above the initial one.
+func (s glicko2System) inflate(r glicko2.Rating, periods int) glicko2.Rating {
+	r = glicko2.Inflate(r, periods)
+	r.RD = math.Min(r.RD, s.initial.RD)
+	return r
+}
+
+// glicko2State keeps the ratings at the start of the current rating period
+// and the matches played in it. Ratings of the players who played in the
+// current period are provisional until the period is closed.
+type glicko2State struct {
+	system  glicko2System
+	base    map[uuid.UUID]glicko2.Rating
+	current map[uuid.UUID]domain.Rating
+	period  []domain.Match
+	last    time.Time
+}
+
+func (s *glicko2State) Apply(match domain.Match) {
+	s.add(match.Date, append(match.SideA(), match.SideB()...), []domain.Match{match})
+}
+
+// ApplyFFA adds a match for every pair of the players to the period.
+func (s *glicko2State) ApplyFFA(match domain.FFAMatch) {
+	s.add(match.Date, match.Players(), match.Pairwise())
+}
+
+// add adds the matches played at the date to the rating period
+// and recalculates the provisional ratings of the players.
+func (s *glicko2State) add(date time.Time, players []domain.Player, matches []domain.Match) {
+	if len(s.period) > 0 {
+		if n := s.system.periods.elapsed(s.last, date); n > 0 {
+			s.closePeriod(n)
+		}
+	}
+	if date.After(s.last) || len(s.period) == 0 {
+		s.last = date
+	}
+	s.period = append(s.period, matches...)
+	ratings := make([]domain.Rating, len(players))
+	for i := range players {
+		ratings[i] = s.calculate(players[i].ID)
+	}
+	for i := range players {
+		s.current[players[i].ID] = ratings[i]
+	}
+}
+
+func (s *glicko2State) Rating(id uuid.UUID) domain.Rating {
+	if r, ok := s.current[id]; ok {
+		return r
+	}
+	return glicko2ToDomain(s.baseRating(id))
+}
+
+// RatingAt returns the rating with the deviation increased for the periods
+// passed since the last match.
+func (s *glicko2State) RatingAt(id uuid.UUID, date time.Time) domain.Rating {
+	if len(s.period) == 0 {
+		return s.Rating(id)
+	}
+	n := s.system.periods.elapsed(s.last, date)
+	if n == 0 {
+		return s.Rating(id)
+	}
+	if r, ok := s.current[id]; ok {
+		inflated := glicko2ToDomain(s.system.inflate(glicko2FromDomain(r), n-1))
+		inflated.Change = r.Change
+		return inflated
+	}
+	return glicko2ToDomain(s.system.inflate(s.baseRating(id), n))
+}
+
+func (s *glicko2State) Expected(a, b uuid.UUID, date time.Time) float64 {
+	return glicko2.Expected(glicko2FromDomain(s.RatingAt(a, date)), glicko2FromDomain(s.RatingAt(b, date)))
+}
+
+func (s *glicko2State) Clone() State {
+	clone := glicko2State{
+		system:  s.system,
+		base:    make(map[uuid.UUID]glicko2.Rating, len(s.base)),
+		current: make(map[uuid.UUID]domain.Rating, len(s.current)),
+		period:  make([]domain.Match, len(s.period)),
+		last:    s.last,
+	}
+	for id, r := range s.base {
+		clone.base[id] = r
+	}
+	for id, r := range s.current {
+		clone.current[id] = r
+	}
+	copy(clone.period, s.period)
+	return &clone
+}
+
+// Regress closes the current period, the deviation moves toward the initial one
+// like the rating, the volatility is kept.
+func (s *glicko2State) Regress(carryover float64) {
+	for id := range s.current {
+		s.base[id] = glicko2FromDomain(s.current[id])
+	}
+	for id, r := range s.base {
+		r.R = s.system.initial.R + (r.R-s.system.initial.R)*carryover
+		r.RD = s.system.initial.RD + (r.RD-s.system.initial.RD)*carryover
+		s.base[id] = r
+	}
+	s.current = make(map[uuid.UUID]domain.Rating)
+	s.period = nil
+}
+
+func (s *glicko2State) baseRating(id uuid.UUID) glicko2.Rating {
+	if r, ok := s.base[id]; ok {
+		return r
+	}
+	return s.system.initial
+}
+
+// calculate returns the provisional rating of the player after all
+// the matches of the current period. In team matches the opponent is
+// the composite of the opposing side: the average rating and deviation.
+func (s *glicko2State) calculate(id uuid.UUID) domain.Rating {
+	before := s.Rating(id)
+	var results []glicko2.Result
+	for i := range s.period {
+		if !plays(s.period[i], id) {
+			continue
+		}
+		results = append(results, glicko2.Result{
+			Opponent: s.composite(s.period[i].Opponents(id)),
+			Score:    matchScore(s.period[i], id),
+		})
+	}
+	after := glicko2ToDomain(glicko2.Calculate(s.baseRating(id), results, s.system.tau))
+	after.Change = after.Value - before.Value
+	return after
+}
+
+func (s *glicko2State) composite(players []domain.Player) glicko2.Rating {
+	var composite glicko2.Rating
+	for _, player := range players {
+		r := s.baseRating(player.ID)
+		composite.R += r.R
+		composite.RD += r.RD * r.RD
+		composite.Sigma += r.Sigma
+	}
+	n := float64(len(players))
+	composite.R /= n
+	composite.RD = math.Sqrt(composite.RD / n)
+	composite.Sigma /= n
+	return composite
+}
+
+// closePeriod fixes the provisional ratings and increases the deviation
+// of the players for every period they did not play in. n is the number
+// of periods passed since the current one started, the current included.
+func (s *glicko2State) closePeriod(n int) {
+	for id, r := range s.base {
+		if _, ok := s.current[id]; ok {
+			continue
+		}
+		s.base[id] = s.system.inflate(r, n)
+	}
+	for id, r := range s.current {
+		s.base[id] = s.system.inflate(glicko2FromDomain(r), n-1)
+	}
+	s.current = make(map[uuid.UUID]domain.Rating)
+	s.period = nil
+}
+
+func glicko2FromDomain(r domain.Rating) glicko2.Rating {
+	return glicko2.Rating{
+		R:     r.Value,
+		RD:    r.Deviation,
+		Sigma: r.Volatility,
+	}
+}
+
+func glicko2ToDomain(r glicko2.Rating) domain.Rating {
+	rating := domain.Rating{
+		System:     Glicko2,
+		Value:      r.R,
+		Deviation:  r.RD,
+		Volatility: r.Sigma,
+	}
+	rating.Interval.Min, rating.Interval.Max = r.ConfidenceInterval()
+	return rating
+}
+
+func plays(match domain.Match, id uuid.UUID) bool {
+	for _, player := range append(match.SideA(), match.SideB()...) {
+		if player.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// matchScore returns 1 if the player's side won, 0.5 for a draw and 0 otherwise.
+func matchScore(match domain.Match, id uuid.UUID) float64 {
+	switch {
+	case match.Winner.ID == uuid.Nil:
+		return 0.5
+	case match.Won(id):
+		return 1
+	}
+	return 0
+}
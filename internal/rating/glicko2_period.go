@@ -0,0 +1,108 @@
+package rating
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+)
+
+const (
+	glicko2PeriodDay   = "day"
+	glicko2PeriodWeek  = "week"
+	glicko2PeriodMonth = "month"
+	glicko2PeriodEvent = "event"
+
+	glicko2DefaultPeriod   = glicko2PeriodDay
+	glicko2DefaultEventGap = time.Hour * 6
+	glicko2MinPeriodLength = time.Minute
+)
+
+// glicko2Periods splits the match history into rating periods.
+// Calendar periods are aligned to the start of a day, a week (Monday) or a month,
+// periods of a fixed length are aligned to the Unix epoch, an event lasts
+// until there is a pause between matches longer than eventGap.
+type glicko2Periods struct {
+	unit     string
+	length   time.Duration
+	eventGap time.Duration
+	location *time.Location
+}
+
+func newGlicko2Periods(cfg config.Glicko2) (glicko2Periods, error) {
+	var err error
+	p := glicko2Periods{
+		unit:     cfg.Period,
+		eventGap: glicko2DefaultEventGap,
+		location: time.Local,
+	}
+	switch p.unit {
+	case "":
+		p.unit = glicko2DefaultPeriod
+	case glicko2PeriodDay, glicko2PeriodWeek, glicko2PeriodMonth, glicko2PeriodEvent:
+	default:
+		length, parseErr := time.ParseDuration(p.unit)
+		switch {
+		case parseErr != nil:
+			err = errors.Join(err, errors.New("unknown period "+p.unit))
+		case length < glicko2MinPeriodLength:
+			err = errors.Join(err, errors.New("period must be at least "+glicko2MinPeriodLength.String()))
+		}
+		p.length = length
+	}
+	if cfg.EventGap != "" {
+		gap, parseErr := time.ParseDuration(cfg.EventGap)
+		if parseErr != nil || gap <= 0 {
+			err = errors.Join(err, errors.New("invalid event_gap "+cfg.EventGap))
+		}
+		p.eventGap = gap
+	}
+	if cfg.Timezone != "" {
+		location, loadErr := time.LoadLocation(cfg.Timezone)
+		if loadErr != nil {
+			err = errors.Join(err, loadErr)
+		}
+		p.location = location
+	}
+	return p, err
+}
+
+// elapsed returns the number of period boundaries between the dates,
+// 0 means both dates are in the same period.
+func (p glicko2Periods) elapsed(last, date time.Time) int {
+	if p.unit == glicko2PeriodEvent {
+		if date.Sub(last) > p.eventGap {
+			return 1
+		}
+		return 0
+	}
+	n := p.index(date) - p.index(last)
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+// index returns the sequential number of the calendar period the date belongs to.
+func (p glicko2Periods) index(date time.Time) int64 {
+	year, month, day := date.In(p.location).Date()
+	days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / int64(time.Hour*24/time.Second)
+	switch p.unit {
+	case glicko2PeriodDay:
+		return days
+	case glicko2PeriodWeek:
+		// 1970-01-01 is Thursday, the week starts on Monday 3 days before.
+		return floorDiv(days+3, 7)
+	case glicko2PeriodMonth:
+		return int64(year)*12 + int64(month) - 1
+	}
+	return floorDiv(date.Unix(), int64(p.length/time.Second))
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
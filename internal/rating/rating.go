@@ -0,0 +1,95 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// System is a rating algorithm replayed over the match history.
+type System interface {
+	// Name is the key the system is registered and configured by.
+	Name() string
+	// Title is the name shown to users.
+	Title() string
+	// NewState returns a state without any matches applied.
+	NewState() State
+}
+
+// State holds the ratings of a system after a prefix of the match history.
+type State interface {
+	// Apply adds the next match in chronological order,
+	// the ratings of the players after it are returned by Rating.
+	Apply(match domain.Match)
+	// ApplyFFA adds the next free-for-all match in chronological order.
+	ApplyFFA(match domain.FFAMatch)
+	// Rating returns the rating of the player after the last applied match.
+	Rating(id uuid.UUID) domain.Rating
+	// RatingAt returns the rating of the player at the date including the
+	// changes caused by inactivity. The date must not be earlier than the
+	// last applied match.
+	RatingAt(id uuid.UUID, date time.Time) domain.Rating
+	// Expected returns the expected score of player a against player b
+	// in a match played at the date: 1 is a sure win, 0 is a sure loss.
+	Expected(a, b uuid.UUID, date time.Time) float64
+	// Clone returns an independent copy of the state, so hypothetical
+	// matches can be applied to it.
+	Clone() State
+	// Regress starts a new season: every rating moves toward the initial one
+	// keeping the carryover share of the difference, the uncertainty grows
+	// the same way. Zero carryover resets the ratings, the games played are kept.
+	Regress(carryover float64)
+}
+
+const (
+	Elo       = "elo"
+	Glicko2   = "glicko2"
+	TrueSkill = "trueskill"
+)
+
+var registry = map[string]func(cfg config.Rating) (System, error){
+	Elo: func(cfg config.Rating) (System, error) {
+		return NewElo(cfg.Elo)
+	},
+	Glicko2: func(cfg config.Rating) (System, error) {
+		return NewGlicko2(cfg.Glicko2)
+	},
+	TrueSkill: func(config.Rating) (System, error) {
+		return NewTrueSkill(), nil
+	},
+}
+
+// DefaultSystems are used when no rating systems are configured.
+var DefaultSystems = []string{Elo, Glicko2}
+
+// New creates the configured rating systems. The order is preserved,
+// the first system is the primary one.
+func New(cfg config.Rating) ([]System, error) {
+	names := cfg.Systems
+	if len(names) == 0 {
+		names = DefaultSystems
+	}
+	systems := make([]System, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		newSystem, ok := registry[name]
+		if !ok {
+			return nil, errors.New("unknown rating system " + name)
+		}
+		if seen[name] {
+			return nil, errors.New("rating system " + name + " is configured twice")
+		}
+		seen[name] = true
+		system, err := newSystem(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("rating system %s: %w", name, err)
+		}
+		systems = append(systems, system)
+	}
+	return systems, nil
+}
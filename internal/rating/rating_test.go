@@ -0,0 +1,485 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/glicko2"
+
+	"github.com/google/uuid"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			names: nil,
+			want:  []string{Elo, Glicko2},
+		},
+		{
+			name:  "order is kept",
+			names: []string{Glicko2, Elo},
+			want:  []string{Glicko2, Elo},
+		},
+		{
+			name:    "unknown",
+			names:   []string{Elo, "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			names:   []string{Elo, Elo},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(config.Rating{Systems: tt.names})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("New() returned %d systems, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Name() != tt.want[i] {
+					t.Errorf("New()[%d] = %v, want %v", i, got[i].Name(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStateClone(t *testing.T) {
+	systems, err := New(config.Rating{Systems: []string{Elo, Glicko2, TrueSkill}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	start := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	for _, system := range systems {
+		t.Run(system.Name(), func(t *testing.T) {
+			state := system.NewState()
+			state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+			before := state.Rating(player1.ID)
+			if e := state.Expected(player1.ID, player2.ID, start); e <= 0.5 {
+				t.Errorf("Expected() = %v, want > 0.5 for the winner", e)
+			}
+
+			clone := state.Clone()
+			clone.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: start.AddDate(0, 0, 1)})
+			if clone.Rating(player1.ID) == before {
+				t.Errorf("match was not applied to the clone")
+			}
+			if after := state.Rating(player1.ID); after != before {
+				t.Errorf("match applied to the clone changed the state: %v -> %v", before, after)
+			}
+		})
+	}
+}
+
+func TestStateApplyFFA(t *testing.T) {
+	systems, err := New(config.Rating{Systems: []string{Elo, Glicko2, TrueSkill}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []domain.Player{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	match := domain.FFAMatch{
+		Date: time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+		Placements: []domain.Placement{
+			{Player: players[0], Place: 1},
+			{Player: players[1], Place: 2},
+			{Player: players[2], Place: 2},
+			{Player: players[3], Place: 4},
+		},
+	}
+	for _, system := range systems {
+		t.Run(system.Name(), func(t *testing.T) {
+			state := system.NewState()
+			state.ApplyFFA(match)
+			var ratings []float64
+			for _, player := range players {
+				ratings = append(ratings, state.Rating(player.ID).Value)
+			}
+			if ratings[0] <= ratings[1] || ratings[2] <= ratings[3] || ratings[0] <= ratings[3] {
+				t.Errorf("ApplyFFA() = %v, want ratings ordered by place", ratings)
+			}
+		})
+	}
+}
+
+func TestStateRegress(t *testing.T) {
+	systems, err := New(config.Rating{Systems: []string{Elo, Glicko2, TrueSkill}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	start := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	for _, system := range systems {
+		t.Run(system.Name(), func(t *testing.T) {
+			initial := system.NewState().Rating(player1.ID)
+			state := system.NewState()
+			for i := 0; i < 5; i++ {
+				state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start.AddDate(0, 0, i)})
+			}
+			before := state.Rating(player1.ID)
+
+			half := state.Clone()
+			half.Regress(0.5)
+			got := half.Rating(player1.ID)
+			if math.Abs(got.Value-(initial.Value+before.Value)/2) > 1 {
+				t.Errorf("Regress(0.5) = %v, want half way between %v and %v", got.Value, initial.Value, before.Value)
+			}
+			if got.Deviation < before.Deviation {
+				t.Errorf("Regress(0.5) deviation = %v, want not less than %v", got.Deviation, before.Deviation)
+			}
+
+			state.Regress(0)
+			if got := state.Rating(player1.ID); got.Value != initial.Value || got.Deviation != initial.Deviation {
+				t.Errorf("Regress(0) = %v, want %v", got, initial)
+			}
+		})
+	}
+}
+
+func TestEloFFA(t *testing.T) {
+	system, err := NewElo(config.Elo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []domain.Player{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	tests := []struct {
+		name   string
+		places []int
+		want   []float64
+	}{
+		{name: "finishing order", places: []int{1, 2, 3}, want: []float64{1020, 1000, 980}},
+		{name: "shared first place", places: []int{1, 1, 3}, want: []float64{1010, 1010, 980}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := domain.FFAMatch{}
+			for i, player := range players {
+				match.Placements = append(match.Placements, domain.Placement{Player: player, Place: tt.places[i]})
+			}
+			state := system.NewState()
+			state.ApplyFFA(match)
+			for i, player := range players {
+				if got := state.Rating(player.ID).Value; got != tt.want[i] {
+					t.Errorf("Rating(%d) = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewElo(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Elo
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  config.Elo{},
+		},
+		{
+			name: "custom",
+			cfg: config.Elo{
+				InitialRating: 1500,
+				Scale:         200,
+				KFactor:       []config.EloKRule{{MaxGames: intPtr(10), K: 50}, {K: 16}},
+			},
+		},
+		{
+			name:    "negative scale",
+			cfg:     config.Elo{Scale: -1},
+			wantErr: true,
+		},
+		{
+			name:    "zero k",
+			cfg:     config.Elo{KFactor: []config.EloKRule{{K: 0}}},
+			wantErr: true,
+		},
+		{
+			name:    "no fallback rule",
+			cfg:     config.Elo{KFactor: []config.EloKRule{{MaxGames: intPtr(10), K: 50}}},
+			wantErr: true,
+		},
+		{
+			name:    "empty range",
+			cfg:     config.Elo{KFactor: []config.EloKRule{{MinRating: intPtr(1200), MaxRating: intPtr(1100), K: 30}, {K: 20}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewElo(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewElo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEloPlayerCoefficient(t *testing.T) {
+	system, err := NewElo(config.Elo{KFactor: []config.EloKRule{
+		{MaxGames: intPtr(30), K: 40},
+		{MinRating: intPtr(1100), K: 16},
+		{K: 24},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := system.(eloSystem)
+	tests := []struct {
+		games  int
+		rating int
+		want   int
+	}{
+		{games: 0, rating: 1000, want: 40},
+		{games: 30, rating: 1200, want: 40},
+		{games: 31, rating: 1100, want: 16},
+		{games: 31, rating: 1099, want: 24},
+	}
+	for _, tt := range tests {
+		if got := s.playerCoefficient(tt.games, tt.rating); got != tt.want {
+			t.Errorf("playerCoefficient(%d, %d) = %v, want %v", tt.games, tt.rating, got, tt.want)
+		}
+	}
+}
+
+func TestEloDecay(t *testing.T) {
+	system, err := NewElo(config.Elo{DecayAfterDays: 30, DecayPerWeek: 10, DecayFloor: 1010})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := system.NewState()
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		days int
+		want float64
+	}{
+		{name: "before decay", id: player1.ID, days: 36, want: 1020},
+		{name: "one week", id: player1.ID, days: 37, want: 1010},
+		{name: "floor", id: player1.ID, days: 100, want: 1010},
+		{name: "below floor", id: player2.ID, days: 100, want: 980},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.RatingAt(tt.id, start.AddDate(0, 0, tt.days)); got.Value != tt.want {
+				t.Errorf("RatingAt() = %v, want %v", got.Value, tt.want)
+			}
+		})
+	}
+
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: start.AddDate(0, 0, 100)})
+	a := state.Rating(player1.ID)
+	if a.Value-a.Change != 1010 {
+		t.Errorf("the match must start from the decayed rating, got %v", a.Value-a.Change)
+	}
+}
+
+func TestEloMarginOfVictory(t *testing.T) {
+	system, err := NewElo(config.Elo{MarginOfVictory: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	tests := []struct {
+		name  string
+		score *domain.Score
+		want  float64
+	}{
+		{name: "no score", score: nil, want: 1020},
+		{name: "close win", score: &domain.Score{A: 10, B: 9}, want: 1020},
+		{name: "big win", score: &domain.Score{A: 10, B: 0}, want: 1069},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := system.NewState()
+			state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Score: tt.score})
+			if a := state.Rating(player1.ID); a.Value != tt.want {
+				t.Errorf("Apply() = %v, want %v", a.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestEloTeams(t *testing.T) {
+	system, err := NewElo(config.Elo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	player3 := domain.Player{ID: uuid.New()}
+	player4 := domain.Player{ID: uuid.New()}
+	tests := []struct {
+		name    string
+		matches []domain.Match
+		want    map[uuid.UUID]float64
+	}{
+		{
+			name: "equal teams",
+			matches: []domain.Match{
+				{PlayerA: player1, PlayerB: player3, TeammatesA: []domain.Player{player2}, TeammatesB: []domain.Player{player4}, Winner: player1},
+			},
+			want: map[uuid.UUID]float64{player1.ID: 1020, player2.ID: 1020, player3.ID: 980, player4.ID: 980},
+		},
+		{
+			name: "weaker team wins",
+			matches: []domain.Match{
+				{PlayerA: player1, PlayerB: player3, Winner: player1},
+				{PlayerA: player1, PlayerB: player3, TeammatesA: []domain.Player{player2}, TeammatesB: []domain.Player{player4}, Winner: player3},
+			},
+			want: map[uuid.UUID]float64{player1.ID: 999, player2.ID: 979, player3.ID: 1001, player4.ID: 1021},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := system.NewState()
+			for _, match := range tt.matches {
+				state.Apply(match)
+			}
+			for id, want := range tt.want {
+				if got := state.Rating(id); got.Value != want {
+					t.Errorf("Rating(%s) = %v, want %v", id, got.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGlicko2Periods(t *testing.T) {
+	player1 := domain.Player{ID: uuid.New()}
+	player2 := domain.Player{ID: uuid.New()}
+	player3 := domain.Player{ID: uuid.New()}
+	start := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	system, err := NewGlicko2(config.Glicko2{Period: "day", Timezone: "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := system.NewState()
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player3, Winner: player1, Date: start.Add(time.Hour * 11)})
+	player2Before := state.Rating(player2.ID)
+
+	// the next day starts a new period, the day after it is skipped
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player3, Date: start.Add(time.Hour * 36)})
+	a, b := state.Rating(player1.ID), state.Rating(player3.ID)
+	if a.Change >= 0 {
+		t.Errorf("draw with a weaker player must decrease the rating, got change %v", a.Change)
+	}
+	if b.Change <= 0 {
+		t.Errorf("draw with a stronger player must increase the rating, got change %v", b.Change)
+	}
+	player2After := state.Rating(player2.ID)
+	want := glicko2.Inflate(glicko2FromDomain(player2Before), 1)
+	if player2After.Value != player2Before.Value {
+		t.Errorf("idle player rating changed: %v -> %v", player2Before.Value, player2After.Value)
+	}
+	if player2After.Deviation != want.RD {
+		t.Errorf("idle player deviation = %v, want %v after one skipped period", player2After.Deviation, want.RD)
+	}
+
+	state.Apply(domain.Match{PlayerA: player1, PlayerB: player3, Winner: player3, Date: start.Add(time.Hour * 84)})
+	want = glicko2.Inflate(want, 2)
+	if r := state.Rating(player2.ID); r.Deviation != want.RD {
+		t.Errorf("idle player deviation = %v, want %v after two more periods", r.Deviation, want.RD)
+	}
+}
+
+func TestGlicko2PeriodsElapsed(t *testing.T) {
+	monday := time.Date(2023, 1, 2, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		cfg  config.Glicko2
+		date time.Time
+		want int
+	}{
+		{
+			name: "same day",
+			cfg:  config.Glicko2{Period: "day", Timezone: "UTC"},
+			date: monday.Add(time.Minute * 59),
+			want: 0,
+		},
+		{
+			name: "next day",
+			cfg:  config.Glicko2{Period: "day", Timezone: "UTC"},
+			date: monday.Add(time.Hour),
+			want: 1,
+		},
+		{
+			name: "timezone",
+			cfg:  config.Glicko2{Period: "day", Timezone: "Europe/Moscow"},
+			date: monday.Add(time.Hour),
+			want: 0,
+		},
+		{
+			name: "same week",
+			cfg:  config.Glicko2{Period: "week", Timezone: "UTC"},
+			date: monday.Add(time.Hour * 24 * 6),
+			want: 0,
+		},
+		{
+			name: "two weeks later",
+			cfg:  config.Glicko2{Period: "week", Timezone: "UTC"},
+			date: monday.Add(time.Hour * 24 * 14),
+			want: 2,
+		},
+		{
+			name: "next month",
+			cfg:  config.Glicko2{Period: "month", Timezone: "UTC"},
+			date: monday.Add(time.Hour * 24 * 30),
+			want: 1,
+		},
+		{
+			name: "fixed length",
+			cfg:  config.Glicko2{Period: "72h"},
+			date: monday.Add(time.Hour * 24 * 7),
+			want: 2,
+		},
+		{
+			name: "same event",
+			cfg:  config.Glicko2{Period: "event", EventGap: "2h"},
+			date: monday.Add(time.Hour * 2),
+			want: 0,
+		},
+		{
+			name: "next event",
+			cfg:  config.Glicko2{Period: "event", EventGap: "2h"},
+			date: monday.Add(time.Hour * 24 * 30),
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newGlicko2Periods(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.elapsed(monday, tt.date); got != tt.want {
+				t.Errorf("elapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package rating
+
+import (
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/trueskill"
+
+	"github.com/google/uuid"
+)
+
+type trueSkillSystem struct {
+	params trueskill.Params
+}
+
+func NewTrueSkill() System {
+	return trueSkillSystem{
+		params: trueskill.DefaultParams(),
+	}
+}
+
+func (trueSkillSystem) Name() string {
+	return TrueSkill
+}
+
+func (trueSkillSystem) Title() string {
+	return "TrueSkill"
+}
+
+func (s trueSkillSystem) NewState() State {
+	return &trueSkillState{
+		params:  s.params,
+		players: make(map[uuid.UUID]trueSkillPlayer),
+	}
+}
+
+type trueSkillPlayer struct {
+	rating trueskill.Rating
+	change float64
+}
+
+type trueSkillState struct {
+	params  trueskill.Params
+	players map[uuid.UUID]trueSkillPlayer
+}
+
+func (s *trueSkillState) player(id uuid.UUID) trueSkillPlayer {
+	p, ok := s.players[id]
+	if !ok {
+		return trueSkillPlayer{rating: s.params.NewRating()}
+	}
+	return p
+}
+
+func (s *trueSkillState) Apply(match domain.Match) {
+	sideA := match.SideA()
+	sideB := match.SideB()
+	ratingsA := s.ratings(sideA)
+	ratingsB := s.ratings(sideB)
+
+	var newA, newB []trueskill.Rating
+	switch match.Winner.ID {
+	case match.PlayerB.ID:
+		newB, newA = trueskill.CalculateTeams(ratingsB, ratingsA, false, s.params)
+	default:
+		newA, newB = trueskill.CalculateTeams(ratingsA, ratingsB, match.Winner.ID != match.PlayerA.ID, s.params)
+	}
+	s.update(sideA, newA)
+	s.update(sideB, newB)
+}
+
+// ApplyFFA approximates the multiplayer model by matches between
+// neighbours in the finishing order, played with the ratings before
+// the free-for-all match.
+func (s *trueSkillState) ApplyFFA(match domain.FFAMatch) {
+	players := match.Players()
+	before := s.ratings(players)
+	after := make([]trueskill.Rating, len(players))
+	for i := range match.Placements {
+		r := before[i]
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(players) {
+				continue
+			}
+			switch placeI, placeJ := match.Placements[i].Place, match.Placements[j].Place; {
+			case placeI < placeJ:
+				r, _ = trueskill.Calculate(r, before[j], false, s.params)
+			case placeI > placeJ:
+				_, r = trueskill.Calculate(before[j], r, false, s.params)
+			default:
+				r, _ = trueskill.Calculate(r, before[j], true, s.params)
+			}
+		}
+		after[i] = r
+	}
+	s.update(players, after)
+}
+
+func (s *trueSkillState) ratings(players []domain.Player) []trueskill.Rating {
+	ratings := make([]trueskill.Rating, 0, len(players))
+	for _, player := range players {
+		ratings = append(ratings, s.player(player.ID).rating)
+	}
+	return ratings
+}
+
+func (s *trueSkillState) update(players []domain.Player, ratings []trueskill.Rating) {
+	for i, player := range players {
+		p := s.player(player.ID)
+		p.change = ratings[i].Conservative() - p.rating.Conservative()
+		p.rating = ratings[i]
+		s.players[player.ID] = p
+	}
+}
+
+func (s *trueSkillState) Rating(id uuid.UUID) domain.Rating {
+	return s.player(id).toDomain()
+}
+
+func (s *trueSkillState) Expected(a, b uuid.UUID, _ time.Time) float64 {
+	return trueskill.WinProbability(s.player(a).rating, s.player(b).rating, s.params)
+}
+
+func (s *trueSkillState) Clone() State {
+	players := make(map[uuid.UUID]trueSkillPlayer, len(s.players))
+	for id, p := range s.players {
+		players[id] = p
+	}
+	return &trueSkillState{
+		params:  s.params,
+		players: players,
+	}
+}
+
+func (s *trueSkillState) Regress(carryover float64) {
+	initial := s.params.NewRating()
+	for id, p := range s.players {
+		p.rating.Mu = initial.Mu + (p.rating.Mu-initial.Mu)*carryover
+		p.rating.Sigma = initial.Sigma + (p.rating.Sigma-initial.Sigma)*carryover
+		p.change = 0
+		s.players[id] = p
+	}
+}
+
+// RatingAt returns the current rating, the uncertainty grows only with matches.
+func (s *trueSkillState) RatingAt(id uuid.UUID, _ time.Time) domain.Rating {
+	return s.Rating(id)
+}
+
+// toDomain exposes the conservative skill estimate as the rating value,
+// so players with few games are not ranked above the established ones.
+func (p trueSkillPlayer) toDomain() domain.Rating {
+	return domain.Rating{
+		System:    TrueSkill,
+		Value:     p.rating.Conservative(),
+		Deviation: p.rating.Sigma,
+		Interval: domain.Interval{
+			Min: p.rating.Conservative(),
+			Max: p.rating.Mu + 3*p.rating.Sigma,
+		},
+		Precision: 1,
+		Change:    p.change,
+	}
+}
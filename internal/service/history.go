@@ -0,0 +1,476 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/rating"
+
+	"github.com/google/uuid"
+)
+
+// history is the match history replayed through the rating systems.
+// Matches appended in chronological order are applied incrementally,
+// any other change of the history needs a full replay.
+type history struct {
+	mu sync.RWMutex
+	// discipline is the ID of the discipline the matches belong to.
+	discipline int
+	systems    []rating.System
+	states     []rating.State
+	players    map[uuid.UUID]domain.Player
+	matches    []domain.Match
+	ffaMatches []domain.FFAMatch
+	// last is the order of the last applied match of any kind.
+	last        matchOrder
+	gamesPlayed map[uuid.UUID]int
+	lastPlayed  map[uuid.UUID]time.Time
+	draws       int
+	voided      int
+	results     map[uuid.UUID]map[uuid.UUID]domain.PlayerStats
+	records     map[uuid.UUID]domain.PlayerRecords
+	// achievements are all the unlocked achievements in the order they were unlocked.
+	achievements []domain.Achievement
+}
+
+func newHistory(discipline int, systems []rating.System) *history {
+	h := history{
+		discipline: discipline,
+		systems:    systems,
+	}
+	h.reset(nil)
+	return &h
+}
+
+func (h *history) reset(players []domain.Player) {
+	h.states = make([]rating.State, 0, len(h.systems))
+	for _, system := range h.systems {
+		h.states = append(h.states, system.NewState())
+	}
+	h.players = make(map[uuid.UUID]domain.Player, len(players))
+	for i := range players {
+		h.players[players[i].ID] = players[i]
+	}
+	h.matches = nil
+	h.ffaMatches = nil
+	h.last = matchOrder{}
+	h.gamesPlayed = make(map[uuid.UUID]int)
+	h.lastPlayed = make(map[uuid.UUID]time.Time)
+	h.draws = 0
+	h.voided = 0
+	h.results = make(map[uuid.UUID]map[uuid.UUID]domain.PlayerStats)
+	h.records = make(map[uuid.UUID]domain.PlayerRecords)
+	h.achievements = nil
+}
+
+// replay drops the current state, applies all the matches in their matchOrder and returns
+// the rating history of them. The order of each list is kept, it must be the matchOrder.
+func (h *history) replay(players []domain.Player, matches []domain.Match, ffaMatches []domain.FFAMatch) []domain.RatingHistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.reset(players)
+	h.matches = make([]domain.Match, 0, len(matches))
+	h.ffaMatches = make([]domain.FFAMatch, 0, len(ffaMatches))
+	entries := make([]domain.RatingHistoryEntry, 0, len(matches)*2*len(h.states))
+	merge(matches, ffaMatches, func(match domain.Match) {
+		_, e := h.apply(match)
+		entries = append(entries, e...)
+	}, func(match domain.FFAMatch) {
+		_, e := h.applyFFA(match)
+		entries = append(entries, e...)
+	})
+	return entries
+}
+
+// merge calls apply and applyFFA for the matches in the order of replay.
+func merge(matches []domain.Match, ffaMatches []domain.FFAMatch, apply func(domain.Match), applyFFA func(domain.FFAMatch)) {
+	i, j := 0, 0
+	for i < len(matches) || j < len(ffaMatches) {
+		if j < len(ffaMatches) && (i == len(matches) || ffaOrder(ffaMatches[j]).before(regularOrder(matches[i]))) {
+			applyFFA(ffaMatches[j])
+			j++
+			continue
+		}
+		apply(matches[i])
+		i++
+	}
+}
+
+// season returns the history of the matches played in the season. With a carryover
+// the ratings start from the ratings after the earlier matches regressed toward
+// the initial ones, otherwise all the players start anew.
+func (h *history) season(season domain.Season) *history {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	players := make([]domain.Player, 0, len(h.players))
+	for _, player := range h.players {
+		players = append(players, player)
+	}
+	sh := newHistory(h.discipline, h.systems)
+	sh.reset(players)
+	if season.Carryover > 0 {
+		merge(h.matches, h.ffaMatches, func(match domain.Match) {
+			if match.Date.Before(season.Start) && !match.Voided {
+				for _, state := range sh.states {
+					state.Apply(match)
+				}
+			}
+		}, func(match domain.FFAMatch) {
+			if match.Date.Before(season.Start) {
+				for _, state := range sh.states {
+					state.ApplyFFA(match)
+				}
+			}
+		})
+		for _, state := range sh.states {
+			state.Regress(season.Carryover)
+		}
+	}
+	merge(h.matches, h.ffaMatches, func(match domain.Match) {
+		if season.Contains(match.Date) {
+			sh.apply(match)
+		}
+	}, func(match domain.FFAMatch) {
+		if season.Contains(match.Date) {
+			sh.applyFFA(match)
+		}
+	})
+	return sh
+}
+
+// simulate returns a copy of the history with the hypothetical matches applied at the date
+// and the matches with the rating changes. The copy shares nothing with the history,
+// its head-to-head results and records start empty.
+func (h *history) simulate(matches []domain.Match, date time.Time) (*history, []domain.Match) {
+	h.mu.RLock()
+	sh := newHistory(h.discipline, h.systems)
+	for i, state := range h.states {
+		sh.states[i] = state.Clone()
+	}
+	for id, player := range h.players {
+		sh.players[id] = player
+	}
+	for id, games := range h.gamesPlayed {
+		sh.gamesPlayed[id] = games
+	}
+	for id, last := range h.lastPlayed {
+		sh.lastPlayed[id] = last
+	}
+	sh.last = h.last
+	h.mu.RUnlock()
+
+	calculated := make([]domain.Match, 0, len(matches))
+	for _, match := range matches {
+		match.Date = date
+		match, _ = sh.apply(match)
+		calculated = append(calculated, match)
+	}
+	return sh, calculated
+}
+
+// append applies the match if it goes after the last one in the matchOrder.
+// It returns false if the history has to be replayed instead.
+func (h *history) append(match domain.Match) (domain.Match, []domain.RatingHistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if regularOrder(match).before(h.last) {
+		return domain.Match{}, nil, false
+	}
+	match, entries := h.apply(match)
+	return match, entries, true
+}
+
+// appendFFA applies the free-for-all match like append.
+func (h *history) appendFFA(match domain.FFAMatch) (domain.FFAMatch, []domain.RatingHistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ffaOrder(match).before(h.last) {
+		return domain.FFAMatch{}, nil, false
+	}
+	match, entries := h.applyFFA(match)
+	return match, entries, true
+}
+
+func (h *history) apply(match domain.Match) (domain.Match, []domain.RatingHistoryEntry) {
+	match.DisciplineID = h.discipline
+	match.TeammatesA = append([]domain.Player(nil), match.TeammatesA...)
+	match.TeammatesB = append([]domain.Player(nil), match.TeammatesB...)
+	players := matchPlayers(&match)
+	for _, player := range players {
+		*player = h.players[player.ID]
+	}
+	if match.Winner.ID != uuid.Nil {
+		match.Winner = h.players[match.Winner.ID]
+	}
+	if match.Voided {
+		h.voided++
+		h.matches = append(h.matches, match)
+		h.updateLast(regularOrder(match))
+		return match, nil
+	}
+	records := h.newRecordContext(&match)
+	entries := calculateMatch(&match, h.states, h.gamesPlayed)
+	for _, player := range players {
+		h.lastPlayed[player.ID] = match.Date
+		h.addResult(player.ID, &match)
+	}
+	h.addRecords(&match, records)
+	if match.Winner.ID == uuid.Nil {
+		h.draws++
+	}
+	h.matches = append(h.matches, match)
+	h.updateLast(regularOrder(match))
+	return match, entries
+}
+
+func (h *history) applyFFA(match domain.FFAMatch) (domain.FFAMatch, []domain.RatingHistoryEntry) {
+	match.DisciplineID = h.discipline
+	match.Placements = append([]domain.Placement(nil), match.Placements...)
+	for i := range match.Placements {
+		match.Placements[i].Player = h.players[match.Placements[i].Player.ID]
+	}
+	entries := calculateFFAMatch(&match, h.states, h.gamesPlayed)
+	for _, placement := range match.Placements {
+		h.lastPlayed[placement.Player.ID] = match.Date
+	}
+	h.addFFARecords(&match)
+	// the head-to-head results count the placements as matches between every pair
+	for _, pair := range match.Pairwise() {
+		h.addResult(pair.PlayerA.ID, &pair)
+		h.addResult(pair.PlayerB.ID, &pair)
+	}
+	h.ffaMatches = append(h.ffaMatches, match)
+	h.updateLast(ffaOrder(match))
+	return match, entries
+}
+
+func (h *history) updateLast(order matchOrder) {
+	if h.last.before(order) {
+		h.last = order
+	}
+}
+
+// matchOrder is the order the matches of both kinds are applied in, the same for the replay
+// and the appended matches: by the date, the regular matches before the free-for-all ones
+// played at the same time, then by the ID.
+type matchOrder struct {
+	date time.Time
+	ffa  bool
+	id   int
+}
+
+func regularOrder(match domain.Match) matchOrder {
+	return matchOrder{date: match.Date, id: match.ID}
+}
+
+func ffaOrder(match domain.FFAMatch) matchOrder {
+	return matchOrder{date: match.Date, ffa: true, id: match.ID}
+}
+
+func (o matchOrder) before(other matchOrder) bool {
+	switch {
+	case !o.date.Equal(other.date):
+		return o.date.Before(other.date)
+	case o.ffa != other.ffa:
+		return !o.ffa
+	}
+	return o.id < other.id
+}
+
+func (h *history) addResult(id uuid.UUID, match *domain.Match) {
+	results, ok := h.results[id]
+	if !ok {
+		results = make(map[uuid.UUID]domain.PlayerStats)
+		h.results[id] = results
+	}
+	for _, opponent := range match.Opponents(id) {
+		results[opponent.ID] = calculateResult(id, match, results[opponent.ID])
+	}
+}
+
+// addPlayer adds a new player or replaces the stored one keeping the ratings.
+func (h *history) addPlayer(player domain.Player) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.players[player.ID] = player
+}
+
+// ratings returns all the players with their ratings at the date ordered by rank.
+// Inactive players go last and provisional players go before them, both are not ranked.
+func (h *history) ratings(now time.Time, rules rankRules) []domain.Player {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	players := make([]domain.Player, 0, len(h.players))
+	for _, player := range h.players {
+		player.GamesPlayed = h.gamesPlayed[player.ID]
+		player.LastPlayedAt = h.lastPlayed[player.ID]
+		player.Ratings = currentRatings(h.states, player.ID, now)
+		player.Inactive = player.Deactivated || isInactive(player, now, rules.inactiveAfter)
+		player.Provisional = rules.isProvisional(player)
+		players = append(players, player)
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Inactive != players[j].Inactive {
+			return !players[i].Inactive
+		}
+		if players[i].Provisional != players[j].Provisional {
+			return !players[i].Provisional
+		}
+		a, b := players[i].PrimaryRating().Value, players[j].PrimaryRating().Value
+		if a != b {
+			return a > b
+		}
+		return players[i].Name < players[j].Name
+	})
+	for i := range players {
+		if !players[i].Inactive && !players[i].Provisional {
+			players[i].RatingRank = i + 1
+		}
+	}
+	return players
+}
+
+// standings returns the places of the players who played in the season at the date,
+// deactivated players are skipped.
+func (h *history) standings(season domain.Season, date time.Time) []domain.SeasonStanding {
+	var standings []domain.SeasonStanding
+	for _, player := range h.ratings(date, rankRules{}) {
+		if player.GamesPlayed == 0 || player.Deactivated {
+			continue
+		}
+		standings = append(standings, domain.SeasonStanding{
+			SeasonID:     season.ID,
+			DisciplineID: h.discipline,
+			Player:       player,
+			Place:        len(standings) + 1,
+			GamesPlayed:  player.GamesPlayed,
+			Ratings:      player.Ratings,
+		})
+	}
+	return standings
+}
+
+// rankRules decide which players are ranked on the leaderboards.
+type rankRules struct {
+	// inactiveAfter hides players without matches for longer, zero disables.
+	inactiveAfter time.Duration
+	// provisionalGames is the number of games a player needs to be ranked, zero disables.
+	provisionalGames int
+	// provisionalDeviation is the highest Glicko-2 deviation of a ranked player, zero disables.
+	provisionalDeviation float64
+}
+
+// isProvisional checks if the player has too few games or too uncertain a rating to be ranked.
+// The deviation is checked only if Glicko-2 is configured.
+func (r rankRules) isProvisional(player domain.Player) bool {
+	if player.GamesPlayed < r.provisionalGames {
+		return true
+	}
+	if r.provisionalDeviation == 0 {
+		return false
+	}
+	glicko, ok := player.Rating(rating.Glicko2)
+	return ok && glicko.Deviation > r.provisionalDeviation
+}
+
+// isInactive checks the time since the last match or the registration if there were none.
+func isInactive(player domain.Player, now time.Time, inactiveAfter time.Duration) bool {
+	if inactiveAfter == 0 {
+		return false
+	}
+	lastActivity := player.LastPlayedAt
+	if player.RegisteredAt.After(lastActivity) {
+		lastActivity = player.RegisteredAt
+	}
+	return now.Sub(lastActivity) > inactiveAfter
+}
+
+// predict returns the outcome probabilities of a match between the players
+// at the date and their ratings after every outcome. The outcomes are applied
+// to copies of the rating states, so the history does not change.
+//
+// The systems predict only the expected score, the draw probability is
+// the share of draws in the history, highest for equal players.
+func (h *history) predict(a, b uuid.UUID, date time.Time) []domain.SystemPrediction {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var drawRate float64
+	if played := len(h.matches) - h.voided; played > 0 {
+		drawRate = float64(h.draws) / float64(played)
+	}
+	predictions := make([]domain.SystemPrediction, 0, len(h.states))
+	for i, state := range h.states {
+		expected := state.Expected(a, b, date)
+		draw := drawRate * 2 * math.Min(expected, 1-expected)
+		p := domain.SystemPrediction{
+			System: domain.RatingSystem{
+				Name:  h.systems[i].Name(),
+				Title: h.systems[i].Title(),
+			},
+			WinA: predictOutcome(state, a, b, a, date),
+			Draw: predictOutcome(state, a, b, uuid.Nil, date),
+			WinB: predictOutcome(state, a, b, b, date),
+		}
+		p.WinA.Probability = expected - draw/2
+		p.Draw.Probability = draw
+		p.WinB.Probability = 1 - expected - draw/2
+		predictions = append(predictions, p)
+	}
+	return predictions
+}
+
+func predictOutcome(state rating.State, a, b, winner uuid.UUID, date time.Time) domain.Outcome {
+	clone := state.Clone()
+	clone.Apply(domain.Match{
+		PlayerA: domain.Player{ID: a},
+		PlayerB: domain.Player{ID: b},
+		Winner:  domain.Player{ID: winner},
+		Date:    date,
+	})
+	return domain.Outcome{
+		A: clone.Rating(a),
+		B: clone.Rating(b),
+	}
+}
+
+// listMatches returns the calculated matches in chronological order.
+func (h *history) listMatches() []domain.Match {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	matches := make([]domain.Match, len(h.matches))
+	copy(matches, h.matches)
+	return matches
+}
+
+// listFFAMatches returns the calculated free-for-all matches in chronological order.
+func (h *history) listFFAMatches() []domain.FFAMatch {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	matches := make([]domain.FFAMatch, len(h.ffaMatches))
+	copy(matches, h.ffaMatches)
+	return matches
+}
+
+// playerResults returns the player's results against every opponent.
+func (h *history) playerResults(id uuid.UUID) map[uuid.UUID]domain.PlayerStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	results := make(map[uuid.UUID]domain.PlayerStats, len(h.results[id]))
+	for other, r := range h.results[id] {
+		results[other] = r
+	}
+	return results
+}
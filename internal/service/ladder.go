@@ -0,0 +1,362 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/config"
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/ladder"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLadderReach    = 3
+	defaultLadderDeadline = 7 * 24 * time.Hour
+)
+
+var errLadderDisabled = errors.New("лестница выключена в настройках")
+
+// ladderRules decide who can be challenged on the ladders and for how long.
+type ladderRules struct {
+	enabled bool
+	// reach is how many positions above a player can challenge.
+	reach int
+	// deadline is the time the challenged player has to accept or play the challenge,
+	// the accepted one has to be played in the same time.
+	deadline time.Duration
+}
+
+func newLadderRules(cfg config.Ladder) (ladderRules, error) {
+	if cfg.Reach < 0 {
+		return ladderRules{}, errors.New("ladder reach must be positive")
+	}
+	if cfg.DeadlineDays < 0 {
+		return ladderRules{}, errors.New("ladder deadline_days must be positive")
+	}
+	rules := ladderRules{
+		enabled:  cfg.Enabled,
+		reach:    cfg.Reach,
+		deadline: time.Duration(cfg.DeadlineDays) * 24 * time.Hour,
+	}
+	if rules.reach == 0 {
+		rules.reach = defaultLadderReach
+	}
+	if rules.deadline == 0 {
+		rules.deadline = defaultLadderDeadline
+	}
+	return rules, nil
+}
+
+// LadderEnabled reports whether the players can join the ladders and challenge each other.
+func (s *PlayerService) LadderEnabled() bool {
+	return s.ladderRules.enabled
+}
+
+// Ladder returns the players of the ladder of the discipline by their positions and its challenges, the latest first.
+// A challenge is played while its match is not voided and is between the players of the challenge.
+func (s *PlayerService) Ladder(discipline int) (domain.Ladder, error) {
+	s.refreshCache()
+	return s.ladder(discipline)
+}
+
+// ladder is Ladder without refreshing the cache, for the callers holding mu.
+func (s *PlayerService) ladder(discipline int) (domain.Ladder, error) {
+	if !s.ladderRules.enabled {
+		return domain.Ladder{}, errLadderDisabled
+	}
+	discipline = disciplineID(discipline)
+	h, err := s.history(discipline)
+	if err != nil {
+		return domain.Ladder{}, err
+	}
+	var entries []domain.LadderEntry
+	var challenges []domain.LadderChallenge
+	s.disciplinesMu.RLock()
+	for _, entry := range s.ladderEntries {
+		if entry.DisciplineID == discipline {
+			entries = append(entries, entry)
+		}
+	}
+	for _, challenge := range s.challenges {
+		if challenge.DisciplineID == discipline {
+			challenges = append(challenges, challenge)
+		}
+	}
+	s.disciplinesMu.RUnlock()
+
+	player := func(p domain.Player) domain.Player {
+		if found, err := s.cachedPlayer(discipline, p.ID); err == nil {
+			return found
+		}
+		return p
+	}
+	for i := range entries {
+		entries[i].Player = player(entries[i].Player)
+	}
+	matches := make(map[int]domain.Match)
+	for _, match := range h.listMatches() {
+		matches[match.ID] = match
+	}
+	now := time.Now()
+	for i := range challenges {
+		c := &challenges[i]
+		c.Challenger, c.Challenged = player(c.Challenger), player(c.Challenged)
+		match, ok := matches[c.MatchID]
+		if ok && !match.Voided && !match.IsTeamMatch() && match.PlayerA.ID != match.PlayerB.ID &&
+			c.Has(match.PlayerA.ID) && c.Has(match.PlayerB.ID) {
+			c.Match = &match
+		}
+		c.Status = ladder.Status(*c, now)
+	}
+
+	l := domain.Ladder{
+		DisciplineID: discipline,
+		Reach:        s.ladderRules.reach,
+		Rungs:        ladder.Rungs(entries, challenges),
+	}
+	for i := len(challenges) - 1; i >= 0; i-- {
+		l.Challenges = append(l.Challenges, challenges[i])
+	}
+	return l, nil
+}
+
+// JoinLadder puts the player at the bottom of the ladder of the discipline.
+func (s *PlayerService) JoinLadder(discipline int, id uuid.UUID) error {
+	s.refreshCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.ladder(discipline)
+	if err != nil {
+		return err
+	}
+	player, err := s.cachedPlayer(l.DisciplineID, id)
+	if err != nil {
+		return err
+	}
+	if player.Deactivated {
+		return errors.New("игрок " + player.Name + " деактивирован")
+	}
+	if l.Position(id) != 0 {
+		return errors.New("игрок " + player.Name + " уже стоит на лестнице")
+	}
+	entry := domain.LadderEntry{
+		DisciplineID: l.DisciplineID,
+		Player:       domain.Player{ID: id},
+		JoinedAt:     time.Now(),
+	}
+	err = s.ladderStorage.AddLadderEntry(entry)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.Lock()
+	s.ladderEntries = append(s.ladderEntries, entry)
+	s.disciplinesMu.Unlock()
+	return nil
+}
+
+// Challenge saves the challenge of the player to a player up to the reach of the ladder above.
+// Neither of them may have another open challenge.
+func (s *PlayerService) Challenge(discipline int, challenger, challenged uuid.UUID) (domain.LadderChallenge, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if challenger == challenged {
+		return domain.LadderChallenge{}, errors.New("нельзя вызвать самого себя")
+	}
+	l, err := s.ladder(discipline)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	var players [2]domain.Player
+	for i, id := range []uuid.UUID{challenger, challenged} {
+		players[i], err = s.cachedPlayer(l.DisciplineID, id)
+		if err != nil {
+			return domain.LadderChallenge{}, err
+		}
+		if l.Position(id) == 0 {
+			return domain.LadderChallenge{}, errors.New("игрок " + players[i].Name + " не стоит на лестнице")
+		}
+		if players[i].Deactivated {
+			return domain.LadderChallenge{}, errors.New("игрок " + players[i].Name + " деактивирован")
+		}
+		for _, c := range l.Open() {
+			if c.Has(id) {
+				return domain.LadderChallenge{}, errors.New("у игрока " + players[i].Name + " уже есть незавершённый вызов")
+			}
+		}
+	}
+	distance := l.Position(challenger) - l.Position(challenged)
+	if distance <= 0 {
+		return domain.LadderChallenge{}, errors.New("можно вызвать только игрока выше на лестнице")
+	}
+	if distance > l.Reach {
+		return domain.LadderChallenge{}, errors.New("можно вызвать игрока не больше чем на " + strconv.Itoa(l.Reach) + " позиции выше")
+	}
+	now := time.Now()
+	created, err := s.ladderStorage.AddChallenge(domain.LadderChallenge{
+		DisciplineID: l.DisciplineID,
+		Challenger:   domain.Player{ID: challenger},
+		Challenged:   domain.Player{ID: challenged},
+		CreatedAt:    now,
+		Deadline:     now.Add(s.ladderRules.deadline),
+	})
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	s.disciplinesMu.Lock()
+	s.challenges = append(s.challenges, created)
+	s.disciplinesMu.Unlock()
+	return s.challenge(created.ID)
+}
+
+// AcceptChallenge saves that the challenged player has accepted the challenge,
+// so it expires instead of being forfeited after the deadline.
+func (s *PlayerService) AcceptChallenge(id int) (domain.LadderChallenge, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.challenge(id)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	if c.Status == domain.LadderAccepted {
+		return domain.LadderChallenge{}, errors.New("вызов " + strconv.Itoa(id) + " уже принят")
+	}
+	if !c.Open() {
+		return domain.LadderChallenge{}, errChallengeClosed(c)
+	}
+	c.AcceptedAt, c.Status = time.Now(), domain.LadderAccepted
+	return c, s.updateChallenge(c)
+}
+
+// CancelChallenge cancels the open challenge, it does not change the ladder.
+func (s *PlayerService) CancelChallenge(id int) (domain.LadderChallenge, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.challenge(id)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	if !c.Open() {
+		return domain.LadderChallenge{}, errChallengeClosed(c)
+	}
+	c.CancelledAt, c.Status = time.Now(), domain.LadderCancelled
+	return c, s.updateChallenge(c)
+}
+
+// RecordLadderMatch saves the match as the result of the open challenge between its players,
+// so it changes the ratings like any other match.
+func (s *PlayerService) RecordLadderMatch(id int, match domain.Match) (domain.Match, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	created, unlocked, err := s.recordLadderMatch(id, match)
+	s.unlockAndNotify(unlocked)
+	return created, err
+}
+
+// recordLadderMatch is RecordLadderMatch holding mu, it also returns the unlocked achievements.
+func (s *PlayerService) recordLadderMatch(id int, match domain.Match) (domain.Match, []domain.Achievement, error) {
+	c, err := s.challenge(id)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	if !c.Open() {
+		return domain.Match{}, nil, errChallengeClosed(c)
+	}
+	if match.IsTeamMatch() || match.PlayerA.ID == match.PlayerB.ID || !c.Has(match.PlayerA.ID) || !c.Has(match.PlayerB.ID) {
+		return domain.Match{}, nil, errors.New("по вызову играют " + c.Challenger.Name + " и " + c.Challenged.Name)
+	}
+	match.DisciplineID = c.DisciplineID
+	created, unlocked, err := s.createMatch(match)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	c.MatchID = created.ID
+	if c.AcceptedAt.IsZero() {
+		// the voided match does not make the played challenge forfeited
+		c.AcceptedAt = time.Now()
+	}
+	return created, unlocked, s.updateChallenge(c)
+}
+
+// LadderChallenge returns the challenge with the players, the match and the status.
+func (s *PlayerService) LadderChallenge(id int) (domain.LadderChallenge, error) {
+	s.refreshCache()
+	return s.challenge(id)
+}
+
+func errChallengeClosed(c domain.LadderChallenge) error {
+	return errors.New("вызов " + strconv.Itoa(c.ID) + " завершён: " + c.Status.Title())
+}
+
+// challenge is LadderChallenge without refreshing the cache, for the callers holding mu.
+func (s *PlayerService) challenge(id int) (domain.LadderChallenge, error) {
+	discipline := 0
+	s.disciplinesMu.RLock()
+	for _, c := range s.challenges {
+		if c.ID == id {
+			discipline = c.DisciplineID
+		}
+	}
+	s.disciplinesMu.RUnlock()
+
+	notFound := errors.New("вызов " + strconv.Itoa(id) + " не найден")
+	if discipline == 0 {
+		return domain.LadderChallenge{}, notFound
+	}
+	l, err := s.ladder(discipline)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	for _, c := range l.Challenges {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return domain.LadderChallenge{}, notFound
+}
+
+func (s *PlayerService) updateChallenge(c domain.LadderChallenge) error {
+	err := s.ladderStorage.UpdateChallenge(c)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.Lock()
+	defer s.disciplinesMu.Unlock()
+
+	for i := range s.challenges {
+		if s.challenges[i].ID == c.ID {
+			s.challenges[i].AcceptedAt = c.AcceptedAt
+			s.challenges[i].CancelledAt = c.CancelledAt
+			s.challenges[i].MatchID = c.MatchID
+		}
+	}
+	return nil
+}
+
+// sharedLadder returns the discipline both players are on the ladder of.
+func (s *PlayerService) sharedLadder(a, b uuid.UUID) (domain.Discipline, bool) {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	joined := make(map[int]int)
+	for _, entry := range s.ladderEntries {
+		if entry.Player.ID == a || entry.Player.ID == b {
+			joined[entry.DisciplineID]++
+		}
+	}
+	for _, discipline := range s.disciplines {
+		if joined[discipline.ID] == 2 {
+			return discipline, true
+		}
+	}
+	return domain.Discipline{}, false
+}
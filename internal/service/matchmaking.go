@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxMatchmakingPlayers limits the search of the best pairings, it takes 2^n steps.
+const maxMatchmakingPlayers = 16
+
+// rematchPenalty is added to the cost of the game of the players who have just met,
+// it decreases to zero after domain.RecentRematch of their games.
+const rematchPenalty = 1.0
+
+// Matchmaking proposes the games between the present players of the discipline. The pairings
+// maximize the total quality of the games, the chances to win in the primary rating system close to equal,
+// and avoid the players who have met recently. With an odd number of players one of them rests.
+func (s *PlayerService) Matchmaking(discipline int, players []uuid.UUID) (domain.Matchmaking, error) {
+	present := make([]uuid.UUID, 0, len(players))
+	for _, id := range players {
+		if !containsUUID(present, id) {
+			present = append(present, id)
+		}
+	}
+	if len(present) < 2 {
+		return domain.Matchmaking{}, errors.New("нужно хотя бы два игрока")
+	}
+	if len(present) > maxMatchmakingPlayers {
+		return domain.Matchmaking{}, errors.New("можно подобрать пары не больше чем для " + strconv.Itoa(maxMatchmakingPlayers) + " игроков")
+	}
+	h, err := s.history(discipline)
+	if err != nil {
+		return domain.Matchmaking{}, err
+	}
+	byID := make(map[uuid.UUID]domain.Player, len(present))
+	for _, id := range present {
+		player, err := s.Get(discipline, id)
+		if err != nil {
+			return domain.Matchmaking{}, err
+		}
+		byID[id] = player
+	}
+	since := rematches(h.listMatches(), present)
+
+	now := time.Now()
+	n := len(present)
+	pairings := make([][]domain.Pairing, n)
+	costs := make([][]float64, n)
+	for i := range present {
+		pairings[i] = make([]domain.Pairing, n)
+		costs[i] = make([]float64, n)
+		for j := i + 1; j < n; j++ {
+			a, b := present[i], present[j]
+			p := domain.Pairing{
+				Prediction: domain.Prediction{
+					PlayerA: byID[a],
+					PlayerB: byID[b],
+					Systems: h.predict(a, b, now),
+				},
+				Since: -1,
+			}
+			if games, ok := since[[2]uuid.UUID{a, b}]; ok {
+				p.Since = games
+			}
+			outcomes := p.Outcomes()
+			p.Quality = 1 - math.Abs(outcomes.WinA.Probability-outcomes.WinB.Probability)
+			pairings[i][j] = p
+			costs[i][j] = pairingCost(p)
+		}
+	}
+
+	var matchmaking domain.Matchmaking
+	pairs, resting := bestPairs(costs)
+	for _, pair := range pairs {
+		matchmaking.Pairings = append(matchmaking.Pairings, pairings[pair[0]][pair[1]])
+	}
+	if resting >= 0 {
+		player := byID[present[resting]]
+		matchmaking.Resting = &player
+	}
+	sort.SliceStable(matchmaking.Pairings, func(i, j int) bool {
+		return pairingCost(matchmaking.Pairings[i]) < pairingCost(matchmaking.Pairings[j])
+	})
+	return matchmaking, nil
+}
+
+// pairingCost is the loss of the quality of the game and the penalty for the recent rematch.
+func pairingCost(p domain.Pairing) float64 {
+	cost := 1 - p.Quality
+	if p.Rematch() {
+		cost += rematchPenalty * float64(domain.RecentRematch-p.Since) / domain.RecentRematch
+	}
+	return cost
+}
+
+// rematches returns the number of the games the present players have played since they last met,
+// the less of the two, by the pairs of their IDs in both orders.
+func rematches(matches []domain.Match, present []uuid.UUID) map[[2]uuid.UUID]int {
+	since := make(map[[2]uuid.UUID]int)
+	played := make(map[uuid.UUID]int, len(present))
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		if match.Voided {
+			continue
+		}
+		for _, a := range present {
+			if !match.Played(a) {
+				continue
+			}
+			for _, b := range match.Opponents(a) {
+				if !containsUUID(present, b.ID) {
+					continue
+				}
+				if _, ok := since[[2]uuid.UUID{a, b.ID}]; !ok {
+					games := played[a]
+					if played[b.ID] < games {
+						games = played[b.ID]
+					}
+					since[[2]uuid.UUID{a, b.ID}] = games
+					since[[2]uuid.UUID{b.ID, a}] = games
+				}
+			}
+		}
+		for _, player := range append(match.SideA(), match.SideB()...) {
+			played[player.ID]++
+		}
+	}
+	return since
+}
+
+// bestPairs returns the pairs of the indexes with the least total cost, the costs are given for i < j.
+// With an odd number of players the one resting is returned, -1 otherwise.
+// It tries all the pairings of the first unpaired player, remembering the best ones for every set of the paired.
+func bestPairs(costs [][]float64) ([][2]int, int) {
+	n := len(costs)
+	full := 1<<n - 1
+	type step struct {
+		cost float64
+		// next is the player paired with the first unpaired one, the first one rests if it is the same.
+		next int
+		done bool
+	}
+	steps := make([]step, full+1)
+	var solve func(paired int) float64
+	solve = func(paired int) float64 {
+		if paired == full {
+			return 0
+		}
+		if steps[paired].done {
+			return steps[paired].cost
+		}
+		first := 0
+		for paired&(1<<first) != 0 {
+			first++
+		}
+		best := step{cost: math.Inf(1), done: true}
+		// with an odd number of players the paired ones are odd once someone rests
+		if n%2 == 1 && bits.OnesCount(uint(paired))%2 == 0 {
+			if cost := solve(paired | 1<<first); cost < best.cost {
+				best.cost, best.next = cost, first
+			}
+		}
+		for j := first + 1; j < n; j++ {
+			if paired&(1<<j) != 0 {
+				continue
+			}
+			if cost := costs[first][j] + solve(paired|1<<first|1<<j); cost < best.cost {
+				best.cost, best.next = cost, j
+			}
+		}
+		steps[paired] = best
+		return best.cost
+	}
+	solve(0)
+
+	var pairs [][2]int
+	resting := -1
+	for paired := 0; paired != full; {
+		first := 0
+		for paired&(1<<first) != 0 {
+			first++
+		}
+		next := steps[paired].next
+		if next == first {
+			resting = first
+		} else {
+			pairs = append(pairs, [2]int{first, next})
+		}
+		paired |= 1<<first | 1<<next
+	}
+	return pairs, resting
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// recordContext is the state before a match the records of its players depend on.
+type recordContext struct {
+	// oneOnOne is set for the matches without teammates, only they count as upsets.
+	oneOnOne bool
+	// expected is the expected score of side A by the primary rating system.
+	expected float64
+	// leader is the player ranked first before the match, nil if no winner needs it.
+	leader uuid.UUID
+}
+
+// newRecordContext returns the state before the match, it must be called before the match is applied.
+func (h *history) newRecordContext(match *domain.Match) recordContext {
+	var ctx recordContext
+	if match.Winner.ID == uuid.Nil {
+		return ctx
+	}
+	if len(match.TeammatesA) == 0 && len(match.TeammatesB) == 0 {
+		ctx.oneOnOne = true
+		ctx.expected = h.states[0].Expected(match.PlayerA.ID, match.PlayerB.ID, match.Date)
+	}
+	for _, player := range matchPlayers(match) {
+		if match.Won(player.ID) && !unlocked(h.records[player.ID], domain.AchievementBeatLeader) {
+			ctx.leader = h.leader(match)
+			break
+		}
+	}
+	return ctx
+}
+
+// leader returns the active player with the highest primary rating before the match.
+func (h *history) leader(match *domain.Match) uuid.UUID {
+	var leader domain.Player
+	var best float64
+	for id := range h.gamesPlayed {
+		player := h.players[id]
+		if player.Deactivated {
+			continue
+		}
+		value := h.states[0].RatingAt(id, match.Date).Value
+		if leader.ID == uuid.Nil || value > best || value == best && player.Name < leader.Name {
+			leader, best = player, value
+		}
+	}
+	return leader.ID
+}
+
+// addRecords updates the records of the players of the applied match and awards the achievements.
+func (h *history) addRecords(match *domain.Match, ctx recordContext) {
+	for _, player := range matchPlayers(match) {
+		r := h.records[player.ID]
+		award := func(kind domain.AchievementKind) {
+			h.award(&r, domain.Achievement{
+				Kind:         kind,
+				Player:       *player,
+				DisciplineID: h.discipline,
+				Date:         match.Date,
+				MatchID:      match.ID,
+			})
+		}
+		won := match.Won(player.ID)
+		switch {
+		case match.Winner.ID == uuid.Nil:
+			r.CurrentStreak = 0
+		case won:
+			if r.CurrentStreak < 0 {
+				r.CurrentStreak = 0
+			}
+			r.CurrentStreak++
+			if r.CurrentStreak > r.LongestWinStreak {
+				r.LongestWinStreak = r.CurrentStreak
+			}
+		default:
+			if r.CurrentStreak > 0 {
+				r.CurrentStreak = 0
+			}
+			r.CurrentStreak--
+		}
+		if won {
+			award(domain.AchievementFirstWin)
+			if r.CurrentStreak >= 5 {
+				award(domain.AchievementWinStreak5)
+			}
+			if r.CurrentStreak >= 10 {
+				award(domain.AchievementWinStreak10)
+			}
+			if ctx.oneOnOne {
+				opponent, probability := match.PlayerB, ctx.expected
+				if player.ID == match.PlayerB.ID {
+					opponent, probability = match.PlayerA, 1-ctx.expected
+				}
+				if r.BiggestUpset.MatchID == 0 || probability < r.BiggestUpset.Probability {
+					r.BiggestUpset = domain.Upset{
+						MatchID:     match.ID,
+						Opponent:    opponent,
+						Date:        match.Date,
+						Probability: probability,
+					}
+				}
+				if probability <= domain.UpsetProbability {
+					award(domain.AchievementUpset)
+				}
+			}
+			if ctx.leader != uuid.Nil && match.Played(ctx.leader) && !match.Won(ctx.leader) {
+				award(domain.AchievementBeatLeader)
+			}
+		}
+		h.addPlayedRecords(&r, *player, match.Date, award)
+		h.records[player.ID] = r
+	}
+}
+
+// addFFARecords updates the records of the players of the applied free-for-all match.
+func (h *history) addFFARecords(match *domain.FFAMatch) {
+	for _, placement := range match.Placements {
+		player := placement.Player
+		r := h.records[player.ID]
+		h.addPlayedRecords(&r, player, match.Date, func(kind domain.AchievementKind) {
+			h.award(&r, domain.Achievement{
+				Kind:         kind,
+				Player:       player,
+				DisciplineID: h.discipline,
+				Date:         match.Date,
+				FFAMatchID:   match.ID,
+			})
+		})
+		h.records[player.ID] = r
+	}
+}
+
+// addPlayedRecords updates the records depending only on the games played and the rating after a match.
+func (h *history) addPlayedRecords(r *domain.PlayerRecords, player domain.Player, date time.Time, award func(domain.AchievementKind)) {
+	if len(player.Ratings) > 0 && (r.PeakRatingDate.IsZero() || player.Ratings[0].Value > r.PeakRating.Value) {
+		r.PeakRating = player.Ratings[0]
+		r.PeakRatingDate = date
+	}
+	if h.gamesPlayed[player.ID] >= 100 {
+		award(domain.AchievementGames100)
+	}
+}
+
+// award adds the achievement unless the player has already unlocked it.
+func (h *history) award(r *domain.PlayerRecords, achievement domain.Achievement) {
+	if unlocked(*r, achievement.Kind) {
+		return
+	}
+	r.Achievements = append(r.Achievements, achievement)
+	h.achievements = append(h.achievements, achievement)
+}
+
+func unlocked(r domain.PlayerRecords, kind domain.AchievementKind) bool {
+	for _, achievement := range r.Achievements {
+		if achievement.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// playerRecords returns the records of the player.
+func (h *history) playerRecords(id uuid.UUID) domain.PlayerRecords {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r := h.records[id]
+	r.Achievements = append([]domain.Achievement(nil), r.Achievements...)
+	return r
+}
+
+// listAchievements returns all the achievements in the order they were unlocked.
+func (h *history) listAchievements() []domain.Achievement {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	achievements := make([]domain.Achievement, len(h.achievements))
+	copy(achievements, h.achievements)
+	return achievements
+}
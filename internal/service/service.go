@@ -3,216 +3,474 @@ package service
 import (
 	"errors"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goserg/ratingserver/internal/cache/mem"
+	"github.com/goserg/ratingserver/internal/config"
 	"github.com/goserg/ratingserver/internal/domain"
-	"github.com/goserg/ratingserver/internal/elo"
 	"github.com/goserg/ratingserver/internal/normalize"
+	"github.com/goserg/ratingserver/internal/rating"
 	"github.com/goserg/ratingserver/internal/storage"
 
-	glicko "github.com/zelenin/go-glicko2"
-
 	"github.com/google/uuid"
 )
 
+// cacheTTL is how often the ratings depending on the current time are recalculated.
+const cacheTTL = time.Hour
+
 type PlayerService struct {
-	playerStorage storage.PlayerStorage
-	matchStorage  storage.MatchStorage
-	cache         *mem.Cache
+	playerStorage     storage.PlayerStorage
+	matchStorage      storage.MatchStorage
+	disciplineStorage storage.DisciplineStorage
+	seasonStorage     storage.SeasonStorage
+	tournamentStorage storage.TournamentStorage
+	ladderStorage     storage.LadderStorage
+	cache             *mem.Cache
+	systems           []rating.System
+	rankRules         rankRules
+	ladderRules       ladderRules
+	// cacheUpdatedAt is the time of the last cache update in Unix nanoseconds.
+	cacheUpdatedAt atomic.Int64
+
+	// mu serializes changes of the storage and the history,
+	// so they are applied in the same order.
+	mu sync.Mutex
+
+	// disciplinesMu guards disciplines, seasons, tournaments, ladders and the histories, they are replaced only under mu.
+	disciplinesMu sync.RWMutex
+	disciplines   []domain.Discipline
+	// histories holds the match history of every discipline by its ID.
+	histories map[int]*history
+	seasons   []domain.Season
+	// currentSeason is zero between the seasons.
+	currentSeason domain.Season
+	// seasonHistories holds the history of the current season of every discipline by its ID.
+	seasonHistories map[int]*history
+	// tournaments are ordered by ID as stored, the games of the brackets are resolved on request.
+	tournaments []domain.Tournament
+	// ladderEntries are ordered by the time the players joined, challenges by ID as stored.
+	ladderEntries []domain.LadderEntry
+	challenges    []domain.LadderChallenge
+
+	// mergeHooks are called before two players are merged, guarded by mu.
+	mergeHooks []func(from, to uuid.UUID) error
+	// achievementHooks are called with the achievements unlocked by a new match, guarded by mu.
+	achievementHooks []func(achievements []domain.Achievement)
 }
 
-func New(playerStorage storage.PlayerStorage, matchStorage storage.MatchStorage, cache *mem.Cache) (*PlayerService, error) {
+func New(
+	playerStorage storage.PlayerStorage,
+	matchStorage storage.MatchStorage,
+	disciplineStorage storage.DisciplineStorage,
+	seasonStorage storage.SeasonStorage,
+	tournamentStorage storage.TournamentStorage,
+	ladderStorage storage.LadderStorage,
+	cache *mem.Cache,
+	systems []rating.System,
+	cfg config.Rating,
+) (*PlayerService, error) {
+	if len(systems) == 0 {
+		return nil, errors.New("no rating systems configured")
+	}
+	if cfg.InactiveAfterDays < 0 {
+		return nil, errors.New("inactive_after_days must be positive")
+	}
+	if cfg.ProvisionalGames < 0 {
+		return nil, errors.New("provisional_games must be positive")
+	}
+	if cfg.ProvisionalDeviation < 0 {
+		return nil, errors.New("provisional_deviation must be positive")
+	}
+	ladder, err := newLadderRules(cfg.Ladder)
+	if err != nil {
+		return nil, err
+	}
 	p := PlayerService{
-		playerStorage: playerStorage,
-		matchStorage:  matchStorage,
-		cache:         cache,
+		playerStorage:     playerStorage,
+		matchStorage:      matchStorage,
+		disciplineStorage: disciplineStorage,
+		seasonStorage:     seasonStorage,
+		tournamentStorage: tournamentStorage,
+		ladderStorage:     ladderStorage,
+		cache:             cache,
+		systems:           systems,
+		rankRules: rankRules{
+			inactiveAfter:        time.Duration(cfg.InactiveAfterDays) * time.Hour * 24,
+			provisionalGames:     cfg.ProvisionalGames,
+			provisionalDeviation: cfg.ProvisionalDeviation,
+		},
+		ladderRules: ladder,
 	}
-	return &p, p.updateCache()
+	return &p, p.reload()
 }
 
-func (s *PlayerService) updateCache() error {
-	players, err := s.getRatings()
+// reload replays the whole match history of every discipline from the storage.
+func (s *PlayerService) reload() error {
+	disciplines, err := s.disciplineStorage.ListDisciplines()
 	if err != nil {
 		return err
 	}
-	glicko2, err := s.getGlicko2()
+	if len(disciplines) == 0 {
+		return errors.New("no disciplines found")
+	}
+	matches, err := s.matchStorage.ListMatches()
 	if err != nil {
 		return err
 	}
-	for i := range players {
-		players[i].Glicko2Rating = glicko2[players[i].ID].Glicko2Rating
+	ffaMatches, err := s.matchStorage.ListFFAMatches()
+	if err != nil {
+		return err
 	}
-	s.cache.Update(players)
-	return nil
-}
-
-func (s *PlayerService) getGlicko2() (map[uuid.UUID]domain.Player, error) {
-	matches, err := s.matchStorage.ListMatches()
+	players, err := s.playerStorage.ListPlayers()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if len(matches) == 0 {
-		return make(map[uuid.UUID]domain.Player), nil
+	seasons, err := s.seasonStorage.ListSeasons()
+	if err != nil {
+		return err
 	}
-	ps, err := s.playerStorage.ListPlayers()
+	tournaments, err := s.tournamentStorage.ListTournaments()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	players := make(map[uuid.UUID]*glicko.Player)
-	for i := range ps {
-		players[ps[i].ID] = glicko.NewDefaultPlayer()
+	ladderEntries, err := s.ladderStorage.ListLadderEntries()
+	if err != nil {
+		return err
 	}
-	period := glicko.NewRatingPeriod()
-	for _, player := range players {
-		period.AddPlayer(player)
+	challenges, err := s.ladderStorage.ListChallenges()
+	if err != nil {
+		return err
 	}
-	start := matches[0].Date
+	matchesByDiscipline := make(map[int][]domain.Match, len(disciplines))
 	for i := range matches {
-		if matches[i].Date.After(start.Add(time.Hour * 24)) {
-			start = matches[i].Date
-			period.Calculate()
-			period = glicko.NewRatingPeriod()
-			for _, player := range players {
-				period.AddPlayer(player)
+		id := disciplineID(matches[i].DisciplineID)
+		matchesByDiscipline[id] = append(matchesByDiscipline[id], matches[i])
+	}
+	ffaByDiscipline := make(map[int][]domain.FFAMatch, len(disciplines))
+	for i := range ffaMatches {
+		id := disciplineID(ffaMatches[i].DisciplineID)
+		ffaByDiscipline[id] = append(ffaByDiscipline[id], ffaMatches[i])
+	}
+	histories := make(map[int]*history, len(disciplines))
+	var entries []domain.RatingHistoryEntry
+	for _, discipline := range disciplines {
+		h := newHistory(discipline.ID, s.systems)
+		entries = append(entries, h.replay(players, matchesByDiscipline[discipline.ID], ffaByDiscipline[discipline.ID])...)
+		histories[discipline.ID] = h
+	}
+	err = s.matchStorage.ReplaceRatingHistory(entries)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.Lock()
+	s.disciplines = disciplines
+	s.histories = histories
+	s.seasons = seasons
+	s.tournaments = tournaments
+	s.ladderEntries = ladderEntries
+	s.challenges = challenges
+	s.disciplinesMu.Unlock()
+
+	err = s.updateSeasons(time.Now())
+	s.updateCache()
+	return err
+}
+
+// updateSeasons archives the finished seasons and replays the current season of every discipline.
+func (s *PlayerService) updateSeasons(now time.Time) error {
+	s.disciplinesMu.RLock()
+	seasons := make([]domain.Season, len(s.seasons))
+	copy(seasons, s.seasons)
+	histories := make(map[int]*history, len(s.histories))
+	for id, h := range s.histories {
+		histories[id] = h
+	}
+	s.disciplinesMu.RUnlock()
+
+	var current domain.Season
+	var archiveErr error
+	for i := range seasons {
+		switch {
+		case seasons[i].Contains(now):
+			current = seasons[i]
+		case seasons[i].Finished(now) && !seasons[i].Archived():
+			archived, err := s.archiveSeason(seasons[i], histories, now)
+			if err != nil {
+				archiveErr = errors.Join(archiveErr, err)
+				continue
 			}
+			seasons[i] = archived
 		}
-		pA := matches[i].PlayerA
-		pB := matches[i].PlayerB
-		period.AddMatch(players[pA.ID], players[pB.ID], findResult(matches[i].Winner.ID, pA.ID, pB.ID))
-	}
-	period.Calculate()
-	for i := range ps {
-		fillGlickoStats(&ps[i], players[ps[i].ID])
 	}
-	playersMap := make(map[uuid.UUID]domain.Player)
-	for i := range ps {
-		playersMap[ps[i].ID] = ps[i]
+	seasonHistories := make(map[int]*history, len(histories))
+	if current.ID != 0 {
+		for id, h := range histories {
+			seasonHistories[id] = h.season(current)
+		}
 	}
-	return playersMap, nil
+
+	s.disciplinesMu.Lock()
+	s.seasons = seasons
+	s.currentSeason = current
+	s.seasonHistories = seasonHistories
+	s.disciplinesMu.Unlock()
+	return archiveErr
 }
 
-func findResult(winnerID, pAID, pBID uuid.UUID) glicko.MatchResult {
-	w := glicko.MATCH_RESULT_DRAW
-	switch winnerID {
-	case pAID:
-		w = glicko.MATCH_RESULT_WIN
-	case pBID:
-		w = glicko.MATCH_RESULT_LOSS
+// archiveSeason saves the final standings of the season in every discipline.
+func (s *PlayerService) archiveSeason(season domain.Season, histories map[int]*history, now time.Time) (domain.Season, error) {
+	var standings []domain.SeasonStanding
+	for _, h := range histories {
+		standings = append(standings, h.season(season).standings(season, season.End)...)
 	}
-	return w
+	season.ArchivedAt = now
+	err := s.seasonStorage.ArchiveSeason(season, standings)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	return season, nil
 }
 
-func fillGlickoStats(player *domain.Player, gPlayer *glicko.Player) {
-	player.Glicko2Rating.Rating = gPlayer.Rating().R()
-	player.Glicko2Rating.RatingDeviation = gPlayer.Rating().Rd()
-	player.Glicko2Rating.Sigma = gPlayer.Rating().Sigma()
-	player.Glicko2Rating.Interval.Min, player.Glicko2Rating.Interval.Max = gPlayer.Rating().ConfidenceInterval()
+// seasonsOutdated reports whether a season has started or finished since the last update.
+func (s *PlayerService) seasonsOutdated(now time.Time) bool {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	if s.currentSeason.ID != 0 && !s.currentSeason.Contains(now) {
+		return true
+	}
+	for _, season := range s.seasons {
+		if season.Contains(now) && season.ID != s.currentSeason.ID {
+			return true
+		}
+		if season.Finished(now) && !season.Archived() {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *PlayerService) getRatings() ([]domain.Player, error) {
-	matches, err := s.matchStorage.ListMatches()
-	if err != nil {
-		return nil, err
+// disciplineID returns the ID of the default discipline for matches recorded without one.
+func disciplineID(id int) int {
+	if id == 0 {
+		return domain.DefaultDisciplineID
 	}
-	players, err := s.playerStorage.ListPlayers()
-	if err != nil {
-		return nil, err
+	return id
+}
+
+// history returns the match history of the discipline.
+func (s *PlayerService) history(discipline int) (*history, error) {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	h, ok := s.histories[disciplineID(discipline)]
+	if !ok {
+		return nil, errors.New("дисциплина не найдена")
 	}
-	playersMap := make(map[uuid.UUID]*domain.Player)
-	for _, player := range players {
-		p := player
-		playersMap[player.ID] = &p
+	return h, nil
+}
+
+func (s *PlayerService) updateCache() {
+	now := time.Now()
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	for id, h := range s.histories {
+		s.cache.Update(id, h.ratings(now, s.rankRules))
 	}
-	matches = calculateMatches(matches)
-	for i := range matches {
-		playersMap[matches[i].PlayerA.ID].EloRating = matches[i].PlayerA.EloRating
-		playersMap[matches[i].PlayerB.ID].EloRating = matches[i].PlayerB.EloRating
-		playersMap[matches[i].PlayerA.ID].GamesPlayed = matches[i].PlayerA.GamesPlayed
-		playersMap[matches[i].PlayerB.ID].GamesPlayed = matches[i].PlayerB.GamesPlayed
-		playersMap[matches[i].PlayerA.ID].RatingChange = matches[i].PlayerA.RatingChange
-		playersMap[matches[i].PlayerB.ID].RatingChange = matches[i].PlayerB.RatingChange
+	s.cacheUpdatedAt.Store(now.UnixNano())
+}
+
+// refreshCache updates the cache if it is older than cacheTTL,
+// so inactivity is taken into account without new matches.
+func (s *PlayerService) refreshCache() {
+	if time.Since(time.Unix(0, s.cacheUpdatedAt.Load())) < cacheTTL {
+		return
 	}
-	for i := range players {
-		players[i] = *playersMap[players[i].ID]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(time.Unix(0, s.cacheUpdatedAt.Load())) < cacheTTL {
+		return
 	}
-	sort.SliceStable(players, func(i, j int) bool {
-		return players[i].EloRating > players[j].EloRating
-	})
-	for i := range players {
-		players[i].RatingRank = i + 1
+	if now := time.Now(); s.seasonsOutdated(now) {
+		// a failed archive is retried on the next refresh
+		_ = s.updateSeasons(now)
 	}
-	return players, nil
+	s.updateCache()
 }
 
-func calculatePlayerCoefficient(n int, rating int) int {
-	if n <= 30 {
-		return 40
+// Disciplines returns all the disciplines, the default one first.
+func (s *PlayerService) Disciplines() []domain.Discipline {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	disciplines := make([]domain.Discipline, len(s.disciplines))
+	copy(disciplines, s.disciplines)
+	return disciplines
+}
+
+// Discipline finds the discipline by name, the empty name is the default discipline.
+func (s *PlayerService) Discipline(name string) (domain.Discipline, error) {
+	disciplines := s.Disciplines()
+	if name == "" {
+		return disciplines[0], nil
 	}
-	if rating >= 2400 {
-		return 10
+	name = normalize.Name(name)
+	for _, discipline := range disciplines {
+		if normalize.Name(discipline.Name) == name {
+			return discipline, nil
+		}
 	}
-	return 20
+	return domain.Discipline{}, errors.New("дисциплина " + name + " не найдена")
 }
 
-func calculatePoints(a domain.Player, winner domain.Player) (elo.Points, elo.Points) {
-	if winner.ID == uuid.Nil {
-		return elo.Draw, elo.Draw
+// CreateDiscipline adds a discipline without matches, all the players take part in it.
+func (s *PlayerService) CreateDiscipline(name string) (domain.Discipline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return domain.Discipline{}, errors.New("название дисциплины должно быть одним словом")
+	}
+	if _, err := s.Discipline(name); err == nil {
+		return domain.Discipline{}, errors.New("дисциплина " + name + " уже существует")
+	}
+	players, err := s.playerStorage.ListPlayers()
+	if err != nil {
+		return domain.Discipline{}, err
+	}
+	for _, player := range players {
+		if normalize.Name(player.Name) == normalize.Name(name) {
+			return domain.Discipline{}, errors.New("название " + name + " занято игроком")
+		}
 	}
-	if winner.ID == a.ID {
-		return elo.Win, elo.Lose
+	discipline, err := s.disciplineStorage.AddDiscipline(domain.Discipline{
+		Name:      name,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return domain.Discipline{}, err
+	}
+	h := newHistory(discipline.ID, s.systems)
+	h.replay(players, nil, nil)
+
+	s.disciplinesMu.Lock()
+	s.disciplines = append(s.disciplines, discipline)
+	s.histories[discipline.ID] = h
+	if s.currentSeason.ID != 0 {
+		s.seasonHistories[discipline.ID] = h.season(s.currentSeason)
 	}
-	return elo.Lose, elo.Win
+	s.disciplinesMu.Unlock()
+
+	s.updateCache()
+	return discipline, nil
 }
 
-func (s *PlayerService) GetMatches() ([]domain.Match, error) {
-	matches, err := s.matchStorage.ListMatches()
+func currentRatings(states []rating.State, id uuid.UUID, date time.Time) []domain.Rating {
+	ratings := make([]domain.Rating, 0, len(states))
+	for _, state := range states {
+		ratings = append(ratings, state.RatingAt(id, date))
+	}
+	return ratings
+}
+
+// GetMatches returns matches of the discipline, the latest first.
+func (s *PlayerService) GetMatches(discipline int) ([]domain.Match, error) {
+	h, err := s.history(discipline)
 	if err != nil {
 		return nil, err
 	}
-	matches = calculateMatches(matches)
+	matches := h.listMatches()
 	reverse(matches)
 	return matches, nil
 }
 
-func calculateMatches(matches []domain.Match) []domain.Match {
-	players := make(map[uuid.UUID]domain.Player)
-	for i := range matches {
-		calculateMatch(&matches[i], players)
+// calculateMatch applies the match to the rating states, fills the players
+// of the match with their ratings after it and returns the rating changes.
+func calculateMatch(match *domain.Match, states []rating.State, gamesPlayed map[uuid.UUID]int) []domain.RatingHistoryEntry {
+	players := matchPlayers(match)
+	before := make([][]domain.Rating, len(players))
+	for i, player := range players {
+		before[i] = make([]domain.Rating, 0, len(states))
+		for _, state := range states {
+			before[i] = append(before[i], state.RatingAt(player.ID, match.Date))
+		}
+	}
+	for _, state := range states {
+		state.Apply(*match)
 	}
-	return matches
+	entries := make([]domain.RatingHistoryEntry, 0, len(players)*len(states))
+	for i, player := range players {
+		player.Ratings = make([]domain.Rating, 0, len(states))
+		for j, state := range states {
+			after := state.Rating(player.ID)
+			player.Ratings = append(player.Ratings, after)
+			entry := newRatingHistoryEntry(player.ID, match.Date, before[i][j], after)
+			entry.MatchID = match.ID
+			entry.DisciplineID = match.DisciplineID
+			entries = append(entries, entry)
+		}
+		gamesPlayed[player.ID]++
+		player.GamesPlayed = gamesPlayed[player.ID]
+	}
+	return entries
 }
 
-func calculateMatch(match *domain.Match, players map[uuid.UUID]domain.Player) {
-	playerA, ok := players[match.PlayerA.ID]
-	if !ok {
-		playerA = match.PlayerA
-		playerA.EloRating = 1000
+// calculateFFAMatch is calculateMatch for free-for-all matches.
+func calculateFFAMatch(match *domain.FFAMatch, states []rating.State, gamesPlayed map[uuid.UUID]int) []domain.RatingHistoryEntry {
+	before := make([][]domain.Rating, len(match.Placements))
+	for i, placement := range match.Placements {
+		before[i] = currentRatings(states, placement.Player.ID, match.Date)
 	}
-	playerB, ok := players[match.PlayerB.ID]
-	if !ok {
-		playerB = match.PlayerB
-		playerB.EloRating = 1000
+	for _, state := range states {
+		state.ApplyFFA(*match)
 	}
-	pointsA, pointsB := calculatePoints(playerA, match.Winner)
-	playerCoefficientA := calculatePlayerCoefficient(playerA.GamesPlayed, playerA.EloRating)
-	playerCoefficientB := calculatePlayerCoefficient(playerB.GamesPlayed, playerB.EloRating)
-
-	newRatingA := elo.Calculate(playerA.EloRating, playerB.EloRating, playerCoefficientA, pointsA)
-	newRatingB := elo.Calculate(playerB.EloRating, playerA.EloRating, playerCoefficientB, pointsB)
-
-	playerA.RatingChange = newRatingA - playerA.EloRating
-	playerA.EloRating = newRatingA
-	playerB.RatingChange = newRatingB - playerB.EloRating
-	playerB.EloRating = newRatingB
+	entries := make([]domain.RatingHistoryEntry, 0, len(match.Placements)*len(states))
+	for i := range match.Placements {
+		player := &match.Placements[i].Player
+		player.Ratings = make([]domain.Rating, 0, len(states))
+		for j, state := range states {
+			after := state.Rating(player.ID)
+			player.Ratings = append(player.Ratings, after)
+			entry := newRatingHistoryEntry(player.ID, match.Date, before[i][j], after)
+			entry.FFAMatchID = match.ID
+			entry.DisciplineID = match.DisciplineID
+			entries = append(entries, entry)
+		}
+		gamesPlayed[player.ID]++
+		player.GamesPlayed = gamesPlayed[player.ID]
+	}
+	return entries
+}
 
-	playerA.GamesPlayed++
-	playerB.GamesPlayed++
+// matchPlayers returns pointers to all the players of the match.
+func matchPlayers(match *domain.Match) []*domain.Player {
+	players := []*domain.Player{&match.PlayerA, &match.PlayerB}
+	for i := range match.TeammatesA {
+		players = append(players, &match.TeammatesA[i])
+	}
+	for i := range match.TeammatesB {
+		players = append(players, &match.TeammatesB[i])
+	}
+	return players
+}
 
-	match.PlayerA = playerA
-	match.PlayerB = playerB
-	players[playerA.ID] = playerA
-	players[playerB.ID] = playerB
+func newRatingHistoryEntry(playerID uuid.UUID, date time.Time, before, after domain.Rating) domain.RatingHistoryEntry {
+	return domain.RatingHistoryEntry{
+		PlayerID:        playerID,
+		System:          after.System,
+		RatingBefore:    before.Value,
+		RatingAfter:     after.Value,
+		DeviationBefore: before.Deviation,
+		DeviationAfter:  after.Deviation,
+		Date:            date,
+	}
 }
 
 func reverse(m []domain.Match) {
@@ -221,122 +479,1067 @@ func reverse(m []domain.Match) {
 	}
 }
 
-func (s *PlayerService) CreateMatch(match domain.Match) (m domain.Match, err error) {
-	defer func() {
+func (s *PlayerService) CreateMatch(match domain.Match) (domain.Match, error) {
+	s.mu.Lock()
+	created, unlocked, err := s.createMatch(match)
+	s.unlockAndNotify(unlocked)
+	return created, err
+}
+
+// createMatch saves the match and calculates the ratings, mu must be held. It returns the achievements
+// unlocked by the match, the hooks are called with them by unlockAndNotify.
+func (s *PlayerService) createMatch(match domain.Match) (domain.Match, []domain.Achievement, error) {
+	err := validateMatch(match)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	match.Date, err = playedAt(match.Date)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	match.DisciplineID = disciplineID(match.DisciplineID)
+	h, err := s.history(match.DisciplineID)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	achievements := h.listAchievements()
+	created, err := s.matchStorage.Create(match)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	calculated, entries, ok := h.append(created)
+	if !ok {
+		// the match was played before the last one, the later matches are recalculated
+		err = s.reload()
 		if err != nil {
-			return
+			return domain.Match{}, nil, err
 		}
-		err = s.updateCache()
-	}()
+		calculated, err = s.GetMatch(created.ID)
+		return calculated, s.unlockedAchievements(created.DisciplineID, achievements), err
+	}
+	err = s.matchStorage.SaveRatingHistory(entries)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	s.addToSeason(created.DisciplineID, created.Date, func(sh *history) bool {
+		_, _, ok := sh.append(created)
+		return ok
+	})
+	s.updateCache()
+	return calculated, s.unlockedAchievements(created.DisciplineID, achievements), nil
+}
 
+func validateMatch(match domain.Match) error {
 	if match.PlayerA.ID == match.PlayerB.ID {
-		return domain.Match{}, errors.New("должно участвовать два разных игрока")
+		return errors.New("должно участвовать два разных игрока")
+	}
+	return match.Validate()
+}
+
+// playedAt returns the date the match was played at, the current time if it is not set.
+func playedAt(date time.Time) (time.Time, error) {
+	now := time.Now()
+	if date.IsZero() {
+		return now, nil
+	}
+	if date.After(now) {
+		return time.Time{}, errors.New("матч не может быть сыгран в будущем")
 	}
-	return s.matchStorage.Create(match)
+	return date, nil
 }
 
-func (s *PlayerService) Get(playerID uuid.UUID) (domain.Player, error) {
-	rating, err := s.getRatings()
+// ImportMatches saves the matches played earlier and recalculates the ratings.
+// The matches get new IDs unless they are set.
+func (s *PlayerService) ImportMatches(matches []domain.Match) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range matches {
+		err := validateMatch(matches[i])
+		if err != nil {
+			return err
+		}
+		if matches[i].Date.IsZero() {
+			return errors.New("не указана дата матча " + matches[i].Summary())
+		}
+		matches[i].Date, err = playedAt(matches[i].Date)
+		if err != nil {
+			return err
+		}
+		matches[i].DisciplineID = disciplineID(matches[i].DisciplineID)
+	}
+	err := s.matchStorage.ImportMatches(matches)
 	if err != nil {
-		return domain.Player{}, err
+		return err
 	}
-	for i := range rating {
-		if rating[i].ID == playerID {
-			return rating[i], nil
+	return s.reload()
+}
+
+// GetMatch returns the match of any discipline with the ratings of the players after it.
+func (s *PlayerService) GetMatch(id int) (domain.Match, error) {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	for _, h := range s.histories {
+		for _, match := range h.listMatches() {
+			if match.ID == id {
+				return match, nil
+			}
 		}
 	}
-	return domain.Player{}, errors.New("not found")
+	return domain.Match{}, errors.New("матч " + strconv.Itoa(id) + " не найден")
+}
+
+// EditMatch replaces the players, the result and the date of the match with the same ID
+// and recalculates the ratings. The discipline and the void flag are kept, so is the date if it is not set.
+func (s *PlayerService) EditMatch(author string, match domain.Match) (domain.Match, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.GetMatch(match.ID)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	err = validateMatch(match)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	if match.Date.IsZero() {
+		match.Date = old.Date
+	}
+	match.Date, err = playedAt(match.Date)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	match.DisciplineID = old.DisciplineID
+	match.Voided = old.Voided
+	err = s.matchStorage.UpdateMatch(match, newMatchChange(domain.MatchEdited, author, old, match.Summary()))
+	if err != nil {
+		return domain.Match{}, err
+	}
+	err = s.reload()
+	if err != nil {
+		return domain.Match{}, err
+	}
+	return s.GetMatch(match.ID)
 }
 
-func (s *PlayerService) GetRatings() []domain.Player {
-	return s.cache.GetRatings()
+// VoidMatch excludes the match from the ratings or restores it.
+func (s *PlayerService) VoidMatch(author string, id int, voided bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match, err := s.GetMatch(id)
+	if err != nil {
+		return err
+	}
+	if match.Voided == voided {
+		return nil
+	}
+	old := match
+	match.Voided = voided
+	action := domain.MatchVoided
+	if !voided {
+		action = domain.MatchRestored
+	}
+	err = s.matchStorage.UpdateMatch(match, newMatchChange(action, author, old, match.Summary()))
+	if err != nil {
+		return err
+	}
+	return s.reload()
 }
 
-func (s *PlayerService) GetPlayerData(id uuid.UUID) (domain.PlayerCardData, error) {
-	var data domain.PlayerCardData
+// DeleteMatch removes the match and recalculates the ratings.
+func (s *PlayerService) DeleteMatch(author string, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	matches, err := s.matchStorage.ListMatches()
+	match, err := s.GetMatch(id)
+	if err != nil {
+		return err
+	}
+	err = s.matchStorage.DeleteMatch(id, newMatchChange(domain.MatchDeleted, author, match, ""))
 	if err != nil {
-		return domain.PlayerCardData{}, err
+		return err
+	}
+	return s.reload()
+}
+
+func newMatchChange(action, author string, old domain.Match, after string) domain.MatchChange {
+	return domain.MatchChange{
+		MatchID: old.ID,
+		Action:  action,
+		Author:  author,
+		Before:  old.Summary(),
+		After:   after,
+		Date:    time.Now(),
+	}
+}
+
+// GetMatchChanges returns the audit trail of the matches, the latest first.
+func (s *PlayerService) GetMatchChanges() ([]domain.MatchChange, error) {
+	return s.matchStorage.ListMatchChanges()
+}
+
+// addToSeason applies a new match to the current season with add,
+// the season is replayed if the match changes its starting ratings
+// or add fails.
+func (s *PlayerService) addToSeason(discipline int, date time.Time, add func(sh *history) bool) {
+	s.disciplinesMu.Lock()
+	defer s.disciplinesMu.Unlock()
+
+	season := s.currentSeason
+	if season.ID == 0 || !date.Before(season.End) {
+		return
+	}
+	sh := s.seasonHistories[discipline]
+	if season.Contains(date) && add(sh) {
+		return
+	}
+	if season.Contains(date) || season.Carryover > 0 {
+		s.seasonHistories[discipline] = s.histories[discipline].season(season)
 	}
-	results := make(map[uuid.UUID]domain.PlayerStats)
-	players, err := s.getRatings()
+}
+
+// CreateFFAMatch records a free-for-all match, the placements are ordered by place.
+func (s *PlayerService) CreateFFAMatch(match domain.FFAMatch) (domain.FFAMatch, error) {
+	s.mu.Lock()
+	created, unlocked, err := s.createFFAMatch(match)
+	s.unlockAndNotify(unlocked)
+	return created, err
+}
+
+// createFFAMatch is CreateFFAMatch holding mu, it also returns the unlocked achievements.
+func (s *PlayerService) createFFAMatch(match domain.FFAMatch) (domain.FFAMatch, []domain.Achievement, error) {
+	err := match.Validate()
 	if err != nil {
-		return domain.PlayerCardData{}, err
+		return domain.FFAMatch{}, nil, err
 	}
+	match.Date, err = playedAt(match.Date)
+	if err != nil {
+		return domain.FFAMatch{}, nil, err
+	}
+	match.DisciplineID = disciplineID(match.DisciplineID)
+	h, err := s.history(match.DisciplineID)
+	if err != nil {
+		return domain.FFAMatch{}, nil, err
+	}
+	achievements := h.listAchievements()
+	created, err := s.matchStorage.CreateFFA(match)
+	if err != nil {
+		return domain.FFAMatch{}, nil, err
+	}
+	calculated, entries, ok := h.appendFFA(created)
+	if !ok {
+		err = s.reload()
+		if err != nil {
+			return domain.FFAMatch{}, nil, err
+		}
+		return created, s.unlockedAchievements(created.DisciplineID, achievements), nil
+	}
+	err = s.matchStorage.SaveRatingHistory(entries)
+	if err != nil {
+		return domain.FFAMatch{}, nil, err
+	}
+	s.addToSeason(created.DisciplineID, created.Date, func(sh *history) bool {
+		_, _, ok := sh.appendFFA(created)
+		return ok
+	})
+	s.updateCache()
+	return calculated, s.unlockedAchievements(created.DisciplineID, achievements), nil
+}
+
+// OnAchievementsUnlocked adds a function called with the achievements unlocked by a new match.
+func (s *PlayerService) OnAchievementsUnlocked(fn func(achievements []domain.Achievement)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.achievementHooks = append(s.achievementHooks, fn)
+}
+
+// unlockedAchievements returns the achievements of the discipline unlocked since the achievements
+// before were listed, nil if there are no hooks to call with them. A backdated match may move
+// an achievement to another match, only the achievements the players had not had before are new.
+func (s *PlayerService) unlockedAchievements(discipline int, before []domain.Achievement) []domain.Achievement {
+	if len(s.achievementHooks) == 0 {
+		return nil
+	}
+	h, err := s.history(discipline)
+	if err != nil {
+		return nil
+	}
+	type key struct {
+		player uuid.UUID
+		kind   domain.AchievementKind
+	}
+	known := make(map[key]bool, len(before))
+	for _, achievement := range before {
+		known[key{achievement.Player.ID, achievement.Kind}] = true
+	}
+	var unlocked []domain.Achievement
+	for _, achievement := range h.listAchievements() {
+		if !known[key{achievement.Player.ID, achievement.Kind}] {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked
+}
+
+// unlockAndNotify releases mu and then calls the achievement hooks with the unlocked achievements,
+// so a slow hook does not hold up the other changes.
+func (s *PlayerService) unlockAndNotify(unlocked []domain.Achievement) {
+	hooks := s.achievementHooks
+	s.mu.Unlock()
+
+	if len(unlocked) == 0 {
+		return
+	}
+	for _, hook := range hooks {
+		hook(unlocked)
+	}
+}
+
+// GetRecords returns the streaks, the records and the achievements of the player in the discipline.
+func (s *PlayerService) GetRecords(discipline int, id uuid.UUID) (domain.PlayerRecords, error) {
+	h, err := s.history(discipline)
+	if err != nil {
+		return domain.PlayerRecords{}, err
+	}
+	return h.playerRecords(id), nil
+}
+
+// GetFFAMatches returns free-for-all matches of the discipline, the latest first.
+func (s *PlayerService) GetFFAMatches(discipline int) ([]domain.FFAMatch, error) {
+	h, err := s.history(discipline)
+	if err != nil {
+		return nil, err
+	}
+	matches := h.listFFAMatches()
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	return matches, nil
+}
+
+// PlacementsByNames returns the placements of the players in the finishing
+// order. Every group is a name or names of tied players joined by "=".
+func (s *PlayerService) PlacementsByNames(discipline int, groups []string) ([]domain.Placement, error) {
+	var placements []domain.Placement
+	for _, group := range groups {
+		place := len(placements) + 1
+		for _, name := range strings.Split(group, tieSeparator) {
+			name = strings.TrimSpace(name)
+			player, err := s.GetByName(discipline, normalize.Name(name))
+			if err != nil {
+				return nil, errors.New("игрок " + name + " не найден")
+			}
+			placements = append(placements, domain.Placement{Player: player, Place: place})
+		}
+	}
+	return placements, nil
+}
+
+// tieSeparator joins the names of the players sharing a place.
+const tieSeparator = "="
+
+func (s *PlayerService) Get(discipline int, playerID uuid.UUID) (domain.Player, error) {
+	s.refreshCache()
+	return s.cachedPlayer(discipline, playerID)
+}
+
+// cachedPlayer is Get without refreshing the cache, for the callers holding mu.
+func (s *PlayerService) cachedPlayer(discipline int, playerID uuid.UUID) (domain.Player, error) {
+	player, ok := s.cache.GetPlayer(disciplineID(discipline), playerID)
+	if !ok {
+		return domain.Player{}, errors.New("not found")
+	}
+	return player, nil
+}
+
+// GetRatings returns active players of the discipline ordered by rank, the provisional ones last.
+func (s *PlayerService) GetRatings(discipline int) []domain.Player {
+	s.refreshCache()
+	return activePlayers(s.cache.GetRatings(disciplineID(discipline)))
+}
+
+// ConservativeLeaderboard ranks the players by the lower bound
+// of the Glicko-2 confidence interval, R − 2·RD, so the players
+// with uncertain ratings do not top the leaderboard.
+const ConservativeLeaderboard = "conservative"
+
+// Leaderboards returns the rating systems the players can be ranked by, the primary one first.
+// The conservative leaderboard is available if Glicko-2 is configured.
+func (s *PlayerService) Leaderboards() []domain.RatingSystem {
+	leaderboards := s.RatingSystems()
+	if s.hasSystem(rating.Glicko2) {
+		leaderboards = append(leaderboards, domain.RatingSystem{
+			Name:  ConservativeLeaderboard,
+			Title: "Glicko-2 консервативный",
+		})
+	}
+	return leaderboards
+}
+
+// Leaderboard returns active players of the discipline ranked by the score of the leaderboard,
+// the provisional ones go last without a rank.
+func (s *PlayerService) Leaderboard(discipline int, name string) ([]domain.LeaderboardEntry, error) {
+	score := func(player domain.Player) domain.Rating {
+		r, _ := player.Rating(name)
+		return r
+	}
+	switch {
+	case name == ConservativeLeaderboard && s.hasSystem(rating.Glicko2):
+		score = func(player domain.Player) domain.Rating {
+			r, _ := player.Rating(rating.Glicko2)
+			// the score is the bound itself, the interval is shown with the Glicko-2 rating
+			r.System = ConservativeLeaderboard
+			r.Value = r.Interval.Min
+			r.Deviation = 0
+			return r
+		}
+	case !s.hasSystem(name):
+		return nil, errors.New("рейтинг " + name + " не найден")
+	}
+	s.refreshCache()
+	players := activePlayers(s.cache.GetRatings(disciplineID(discipline)))
+	entries := make([]domain.LeaderboardEntry, 0, len(players))
 	for _, player := range players {
-		if player.ID == id {
-			data.Player = player
-			continue
+		entries = append(entries, domain.LeaderboardEntry{Player: player, Score: score(player)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Player.Provisional != b.Player.Provisional {
+			return !a.Player.Provisional
+		}
+		if a.Score.Value != b.Score.Value {
+			return a.Score.Value > b.Score.Value
+		}
+		return a.Player.Name < b.Player.Name
+	})
+	for i := range entries {
+		if !entries[i].Player.Provisional {
+			entries[i].Rank = i + 1
 		}
-		results[player.ID] = domain.PlayerStats{Player: player}
 	}
-	for i := range matches {
-		if matches[i].PlayerA.ID != id && matches[i].PlayerB.ID != id {
+	return entries, nil
+}
+
+func activePlayers(players []domain.Player) []domain.Player {
+	active := players[:0]
+	for i := range players {
+		if !players[i].Inactive {
+			active = append(active, players[i])
+		}
+	}
+	return active
+}
+
+// RatingSystems returns configured rating systems, the primary one first.
+func (s *PlayerService) RatingSystems() []domain.RatingSystem {
+	systems := make([]domain.RatingSystem, 0, len(s.systems))
+	for _, system := range s.systems {
+		systems = append(systems, domain.RatingSystem{
+			Name:  system.Name(),
+			Title: system.Title(),
+		})
+	}
+	return systems
+}
+
+func (s *PlayerService) hasSystem(name string) bool {
+	for _, system := range s.systems {
+		if system.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPlayerData returns the player with the results against every opponent in the discipline.
+func (s *PlayerService) GetPlayerData(discipline int, id uuid.UUID) (domain.PlayerCardData, error) {
+	var data domain.PlayerCardData
+
+	h, err := s.history(discipline)
+	if err != nil {
+		return data, err
+	}
+	s.refreshCache()
+	results := h.playerResults(id)
+	for _, player := range s.cache.GetRatings(disciplineID(discipline)) {
+		if player.ID == id {
+			data.Player = player
 			continue
 		}
-		other, r := s.calculateResult(id, &matches[i], results)
-		results[other] = r
+		r := results[player.ID]
+		r.Player = player
+		results[player.ID] = r
 	}
 	data.Results = results
+	data.Records = h.playerRecords(id)
 	return data, nil
 }
 
-func (s *PlayerService) calculateResult(id uuid.UUID, match *domain.Match, results map[uuid.UUID]domain.PlayerStats) (otherID uuid.UUID, result domain.PlayerStats) {
-	var this, other *domain.Player
-	if match.PlayerA.ID == id {
-		this = &match.PlayerA
-		other = &match.PlayerB
-	} else {
-		this = &match.PlayerB
-		other = &match.PlayerA
-	}
-	r := results[other.ID]
+// calculateResult adds the match to the player's results against the opponent.
+func calculateResult(id uuid.UUID, match *domain.Match, r domain.PlayerStats) domain.PlayerStats {
 	switch {
-	case this.ID == match.Winner.ID:
+	case match.Winner.ID == uuid.Nil:
+		r.Draws++
+	case match.Won(id):
 		r.Wins++
-	case other.ID == match.Winner.ID:
-		r.Loses++
 	default:
-		r.Draws++
+		r.Loses++
 	}
-	return other.ID, r
+	return r
 }
 
-func (s *PlayerService) GetByName(name string) (domain.Player, error) {
-	player, ok := s.cache.GetPlayerByName(name)
-	if !ok {
-		return domain.Player{}, errors.New("not found")
+// GetRatingHistory returns the player's rating changes in the discipline in chronological order.
+func (s *PlayerService) GetRatingHistory(discipline int, id uuid.UUID) ([]domain.RatingHistoryEntry, error) {
+	return s.matchStorage.ListRatingHistory(domain.RatingHistoryFilter{
+		PlayerID:     id,
+		DisciplineID: disciplineID(discipline),
+	})
+}
+
+// RatingChart returns the Elo and Glicko-2 ratings of the player in the discipline before the first match
+// and after every match. The systems which are not configured are skipped.
+func (s *PlayerService) RatingChart(discipline int, id uuid.UUID) ([]domain.RatingSeries, error) {
+	entries, err := s.GetRatingHistory(discipline, id)
+	if err != nil {
+		return nil, err
 	}
-	return player, nil
+	var series []domain.RatingSeries
+	for _, system := range s.systems {
+		// the other systems are on a different scale
+		if system.Name() != rating.Elo && system.Name() != rating.Glicko2 {
+			continue
+		}
+		rs := domain.RatingSeries{
+			System: domain.RatingSystem{Name: system.Name(), Title: system.Title()},
+		}
+		for _, entry := range entries {
+			if entry.System != system.Name() {
+				continue
+			}
+			if len(rs.Points) == 0 {
+				rs.Points = append(rs.Points, domain.RatingPoint{
+					Date:      entry.Date,
+					Value:     entry.RatingBefore,
+					Deviation: entry.DeviationBefore,
+				})
+			}
+			rs.Points = append(rs.Points, domain.RatingPoint{
+				Date:      entry.Date,
+				Value:     entry.RatingAfter,
+				Deviation: entry.DeviationAfter,
+			})
+		}
+		series = append(series, rs)
+	}
+	return series, nil
 }
 
-func (s *PlayerService) CreatePlayer(name string) (player domain.Player, err error) {
-	defer func() {
-		if err != nil {
-			return
+// GetRatingsAt returns the player's ratings in the discipline after the last match played not later than date.
+func (s *PlayerService) GetRatingsAt(discipline int, id uuid.UUID, date time.Time) ([]domain.Rating, error) {
+	entries, err := s.matchStorage.ListRatingHistory(domain.RatingHistoryFilter{
+		PlayerID:     id,
+		DisciplineID: disciplineID(discipline),
+		To:           date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ratings := make([]domain.Rating, 0, len(s.systems))
+	for _, system := range s.systems {
+		r := system.NewState().Rating(id)
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].System == system.Name() {
+				r.Value = entries[i].RatingAfter
+				r.Deviation = entries[i].DeviationAfter
+				break
+			}
 		}
-		err = s.updateCache()
-	}()
+		ratings = append(ratings, r)
+	}
+	return ratings, nil
+}
 
-	players, err := s.playerStorage.ListPlayers()
+// Predict returns the outcome probabilities of a match in the discipline between
+// the players played now and their ratings after every outcome in every rating system.
+func (s *PlayerService) Predict(discipline int, a, b uuid.UUID) (domain.Prediction, error) {
+	if a == b {
+		return domain.Prediction{}, errors.New("должно участвовать два разных игрока")
+	}
+	h, err := s.history(discipline)
 	if err != nil {
-		return domain.Player{}, err
+		return domain.Prediction{}, err
 	}
-	normName := normalize.Name(name)
+	playerA, err := s.Get(discipline, a)
+	if err != nil {
+		return domain.Prediction{}, err
+	}
+	playerB, err := s.Get(discipline, b)
+	if err != nil {
+		return domain.Prediction{}, err
+	}
+	return domain.Prediction{
+		PlayerA: playerA,
+		PlayerB: playerB,
+		Systems: h.predict(a, b, time.Now()),
+	}, nil
+}
+
+// Simulate applies the hypothetical matches of the discipline after the recorded ones
+// in the given order and returns the leaderboard with the rating changes, nothing is saved.
+func (s *PlayerService) Simulate(discipline int, matches []domain.Match) (domain.Simulation, error) {
+	if len(matches) == 0 {
+		return domain.Simulation{}, errors.New("нужно указать хотя бы один матч")
+	}
+	for _, match := range matches {
+		if err := validateMatch(match); err != nil {
+			return domain.Simulation{}, err
+		}
+	}
+	h, err := s.history(discipline)
+	if err != nil {
+		return domain.Simulation{}, err
+	}
+	now := time.Now()
+	before := make(map[uuid.UUID]domain.Player)
+	for _, player := range h.ratings(now, s.rankRules) {
+		before[player.ID] = player
+	}
+	sh, calculated := h.simulate(matches, now)
+	simulation := domain.Simulation{Matches: calculated}
+	for _, player := range sh.ratings(now, s.rankRules) {
+		if player.Inactive {
+			continue
+		}
+		simulation.Players = append(simulation.Players, domain.SimulatedPlayer{
+			Before: before[player.ID],
+			After:  player,
+		})
+	}
+	return simulation, nil
+}
+
+// HeadToHead compares the players of the discipline: their matches against each other,
+// the totals, the trends of the primary ratings and the current prediction.
+func (s *PlayerService) HeadToHead(discipline int, a, b uuid.UUID) (domain.HeadToHead, error) {
+	prediction, err := s.Predict(discipline, a, b)
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+	h, err := s.history(discipline)
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+	h2h := domain.HeadToHead{
+		PlayerA:    prediction.PlayerA,
+		PlayerB:    prediction.PlayerB,
+		Prediction: prediction,
+	}
+	matches := h.listMatches()
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		if !match.Played(a) || !containsID(match.Opponents(a), b) {
+			continue
+		}
+		h2h.Matches = append(h2h.Matches, match)
+		if match.Voided {
+			continue
+		}
+		switch {
+		case match.Winner.ID == uuid.Nil:
+			h2h.Draws++
+		case match.Won(a):
+			h2h.WinsA++
+		default:
+			h2h.WinsB++
+		}
+	}
+	ffaMatches := h.listFFAMatches()
+	for i := len(ffaMatches) - 1; i >= 0; i-- {
+		match := ffaMatches[i]
+		placeA, placeB := match.Place(a), match.Place(b)
+		if placeA == 0 || placeB == 0 {
+			continue
+		}
+		h2h.FFAMatches = append(h2h.FFAMatches, match)
+		switch {
+		case placeA < placeB:
+			h2h.WinsA++
+		case placeA > placeB:
+			h2h.WinsB++
+		default:
+			h2h.Draws++
+		}
+	}
+	h2h.TrendA, err = s.ratingTrend(discipline, a)
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+	h2h.TrendB, err = s.ratingTrend(discipline, b)
+	if err != nil {
+		return domain.HeadToHead{}, err
+	}
+	return h2h, nil
+}
+
+func containsID(players []domain.Player, id uuid.UUID) bool {
 	for _, player := range players {
-		if normalize.Name(player.Name) == normName {
-			return domain.Player{}, errors.New("player " + player.Name + " already exists")
+		if player.ID == id {
+			return true
 		}
 	}
+	return false
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ratingTrend returns the primary rating of the player before the first match and after every match.
+func (s *PlayerService) ratingTrend(discipline int, id uuid.UUID) ([]domain.RatingPoint, error) {
+	entries, err := s.matchStorage.ListRatingHistory(domain.RatingHistoryFilter{
+		PlayerID:     id,
+		DisciplineID: disciplineID(discipline),
+		System:       s.systems[0].Name(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	trend := make([]domain.RatingPoint, 0, len(entries)+1)
+	trend = append(trend, domain.RatingPoint{Date: entries[0].Date, Value: entries[0].RatingBefore})
+	for _, entry := range entries {
+		trend = append(trend, domain.RatingPoint{Date: entry.Date, Value: entry.RatingAfter})
+	}
+	return trend, nil
+}
+
+// GetByName returns the player with the ratings in the discipline.
+func (s *PlayerService) GetByName(discipline int, name string) (domain.Player, error) {
+	s.refreshCache()
+	player, ok := s.cache.GetPlayerByName(disciplineID(discipline), name)
+	if !ok {
+		return domain.Player{}, errors.New("not found")
+	}
+	return player, nil
+}
+
+func (s *PlayerService) CreatePlayer(name string) (domain.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkPlayerName(name, uuid.Nil)
+	if err != nil {
+		return domain.Player{}, err
+	}
 	newPlayer := domain.Player{
 		ID:           uuid.New(),
 		Name:         name,
 		RegisteredAt: time.Now(),
 	}
-	player, err = s.playerStorage.Add(newPlayer)
+	player, err := s.playerStorage.Add(newPlayer)
 	if err != nil {
 		return domain.Player{}, err
 	}
+	s.disciplinesMu.RLock()
+	for _, h := range s.histories {
+		h.addPlayer(player)
+	}
+	for _, h := range s.seasonHistories {
+		h.addPlayer(player)
+	}
+	s.disciplinesMu.RUnlock()
+
+	s.updateCache()
+	return player, nil
+}
+
+// checkPlayerName checks that the name is not taken by another player
+// or a discipline, the player with the id may have it.
+func (s *PlayerService) checkPlayerName(name string, id uuid.UUID) error {
+	players, err := s.playerStorage.ListPlayers()
+	if err != nil {
+		return err
+	}
+	normName := normalize.Name(name)
+	for _, player := range players {
+		if player.ID != id && normalize.Name(player.Name) == normName {
+			return errors.New("player " + player.Name + " already exists")
+		}
+	}
+	// disciplines and players are told apart by name in the bot commands
+	if _, err := s.Discipline(name); err == nil {
+		return errors.New("имя " + name + " занято дисциплиной")
+	}
+	return nil
+}
+
+// GetPlayer returns the player without the ratings.
+func (s *PlayerService) GetPlayer(id uuid.UUID) (domain.Player, error) {
+	player, err := s.playerStorage.Get(id)
+	if err != nil {
+		return domain.Player{}, errors.New("игрок не найден")
+	}
 	return player, nil
 }
+
+// RenamePlayer changes the name of the player, the matches are kept.
+func (s *PlayerService) RenamePlayer(id uuid.UUID, name string) (domain.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return domain.Player{}, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.Player{}, errors.New("имя должно быть не пустое")
+	}
+	err = s.checkPlayerName(name, id)
+	if err != nil {
+		return domain.Player{}, err
+	}
+	player.Name = name
+	err = s.playerStorage.UpdatePlayer(player)
+	if err != nil {
+		return domain.Player{}, err
+	}
+	// the names are copied to the matches, so the history is replayed
+	return player, s.reload()
+}
+
+// DeactivatePlayer hides the player from the leaderboards or shows them again.
+func (s *PlayerService) DeactivatePlayer(id uuid.UUID, deactivated bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return err
+	}
+	player.Deactivated = deactivated
+	err = s.playerStorage.UpdatePlayer(player)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.RLock()
+	for _, h := range s.histories {
+		h.addPlayer(player)
+	}
+	for _, h := range s.seasonHistories {
+		h.addPlayer(player)
+	}
+	s.disciplinesMu.RUnlock()
+
+	s.updateCache()
+	return nil
+}
+
+// OnPlayersMerged adds a function called before the player from is merged into the player to,
+// so the links to the players kept outside of the storage are updated. An error of the function
+// stops the merge, the function must be safe to call again when the merge is retried.
+func (s *PlayerService) OnPlayersMerged(fn func(from, to uuid.UUID) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mergeHooks = append(s.mergeHooks, fn)
+}
+
+// MergePlayers moves all the matches of the player from, created by mistake,
+// to the player to and removes the player from. The players must not have
+// played in the same match.
+func (s *PlayerService) MergePlayers(from, to uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if from == to {
+		return errors.New("нельзя объединить игрока с самим собой")
+	}
+	fromPlayer, err := s.GetPlayer(from)
+	if err != nil {
+		return err
+	}
+	toPlayer, err := s.GetPlayer(to)
+	if err != nil {
+		return err
+	}
+	shared, err := s.sharedMatch(from, to)
+	if err != nil {
+		return err
+	}
+	if shared != "" {
+		return errors.New("игроки " + fromPlayer.Name + " и " + toPlayer.Name + " играли в одном матче " + shared + ", объединить нельзя")
+	}
+	if tournament, ok := s.sharedTournament(from, to); ok {
+		return errors.New("игроки " + fromPlayer.Name + " и " + toPlayer.Name + " участвуют в турнире " + tournament.Name + ", объединить нельзя")
+	}
+	if discipline, ok := s.sharedLadder(from, to); ok {
+		return errors.New("игроки " + fromPlayer.Name + " и " + toPlayer.Name + " стоят на лестнице " + discipline.Name + ", объединить нельзя")
+	}
+	// the links are moved first, so none of them is left to the removed player
+	for _, hook := range s.mergeHooks {
+		err = hook(from, to)
+		if err != nil {
+			return err
+		}
+	}
+	err = s.playerStorage.MergePlayers(from, to)
+	if err != nil {
+		return err
+	}
+	return s.reload()
+}
+
+// sharedMatch returns the number of a match both players played in, empty if there is none.
+func (s *PlayerService) sharedMatch(a, b uuid.UUID) (string, error) {
+	matches, err := s.matchStorage.ListMatches()
+	if err != nil {
+		return "", err
+	}
+	for _, match := range matches {
+		if match.Played(a) && match.Played(b) {
+			return strconv.Itoa(match.ID), nil
+		}
+	}
+	ffaMatches, err := s.matchStorage.ListFFAMatches()
+	if err != nil {
+		return "", err
+	}
+	for _, match := range ffaMatches {
+		if match.Played(a) && match.Played(b) {
+			return strconv.Itoa(match.ID), nil
+		}
+	}
+	return "", nil
+}
+
+// Seasons returns all the seasons ordered by start.
+func (s *PlayerService) Seasons() []domain.Season {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	seasons := make([]domain.Season, len(s.seasons))
+	copy(seasons, s.seasons)
+	return seasons
+}
+
+// CurrentSeason returns the season going on now, false between the seasons.
+func (s *PlayerService) CurrentSeason() (domain.Season, bool) {
+	s.refreshCache()
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	return s.currentSeason, s.currentSeason.ID != 0
+}
+
+func (s *PlayerService) Season(id int) (domain.Season, error) {
+	for _, season := range s.Seasons() {
+		if season.ID == id {
+			return season, nil
+		}
+	}
+	return domain.Season{}, errors.New("сезон не найден")
+}
+
+// CreateSeason adds a season, it must not overlap the other seasons.
+// A season that has already finished is archived at once.
+func (s *PlayerService) CreateSeason(season domain.Season) (domain.Season, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	season.Name = strings.TrimSpace(season.Name)
+	err := season.Validate()
+	if err != nil {
+		return domain.Season{}, err
+	}
+	for _, other := range s.Seasons() {
+		if normalize.Name(other.Name) == normalize.Name(season.Name) {
+			return domain.Season{}, errors.New("сезон " + season.Name + " уже существует")
+		}
+		if other.Overlaps(season) {
+			return domain.Season{}, errors.New("сезон пересекается с сезоном " + other.Name)
+		}
+	}
+	created, err := s.seasonStorage.AddSeason(season)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	s.disciplinesMu.Lock()
+	s.seasons = append(s.seasons, created)
+	sort.SliceStable(s.seasons, func(i, j int) bool {
+		return s.seasons[i].Start.Before(s.seasons[j].Start)
+	})
+	s.disciplinesMu.Unlock()
+
+	err = s.updateSeasons(time.Now())
+	if err != nil {
+		return domain.Season{}, err
+	}
+	return s.Season(created.ID)
+}
+
+// GetSeasonStandings returns the standings of the season in the discipline:
+// the archived ones for a finished season, the live ones otherwise.
+func (s *PlayerService) GetSeasonStandings(discipline, seasonID int) ([]domain.SeasonStanding, error) {
+	season, err := s.Season(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := s.history(discipline)
+	if err != nil {
+		return nil, err
+	}
+	if season.Archived() {
+		standings, err := s.seasonStorage.ListSeasonStandings(season.ID, disciplineID(discipline))
+		if err != nil {
+			return nil, err
+		}
+		s.fillStandings(disciplineID(discipline), standings)
+		return standings, nil
+	}
+	now := time.Now()
+	if season.Finished(now) {
+		now = season.End
+	}
+	s.disciplinesMu.RLock()
+	sh, ok := s.seasonHistories[disciplineID(discipline)]
+	current := s.currentSeason.ID == season.ID
+	s.disciplinesMu.RUnlock()
+	if !ok || !current {
+		sh = h.season(season)
+	}
+	return sh.standings(season, now), nil
+}
+
+// fillStandings adds the names of the players and orders the ratings like the rating systems,
+// the ratings of the systems configured after the season was archived are left empty.
+func (s *PlayerService) fillStandings(discipline int, standings []domain.SeasonStanding) {
+	s.refreshCache()
+	for i := range standings {
+		if player, ok := s.cache.GetPlayer(discipline, standings[i].Player.ID); ok {
+			standings[i].Player.Name = player.Name
+		}
+		ratings := make([]domain.Rating, 0, len(s.systems))
+		for _, system := range s.systems {
+			r := domain.Rating{System: system.Name()}
+			for _, archived := range standings[i].Ratings {
+				if archived.System == system.Name() {
+					r = archived
+				}
+			}
+			ratings = append(ratings, r)
+		}
+		standings[i].Ratings = ratings
+	}
+}
@@ -1,15 +1,24 @@
 package service
 
 import (
+	"errors"
+	"math"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/goserg/ratingserver/internal/cache/mem"
+	"github.com/goserg/ratingserver/internal/config"
 	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/rating"
 
 	"github.com/google/uuid"
 )
 
-func Test_calculateMatches(t *testing.T) {
+func Test_history_replay(t *testing.T) {
 	player1 := uuid.New()
 	player2 := uuid.New()
 	player3 := uuid.New()
@@ -57,51 +66,60 @@ func Test_calculateMatches(t *testing.T) {
 			},
 			want: []domain.Match{
 				{
+					DisciplineID: domain.DefaultDisciplineID,
 					PlayerA: domain.Player{
-						ID:           player1,
-						EloRating:    980,
-						GamesPlayed:  1,
-						RatingChange: -20,
+						ID:          player1,
+						GamesPlayed: 1,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 980, Change: -20},
+						},
 					},
 					PlayerB: domain.Player{
-						ID:           player2,
-						EloRating:    1020,
-						GamesPlayed:  1,
-						RatingChange: 20,
+						ID:          player2,
+						GamesPlayed: 1,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 1020, Change: 20},
+						},
 					},
 					Winner: domain.Player{
 						ID: player2,
 					},
 				},
 				{
+					DisciplineID: domain.DefaultDisciplineID,
 					PlayerA: domain.Player{
-						ID:           player1,
-						EloRating:    961,
-						GamesPlayed:  2,
-						RatingChange: -19,
+						ID:          player1,
+						GamesPlayed: 2,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 961, Change: -19},
+						},
 					},
 					PlayerB: domain.Player{
-						ID:           player3,
-						EloRating:    1019,
-						GamesPlayed:  1,
-						RatingChange: 19,
+						ID:          player3,
+						GamesPlayed: 1,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 1019, Change: 19},
+						},
 					},
 					Winner: domain.Player{
 						ID: player3,
 					},
 				},
 				{
+					DisciplineID: domain.DefaultDisciplineID,
 					PlayerA: domain.Player{
-						ID:           player2,
-						EloRating:    1040,
-						GamesPlayed:  2,
-						RatingChange: 20,
+						ID:          player2,
+						GamesPlayed: 2,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 1040, Change: 20},
+						},
 					},
 					PlayerB: domain.Player{
-						ID:           player3,
-						EloRating:    999,
-						GamesPlayed:  2,
-						RatingChange: -20,
+						ID:          player3,
+						GamesPlayed: 2,
+						Ratings: []domain.Rating{
+							{System: rating.Elo, Value: 999, Change: -20},
+						},
 					},
 					Winner: domain.Player{
 						ID: player2,
@@ -112,9 +130,1822 @@ func Test_calculateMatches(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := calculateMatches(tt.matches); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("calculateMatches() = %v, want %v", got, tt.want)
+			h := newHistory(domain.DefaultDisciplineID, eloSystems(t))
+			h.replay([]domain.Player{{ID: player1}, {ID: player2}, {ID: player3}}, tt.matches, nil)
+			if got := h.listMatches(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("replay() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func eloSystems(t *testing.T) []rating.System {
+	system, err := rating.NewElo(config.Elo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []rating.System{system}
+}
+
+func TestPlayerService_RatingHistory(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	st.players = []domain.Player{player1, player2}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.history) != 2 {
+		t.Fatalf("history must be saved on start, got %d entries", len(st.history))
+	}
+	_, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: start.Add(time.Hour * 24)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := s.GetRatingHistory(domain.DefaultDisciplineID, player1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.RatingHistoryEntry{
+		{MatchID: 1, DisciplineID: domain.DefaultDisciplineID, PlayerID: player1.ID, System: rating.Elo, RatingBefore: 1000, RatingAfter: 1020, Date: start},
+		{MatchID: 2, DisciplineID: domain.DefaultDisciplineID, PlayerID: player1.ID, System: rating.Elo, RatingBefore: 1020, RatingAfter: 998, Date: start.Add(time.Hour * 24)},
+	}
+	if !reflect.DeepEqual(history, want) {
+		t.Errorf("GetRatingHistory() = %v, want %v", history, want)
+	}
+	ratings, err := s.GetRatingsAt(domain.DefaultDisciplineID, player1.ID, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratings[0].Value != 1020 {
+		t.Errorf("GetRatingsAt() = %v, want 1020", ratings[0].Value)
+	}
+}
+
+func TestPlayerService_RatingChart(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	st.players = []domain.Player{player1, player2, player3}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: start.Add(time.Hour), Voided: true})
+	_, _ = st.Create(domain.Match{PlayerA: player2, PlayerB: player1, Winner: player1, Date: start.Add(2 * time.Hour)})
+
+	systems, err := rating.New(config.Rating{Systems: []string{rating.Elo, rating.TrueSkill, rating.Glicko2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(st, st, st, st, st, st, mem.New(), systems, config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, err := s.RatingChart(domain.DefaultDisciplineID, player1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].System.Name != rating.Elo || series[1].System.Name != rating.Glicko2 {
+		t.Fatalf("RatingChart() = %v, want the elo and glicko2 series", series)
+	}
+	elo, glicko := series[0].Points, series[1].Points
+	if len(elo) != 3 || len(glicko) != 3 {
+		t.Fatalf("RatingChart() has %d and %d points, want the rating before and after two matches", len(elo), len(glicko))
+	}
+	if elo[0].Value != 1000 || elo[0].Deviation != 0 || !elo[0].Date.Equal(start) || !elo[2].Date.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("RatingChart() elo = %v", elo)
+	}
+	if glicko[0].Value != 1500 || glicko[0].Deviation != 350 {
+		t.Errorf("RatingChart() glicko2 starts at %v, want 1500 ± 350", glicko[0])
+	}
+	for i := 1; i < len(glicko); i++ {
+		if glicko[i].Value <= glicko[i-1].Value || glicko[i].Deviation >= glicko[i-1].Deviation {
+			t.Errorf("RatingChart() glicko2 = %v, the wins must raise the rating and narrow the band", glicko)
+		}
+	}
+	series, err = s.RatingChart(domain.DefaultDisciplineID, player3.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || len(series[0].Points) != 0 || len(series[1].Points) != 0 {
+		t.Errorf("RatingChart() = %v, want no points before the first match", series)
+	}
+}
+
+func TestPlayerService_Inactivity(t *testing.T) {
+	st := &memStorage{}
+	now := time.Now()
+	player1 := domain.Player{ID: uuid.New(), Name: "player1", RegisteredAt: now.AddDate(-1, 0, 0)}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2", RegisteredAt: now.AddDate(-1, 0, 0)}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3", RegisteredAt: now.AddDate(-1, 0, 0)}
+	newcomer := domain.Player{ID: uuid.New(), Name: "newcomer", RegisteredAt: now}
+	st.players = []domain.Player{player1, player2, player3, newcomer}
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: now.AddDate(0, -6, 0)})
+	_, _ = st.Create(domain.Match{PlayerA: player2, PlayerB: player3, Winner: player2, Date: now.AddDate(0, 0, -1)})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{InactiveAfterDays: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+		names = append(names, player.Name)
+	}
+	if want := []string{"player2", "newcomer", "player3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetRatings() = %v, want %v", names, want)
+	}
+	player, err := s.Get(domain.DefaultDisciplineID, player1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !player.Inactive || player.RatingRank != 0 {
+		t.Errorf("player1 must be inactive and unranked, got inactive %v, rank %d", player.Inactive, player.RatingRank)
+	}
+}
+
+func TestPlayerService_Predict(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	st.players = []domain.Player{player1, player2}
+	start := time.Now().Add(-time.Hour)
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Date: start})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prediction, err := s.Predict(domain.DefaultDisciplineID, player1.ID, player2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := prediction.Systems[0]
+	if sum := p.WinA.Probability + p.Draw.Probability + p.WinB.Probability; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("probabilities sum = %v, want 1", sum)
+	}
+	if p.WinA.Probability <= p.WinB.Probability {
+		t.Errorf("stronger player must be more likely to win: %v <= %v", p.WinA.Probability, p.WinB.Probability)
+	}
+	if p.Draw.Probability <= 0 {
+		t.Errorf("draw probability must be positive when there were draws, got %v", p.Draw.Probability)
+	}
+	tests := []struct {
+		name    string
+		outcome domain.Outcome
+		wantA   float64
+		wantB   float64
+	}{
+		{name: "win", outcome: p.WinA, wantA: 1036, wantB: 964},
+		{name: "draw", outcome: p.Draw, wantA: 1016, wantB: 984},
+		{name: "lose", outcome: p.WinB, wantA: 996, wantB: 1004},
+	}
+	for _, tt := range tests {
+		if tt.outcome.A.Value != tt.wantA || tt.outcome.B.Value != tt.wantB {
+			t.Errorf("%s: ratings = %v, %v, want %v, %v", tt.name, tt.outcome.A.Value, tt.outcome.B.Value, tt.wantA, tt.wantB)
+		}
+	}
+	after, _ := s.Get(domain.DefaultDisciplineID, player1.ID)
+	if after.PrimaryRating().Value != 1018 {
+		t.Errorf("prediction must not change ratings, got %v", after.PrimaryRating().Value)
+	}
+	if _, err = s.Predict(domain.DefaultDisciplineID, player1.ID, player1.ID); err == nil {
+		t.Errorf("prediction for the same player must fail")
+	}
+}
+
+func TestPlayerService_TeamMatch(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	player4 := domain.Player{ID: uuid.New(), Name: "player4"}
+	st.players = []domain.Player{player1, player2, player3, player4}
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	match, err := s.CreateMatch(domain.Match{
+		PlayerA:    player1,
+		PlayerB:    player3,
+		TeammatesA: []domain.Player{player2},
+		TeammatesB: []domain.Player{player4},
+		Winner:     player1,
+		Date:       time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := match.TeammatesB[0].PrimaryRating().Value; got != 980 {
+		t.Errorf("created match teammate rating = %v, want 980", got)
+	}
+	want := map[uuid.UUID]float64{player1.ID: 1020, player2.ID: 1020, player3.ID: 980, player4.ID: 980}
+	for id, value := range want {
+		player, err := s.Get(domain.DefaultDisciplineID, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if player.PrimaryRating().Value != value || player.GamesPlayed != 1 {
+			t.Errorf("player %s: rating %v, games %d, want %v, 1", player.Name, player.PrimaryRating().Value, player.GamesPlayed, value)
+		}
+	}
+	data, err := s.GetPlayerData(domain.DefaultDisciplineID, player2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Results[player3.ID].Wins != 1 || data.Results[player4.ID].Wins != 1 || data.Results[player1.ID].Wins != 0 {
+		t.Errorf("player2 results = %+v, want a win against each opponent only", data.Results)
+	}
+
+	_, err = s.CreateMatch(domain.Match{
+		PlayerA:    player1,
+		PlayerB:    player3,
+		TeammatesA: []domain.Player{player3},
+		Winner:     player1,
+	})
+	if err == nil {
+		t.Errorf("player playing for both sides must fail")
+	}
+}
+
+func TestPlayerService_FFAMatch(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	st.players = []domain.Player{player1, player2, player3}
+	start := time.Now().Add(-time.Hour)
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start.Add(time.Minute)})
+	_, _ = st.CreateFFA(domain.FFAMatch{Date: start, Placements: []domain.Placement{
+		{Player: player3, Place: 1},
+		{Player: player1, Place: 2},
+		{Player: player2, Place: 2},
+	}})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the free-for-all match was played first: player3 1020, player1 and player2 990,
+	// then player1 wins against player2
+	want := map[uuid.UUID]float64{player1.ID: 1010, player2.ID: 970, player3.ID: 1020}
+	for id, value := range want {
+		player, err := s.Get(domain.DefaultDisciplineID, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if player.PrimaryRating().Value != value {
+			t.Errorf("player %s: rating %v, want %v", player.Name, player.PrimaryRating().Value, value)
+		}
+	}
+	data, err := s.GetPlayerData(domain.DefaultDisciplineID, player1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := data.Results[player2.ID]; r.Wins != 1 || r.Draws != 1 || r.Loses != 0 {
+		t.Errorf("results against player2 = %+v, want 1 win and 1 draw", r)
+	}
+	if r := data.Results[player3.ID]; r.Wins != 0 || r.Draws != 0 || r.Loses != 1 {
+		t.Errorf("results against player3 = %+v, want 1 loss", r)
+	}
+
+	placements, err := s.PlacementsByNames(domain.DefaultDisciplineID, []string{"player1", "player2=player3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.CreateFFAMatch(domain.FFAMatch{Date: time.Now(), Placements: placements}); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := s.GetFFAMatches(domain.DefaultDisciplineID); len(matches) != 2 || matches[0].Placements[2].Place != 2 {
+		t.Errorf("GetFFAMatches() = %+v, want the new match with a shared second place first", matches)
+	}
+	if _, err = s.CreateFFAMatch(domain.FFAMatch{Date: time.Now(), Placements: placements[:2]}); err == nil {
+		t.Errorf("free-for-all match of two players must fail")
+	}
+
+	// the matches played at the same time keep their ratings after a reload
+	at := time.Now()
+	if _, err = s.CreateFFAMatch(domain.FFAMatch{Date: at, Placements: placements}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.CreateMatch(domain.Match{PlayerA: player2, PlayerB: player3, Winner: player3, Date: at}); err != nil {
+		t.Fatal(err)
+	}
+	ratings := func() map[uuid.UUID]float64 {
+		values := make(map[uuid.UUID]float64)
+		for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+			values[player.ID] = player.PrimaryRating().Value
+		}
+		return values
+	}
+	appended := ratings()
+	if err = s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if replayed := ratings(); !reflect.DeepEqual(replayed, appended) {
+		t.Errorf("ratings after the reload = %v, want %v", replayed, appended)
+	}
+}
+
+func TestPlayerService_Disciplines(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	st.players = []domain.Player{player1, player2}
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: time.Now()})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chess, err := s.CreateDiscipline("Шахматы")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.CreateDiscipline("шахматы"); err == nil {
+		t.Errorf("discipline with the same name must fail")
+	}
+	if _, err = s.CreateDiscipline("player1"); err == nil {
+		t.Errorf("discipline with a player's name must fail")
+	}
+	if found, err := s.Discipline("ШАХМАТЫ"); err != nil || found.ID != chess.ID {
+		t.Errorf("Discipline() = %v, %v, want %v", found, err, chess)
+	}
+	newcomer, err := s.CreatePlayer("newcomer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.CreateMatch(domain.Match{DisciplineID: chess.ID, PlayerA: player2, PlayerB: newcomer, Winner: player2, Date: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		discipline int
+		want       map[uuid.UUID]float64
+	}{
+		{
+			name:       "default",
+			discipline: domain.DefaultDisciplineID,
+			want:       map[uuid.UUID]float64{player1.ID: 1020, player2.ID: 980, newcomer.ID: 1000},
+		},
+		{
+			name:       "chess",
+			discipline: chess.ID,
+			want:       map[uuid.UUID]float64{player1.ID: 1000, player2.ID: 1020, newcomer.ID: 980},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := s.GetRatings(tt.discipline)
+			if len(players) != len(tt.want) {
+				t.Fatalf("GetRatings() returned %d players, want %d", len(players), len(tt.want))
+			}
+			for _, player := range players {
+				if player.PrimaryRating().Value != tt.want[player.ID] {
+					t.Errorf("player %s: rating %v, want %v", player.Name, player.PrimaryRating().Value, tt.want[player.ID])
+				}
+			}
+			matches, err := s.GetMatches(tt.discipline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != 1 || matches[0].DisciplineID != tt.discipline {
+				t.Errorf("GetMatches() = %v, want one match of the discipline", matches)
+			}
+		})
+	}
+	if _, err = s.GetMatches(100); err == nil {
+		t.Errorf("matches of an unknown discipline must fail")
+	}
+}
+
+type memStorage struct {
+	players    []domain.Player
+	matches    []domain.Match
+	ffaMatches []domain.FFAMatch
+	history    []domain.RatingHistoryEntry
+	// disciplines are added after the default one
+	disciplines []domain.Discipline
+	seasons     []domain.Season
+	standings   []domain.SeasonStanding
+	changes     []domain.MatchChange
+	tournaments []domain.Tournament
+	ladder      []domain.LadderEntry
+	challenges  []domain.LadderChallenge
+}
+
+func (m *memStorage) ListPlayers() ([]domain.Player, error) {
+	players := make([]domain.Player, len(m.players))
+	copy(players, m.players)
+	return players, nil
+}
+
+func (m *memStorage) Get(id uuid.UUID) (domain.Player, error) {
+	for i := range m.players {
+		if m.players[i].ID == id {
+			return m.players[i], nil
+		}
+	}
+	return domain.Player{}, errors.New("not found")
+}
+
+func (m *memStorage) Add(player domain.Player) (domain.Player, error) {
+	m.players = append(m.players, player)
+	return player, nil
+}
+
+func (m *memStorage) UpdatePlayer(player domain.Player) error {
+	for i := range m.players {
+		if m.players[i].ID == player.ID {
+			m.players[i] = player
+		}
+	}
+	return nil
+}
+
+func (m *memStorage) MergePlayers(from, to uuid.UUID) error {
+	var target domain.Player
+	players := m.players[:0]
+	for _, player := range m.players {
+		if player.ID == to {
+			target = player
+		}
+		if player.ID != from {
+			players = append(players, player)
+		}
+	}
+	m.players = players
+	replace := func(player *domain.Player) {
+		if player.ID == from {
+			*player = target
+		}
+	}
+	for i := range m.matches {
+		match := &m.matches[i]
+		replace(&match.PlayerA)
+		replace(&match.PlayerB)
+		replace(&match.Winner)
+		for j := range match.TeammatesA {
+			replace(&match.TeammatesA[j])
+		}
+		for j := range match.TeammatesB {
+			replace(&match.TeammatesB[j])
+		}
+	}
+	for i := range m.ffaMatches {
+		for j := range m.ffaMatches[i].Placements {
+			replace(&m.ffaMatches[i].Placements[j].Player)
+		}
+	}
+	return nil
+}
+
+func (m *memStorage) ImportPlayers(players []domain.Player) error {
+	m.players = append(m.players, players...)
+	return nil
+}
+
+func (m *memStorage) ListMatches() ([]domain.Match, error) {
+	matches := make([]domain.Match, len(m.matches))
+	copy(matches, m.matches)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Date.Before(matches[j].Date)
+	})
+	return matches, nil
+}
+
+func (m *memStorage) Create(match domain.Match) (domain.Match, error) {
+	match.ID = 1
+	if n := len(m.matches); n > 0 {
+		match.ID = m.matches[n-1].ID + 1
+	}
+	m.matches = append(m.matches, match)
+	return match, nil
+}
+
+func (m *memStorage) UpdateMatch(match domain.Match, change domain.MatchChange) error {
+	for i := range m.matches {
+		if m.matches[i].ID == match.ID {
+			m.matches[i] = match
+		}
+	}
+	m.changes = append([]domain.MatchChange{change}, m.changes...)
+	return nil
+}
+
+func (m *memStorage) DeleteMatch(id int, change domain.MatchChange) error {
+	for i := range m.matches {
+		if m.matches[i].ID == id {
+			m.matches = append(m.matches[:i], m.matches[i+1:]...)
+			break
+		}
+	}
+	m.changes = append([]domain.MatchChange{change}, m.changes...)
+	return nil
+}
+
+func (m *memStorage) ListMatchChanges() ([]domain.MatchChange, error) {
+	return m.changes, nil
+}
+
+func (m *memStorage) ImportMatches(matches []domain.Match) error {
+	for _, match := range matches {
+		if match.ID != 0 {
+			m.matches = append(m.matches, match)
+			continue
+		}
+		_, _ = m.Create(match)
+	}
+	return nil
+}
+
+func (m *memStorage) ListFFAMatches() ([]domain.FFAMatch, error) {
+	matches := make([]domain.FFAMatch, len(m.ffaMatches))
+	copy(matches, m.ffaMatches)
+	return matches, nil
+}
+
+func (m *memStorage) CreateFFA(match domain.FFAMatch) (domain.FFAMatch, error) {
+	match.ID = len(m.ffaMatches) + 1
+	m.ffaMatches = append(m.ffaMatches, match)
+	return match, nil
+}
+
+func (m *memStorage) ListDisciplines() ([]domain.Discipline, error) {
+	disciplines := []domain.Discipline{{ID: domain.DefaultDisciplineID, Name: "общий"}}
+	return append(disciplines, m.disciplines...), nil
+}
+
+func (m *memStorage) AddDiscipline(discipline domain.Discipline) (domain.Discipline, error) {
+	discipline.ID = len(m.disciplines) + 2
+	m.disciplines = append(m.disciplines, discipline)
+	return discipline, nil
+}
+
+func (m *memStorage) ListSeasons() ([]domain.Season, error) {
+	seasons := make([]domain.Season, len(m.seasons))
+	copy(seasons, m.seasons)
+	sort.SliceStable(seasons, func(i, j int) bool {
+		return seasons[i].Start.Before(seasons[j].Start)
+	})
+	return seasons, nil
+}
+
+func (m *memStorage) AddSeason(season domain.Season) (domain.Season, error) {
+	season.ID = len(m.seasons) + 1
+	m.seasons = append(m.seasons, season)
+	return season, nil
+}
+
+func (m *memStorage) ArchiveSeason(season domain.Season, standings []domain.SeasonStanding) error {
+	for i := range m.seasons {
+		if m.seasons[i].ID == season.ID {
+			m.seasons[i].ArchivedAt = season.ArchivedAt
+		}
+	}
+	m.standings = append(m.standings, standings...)
+	return nil
+}
+
+func (m *memStorage) ListSeasonStandings(seasonID, disciplineID int) ([]domain.SeasonStanding, error) {
+	var standings []domain.SeasonStanding
+	for _, standing := range m.standings {
+		if standing.SeasonID == seasonID && standing.DisciplineID == disciplineID {
+			standings = append(standings, standing)
+		}
+	}
+	return standings, nil
+}
+
+func (m *memStorage) ListRatingHistory(filter domain.RatingHistoryFilter) ([]domain.RatingHistoryEntry, error) {
+	var entries []domain.RatingHistoryEntry
+	for _, entry := range m.history {
+		if filter.PlayerID != uuid.Nil && entry.PlayerID != filter.PlayerID {
+			continue
+		}
+		if filter.DisciplineID != 0 && entry.DisciplineID != filter.DisciplineID {
+			continue
+		}
+		if filter.System != "" && entry.System != filter.System {
+			continue
+		}
+		if !filter.To.IsZero() && entry.Date.After(filter.To) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (m *memStorage) SaveRatingHistory(entries []domain.RatingHistoryEntry) error {
+	m.history = append(m.history, entries...)
+	return nil
+}
+
+func (m *memStorage) ReplaceRatingHistory(entries []domain.RatingHistoryEntry) error {
+	m.history = entries
+	return nil
+}
+
+func (m *memStorage) ListTournaments() ([]domain.Tournament, error) {
+	tournaments := make([]domain.Tournament, len(m.tournaments))
+	for i, t := range m.tournaments {
+		t.Players = append([]domain.Player(nil), t.Players...)
+		t.Games = append([]domain.TournamentGame(nil), t.Games...)
+		tournaments[i] = t
+	}
+	return tournaments, nil
+}
+
+func (m *memStorage) AddTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	tournament.ID = len(m.tournaments) + 1
+	m.tournaments = append(m.tournaments, tournament)
+	return tournament, nil
+}
+
+func (m *memStorage) SaveTournamentGames(tournamentID int, games []domain.TournamentGame) error {
+	t := &m.tournaments[tournamentID-1]
+	for _, game := range games {
+		game.Match = nil
+		replaced := false
+		for i := range t.Games {
+			if sameGame(t.Games[i], game) {
+				t.Games[i], replaced = game, true
+			}
+		}
+		if !replaced {
+			t.Games = append(t.Games, game)
+		}
+	}
+	return nil
+}
+
+func (m *memStorage) FinishTournament(id int, finishedAt time.Time) error {
+	m.tournaments[id-1].FinishedAt = finishedAt
+	return nil
+}
+
+func (m *memStorage) ListLadderEntries() ([]domain.LadderEntry, error) {
+	return append([]domain.LadderEntry(nil), m.ladder...), nil
+}
+
+func (m *memStorage) AddLadderEntry(entry domain.LadderEntry) error {
+	m.ladder = append(m.ladder, entry)
+	return nil
+}
+
+func (m *memStorage) ListChallenges() ([]domain.LadderChallenge, error) {
+	return append([]domain.LadderChallenge(nil), m.challenges...), nil
+}
+
+func (m *memStorage) AddChallenge(challenge domain.LadderChallenge) (domain.LadderChallenge, error) {
+	challenge.ID = len(m.challenges) + 1
+	m.challenges = append(m.challenges, challenge)
+	return challenge, nil
+}
+
+func (m *memStorage) UpdateChallenge(challenge domain.LadderChallenge) error {
+	challenge.Match = nil
+	challenge.Status = ""
+	m.challenges[challenge.ID-1] = challenge
+	return nil
+}
+
+func newBenchService(b *testing.B, players, matches int) (*PlayerService, *memStorage) {
+	b.Helper()
+	st := &memStorage{}
+	for i := 0; i < players; i++ {
+		st.players = append(st.players, domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i)})
+	}
+	rnd := rand.New(rand.NewSource(1))
+	// the matches of the largest size take less than six years
+	start := time.Now().AddDate(-10, 0, 0)
+	for i := 0; i < matches; i++ {
+		playerA := st.players[rnd.Intn(players)]
+		playerB := st.players[rnd.Intn(players)]
+		if playerA.ID == playerB.ID {
+			continue
+		}
+		_, _ = st.Create(domain.Match{
+			PlayerA: domain.Player{ID: playerA.ID},
+			PlayerB: domain.Player{ID: playerB.ID},
+			Winner:  domain.Player{ID: playerA.ID},
+			Date:    start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	systems, err := rating.New(config.Rating{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	s, err := New(st, st, st, st, st, st, mem.New(), systems, config.Rating{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return s, st
+}
+
+var benchSizes = []int{100, 1000, 10000, 50000}
+
+func BenchmarkPlayerService_Get(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			s, st := newBenchService(b, 30, size)
+			id := st.players[0].ID
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Get(domain.DefaultDisciplineID, id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPlayerService_GetRatings(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			s, _ := newBenchService(b, 30, size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.GetRatings(domain.DefaultDisciplineID)
+			}
+		})
+	}
+}
+
+func BenchmarkPlayerService_GetPlayerData(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			s, st := newBenchService(b, 30, size)
+			id := st.players[0].ID
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.GetPlayerData(domain.DefaultDisciplineID, id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPlayerService_CreateMatch(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			s, st := newBenchService(b, 30, size)
+			// after the history, so the ratings are not replayed, but not in the future
+			date := time.Now().AddDate(-1, 0, 0)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := s.CreateMatch(domain.Match{
+					PlayerA: st.players[0],
+					PlayerB: st.players[1],
+					Winner:  st.players[i%2],
+					Date:    date.Add(time.Duration(i) * time.Second),
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPlayerService_Seasons(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	st.players = []domain.Player{player1, player2}
+	now := time.Now()
+	past, _ := st.AddSeason(domain.Season{Name: "past", Start: now.AddDate(0, 0, -60), End: now.AddDate(0, 0, -30)})
+	current, _ := st.AddSeason(domain.Season{Name: "current", Start: now.AddDate(0, 0, -7), End: now.AddDate(0, 0, 7), Carryover: 0.5})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: now.AddDate(0, 0, -50)})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: now.AddDate(0, 0, -40)})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: now.AddDate(0, 0, -1)})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := s.CurrentSeason(); !ok || got.ID != current.ID {
+		t.Errorf("CurrentSeason() = %v, %v, want %v", got, ok, current)
+	}
+	if ratings := s.GetRatings(domain.DefaultDisciplineID); ratings[0].ID != player1.ID {
+		t.Errorf("all-time leader is %s, want player1", ratings[0].Name)
+	}
+
+	standings, err := s.GetSeasonStandings(domain.DefaultDisciplineID, past.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.standings) != 2 || !s.Seasons()[0].Archived() {
+		t.Errorf("finished season was not archived: %v", st.standings)
+	}
+	if len(standings) != 2 || standings[0].Player.Name != "player1" || standings[0].GamesPlayed != 2 {
+		t.Errorf("GetSeasonStandings(past) = %+v, want player1 first with 2 games", standings)
+	}
+
+	// the season starts half way between the all-time ratings and the initial ones,
+	// so the win of player2 is enough to lead the season
+	standings, err = s.GetSeasonStandings(domain.DefaultDisciplineID, current.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 2 || standings[0].Player.ID != player2.ID || standings[0].GamesPlayed != 1 {
+		t.Errorf("GetSeasonStandings(current) = %+v, want player2 first with 1 game", standings)
+	}
+	if _, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	standings, _ = s.GetSeasonStandings(domain.DefaultDisciplineID, current.ID)
+	if len(standings) != 2 || standings[0].GamesPlayed != 2 {
+		t.Errorf("GetSeasonStandings(current) = %+v, want the new match counted", standings)
+	}
+
+	tests := []struct {
+		name   string
+		season domain.Season
+	}{
+		{name: "overlapping", season: domain.Season{Name: "new", Start: now, End: now.AddDate(0, 1, 0)}},
+		{name: "same name", season: domain.Season{Name: "Past", Start: now.AddDate(0, 1, 0), End: now.AddDate(0, 2, 0)}},
+		{name: "ends before start", season: domain.Season{Name: "new", Start: now.AddDate(0, 2, 0), End: now.AddDate(0, 1, 0)}},
+		{name: "carryover", season: domain.Season{Name: "new", Start: now.AddDate(0, 1, 0), End: now.AddDate(0, 2, 0), Carryover: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateSeason(tt.season); err == nil {
+				t.Errorf("CreateSeason() must fail")
+			}
+		})
+	}
+}
+
+func TestPlayerService_EditMatch(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	st.players = []domain.Player{player1, player2, player3}
+	start := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	first, _ := st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player3, Winner: player1, Date: start.Add(time.Hour)})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratings := func() map[uuid.UUID]float64 {
+		got := make(map[uuid.UUID]float64)
+		for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+			got[player.ID] = player.PrimaryRating().Value
+		}
+		return got
+	}
+
+	edited, err := s.EditMatch("admin", domain.Match{ID: first.ID, PlayerA: player1, PlayerB: player2, Winner: player2, Date: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Winner.ID != player2.ID {
+		t.Errorf("EditMatch() winner = %v, want player2", edited.Winner.Name)
+	}
+	// player1 beats player3 as the underdog after losing the first match
+	want := map[uuid.UUID]float64{player1.ID: 1001, player2.ID: 1020, player3.ID: 979}
+	if got := ratings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings after edit = %v, want %v", got, want)
+	}
+	if _, err = s.EditMatch("admin", domain.Match{ID: first.ID, PlayerA: player1, PlayerB: player1, Date: start}); err == nil {
+		t.Errorf("edit to a match of the same player must fail")
+	}
+
+	if err = s.VoidMatch("admin", first.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	want = map[uuid.UUID]float64{player1.ID: 1020, player2.ID: 1000, player3.ID: 980}
+	if got := ratings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings after void = %v, want %v", got, want)
+	}
+	if match, _ := s.GetMatch(first.ID); !match.Voided {
+		t.Errorf("voided match is not listed as void")
+	}
+
+	if err = s.DeleteMatch("admin", first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.GetMatch(first.ID); err == nil {
+		t.Errorf("deleted match is still found")
+	}
+	if got := ratings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings after delete = %v, want %v", got, want)
+	}
+
+	changes, err := s.GetMatchChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, change := range changes {
+		if change.Author != "admin" || change.MatchID != first.ID {
+			t.Errorf("change %+v, want made by admin to the first match", change)
+		}
+		actions = append(actions, change.Action)
+	}
+	if want := []string{domain.MatchDeleted, domain.MatchVoided, domain.MatchEdited}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("GetMatchChanges() actions = %v, want %v", actions, want)
+	}
+}
+
+func TestPlayerService_BackdatedMatch(t *testing.T) {
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	start := time.Now().Add(-24 * time.Hour)
+	matches := []domain.Match{
+		{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start},
+		{PlayerA: player2, PlayerB: player3, Winner: player2, Date: start.Add(time.Hour)},
+		{PlayerA: player3, PlayerB: player1, Winner: player3, Date: start.Add(2 * time.Hour)},
+	}
+	ratings := func(s *PlayerService) map[uuid.UUID]float64 {
+		got := make(map[uuid.UUID]float64)
+		for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+			got[player.ID] = player.PrimaryRating().Value
+		}
+		return got
+	}
+
+	// the ratings replayed from the matches recorded in the order they were played
+	ordered := &memStorage{players: []domain.Player{player1, player2, player3}}
+	ps, err := New(ordered, ordered, ordered, ordered, ordered, ordered, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, match := range matches {
+		if _, err = ps.CreateMatch(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := ratings(ps)
+
+	st := &memStorage{players: []domain.Player{player1, player2, player3}}
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int{2, 1} {
+		if _, err = s.CreateMatch(matches[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	created, err := s.CreateMatch(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ratings(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings after a backdated match = %v, want %v", got, want)
+	}
+	if created.PlayerA.PrimaryRating().Change == 0 {
+		t.Errorf("CreateMatch() returned the backdated match without the rating change")
+	}
+	listed, err := s.GetMatches(domain.DefaultDisciplineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range listed {
+		if want := matches[len(matches)-1-i].Date; !listed[i].Date.Equal(want) {
+			t.Errorf("GetMatches()[%d] played at %v, want %v", i, listed[i].Date, want)
+		}
+	}
+
+	if _, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: time.Now().Add(time.Hour)}); err == nil {
+		t.Errorf("CreateMatch() of a match in the future must fail")
+	}
+
+	imported := &memStorage{players: []domain.Player{player1, player2, player3}}
+	s, err = New(imported, imported, imported, imported, imported, imported, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.ImportMatches([]domain.Match{matches[2], matches[0], matches[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ratings(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("ratings after import = %v, want %v", got, want)
+	}
+	if err = s.ImportMatches([]domain.Match{{PlayerA: player1, PlayerB: player2, Winner: player1}}); err == nil {
+		t.Errorf("ImportMatches() of a match without the date must fail")
+	}
+}
+
+func TestPlayerService_PlayerLifecycle(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	duplicate := domain.Player{ID: uuid.New(), Name: "Player 2"}
+	st.players = []domain.Player{player1, player2, duplicate}
+	start := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC)
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+	_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: duplicate, Winner: player1, Date: start.Add(time.Hour)})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := 0
+	errRelink := errors.New("relink failed")
+	s.OnPlayersMerged(func(from, to uuid.UUID) error {
+		if from != duplicate.ID || to != player2.ID {
+			t.Errorf("merge hook called with %v, %v", from, to)
+		}
+		merged++
+		return errRelink
+	})
+
+	if _, err = s.RenamePlayer(player1.ID, "PLAYER2"); err == nil {
+		t.Errorf("RenamePlayer() to the normalized name of another player must fail")
+	}
+	renamed, err := s.RenamePlayer(player1.ID, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetByName(domain.DefaultDisciplineID, "first"); got.ID != renamed.ID || got.GamesPlayed != 2 {
+		t.Errorf("GetByName() after rename = %+v, want the player with 2 games", got)
+	}
+	matches, _ := s.GetMatches(domain.DefaultDisciplineID)
+	if matches[0].PlayerA.Name != "first" {
+		t.Errorf("match player name = %v, want first", matches[0].PlayerA.Name)
+	}
+
+	if err = s.MergePlayers(duplicate.ID, player2.ID); !errors.Is(err, errRelink) {
+		t.Fatalf("MergePlayers() with a failed hook = %v, want %v", err, errRelink)
+	}
+	if _, err = s.GetPlayer(duplicate.ID); err != nil {
+		t.Errorf("the player is removed though the merge hook failed: %v", err)
+	}
+	errRelink = nil
+	if err = s.MergePlayers(duplicate.ID, player2.ID); err != nil {
+		t.Fatal(err)
+	}
+	if merged != 2 {
+		t.Errorf("merge hook called %d times, want 2", merged)
+	}
+	if _, err = s.GetByName(domain.DefaultDisciplineID, "Player 2"); err == nil {
+		t.Errorf("merged player is still found")
+	}
+	if got, _ := s.GetByName(domain.DefaultDisciplineID, "player2"); got.GamesPlayed != 2 {
+		t.Errorf("games of the merged player = %d, want 2", got.GamesPlayed)
+	}
+	if err = s.MergePlayers(player1.ID, player2.ID); err == nil {
+		t.Errorf("MergePlayers() of the players of the same match must fail")
+	}
+
+	if err = s.DeactivatePlayer(player2.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+		if player.ID == player2.ID {
+			t.Errorf("deactivated player is on the leaderboard")
+		}
+	}
+	if got, _ := s.GetByName(domain.DefaultDisciplineID, "player2"); !got.Deactivated || got.GamesPlayed != 2 {
+		t.Errorf("deactivated player = %+v, want deactivated with the games kept", got)
+	}
+	if err = s.DeactivatePlayer(player2.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.GetRatings(domain.DefaultDisciplineID)); got != 2 {
+		t.Errorf("leaderboard has %d players after activation, want 2", got)
+	}
+}
+
+func TestPlayerService_HeadToHead(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	player4 := domain.Player{ID: uuid.New(), Name: "player4"}
+	st.players = []domain.Player{player1, player2, player3, player4}
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	matches := []domain.Match{
+		{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start},
+		{PlayerA: player2, PlayerB: player1, Date: start.Add(time.Minute)},
+		{PlayerA: player1, PlayerB: player3, Winner: player3, Date: start.Add(2 * time.Minute)},
+		{PlayerA: player1, PlayerB: player3, TeammatesA: []domain.Player{player2}, TeammatesB: []domain.Player{player4}, Winner: player1, Date: start.Add(3 * time.Minute)},
+		{PlayerA: player2, PlayerB: player1, Winner: player2, Date: start.Add(4 * time.Minute)},
+	}
+	for _, match := range matches {
+		if _, err = s.CreateMatch(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = s.VoidMatch("test", 5, true); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.CreateFFAMatch(domain.FFAMatch{Date: start.Add(5 * time.Minute), Placements: []domain.Placement{
+		{Player: player3, Place: 1},
+		{Player: player2, Place: 2},
+		{Player: player1, Place: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h2h, err := s.HeadToHead(domain.DefaultDisciplineID, player1.ID, player2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, match := range h2h.Matches {
+		ids = append(ids, match.ID)
+	}
+	if want := []int{5, 2, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("matches = %v, want %v: teammates are not opponents, the latest first", ids, want)
+	}
+	if len(h2h.FFAMatches) != 1 {
+		t.Errorf("free-for-all matches = %d, want 1", len(h2h.FFAMatches))
+	}
+	if h2h.WinsA != 1 || h2h.Draws != 1 || h2h.WinsB != 1 || h2h.Total() != 3 {
+		t.Errorf("totals = %d:%d:%d, want 1:1:1 without the voided match", h2h.WinsA, h2h.Draws, h2h.WinsB)
+	}
+	if len(h2h.Prediction.Systems) != 1 {
+		t.Errorf("prediction systems = %d, want 1", len(h2h.Prediction.Systems))
+	}
+	if len(h2h.TrendA) != 6 || h2h.TrendA[0].Value != 1000 {
+		t.Errorf("trend of player1 = %+v, want the initial rating and 5 counted matches", h2h.TrendA)
+	}
+	if last := h2h.TrendA[len(h2h.TrendA)-1].Value; last != h2h.PlayerA.PrimaryRating().Value {
+		t.Errorf("trend of player1 ends with %v, want the current rating %v", last, h2h.PlayerA.PrimaryRating().Value)
+	}
+	if _, err = s.HeadToHead(domain.DefaultDisciplineID, player1.ID, player1.ID); err == nil {
+		t.Errorf("comparing the player with themselves must fail")
+	}
+}
+
+func TestPlayerService_Achievements(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	st.players = []domain.Player{player1, player2}
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notified []domain.AchievementKind
+	s.OnAchievementsUnlocked(func(achievements []domain.Achievement) {
+		// the hooks may be slow, they must not hold up the other changes
+		if !s.mu.TryLock() {
+			t.Errorf("achievement hook called while holding mu")
+		} else {
+			s.mu.Unlock()
+		}
+		for _, achievement := range achievements {
+			notified = append(notified, achievement.Kind)
+		}
+	})
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 8; i++ {
+		_, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []domain.AchievementKind{domain.AchievementFirstWin, domain.AchievementWinStreak5}
+	if !reflect.DeepEqual(notified, want) {
+		t.Errorf("notified = %v, want %v", notified, want)
+	}
+	notified = nil
+	_, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player2, Date: start.Add(10 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []domain.AchievementKind{domain.AchievementFirstWin, domain.AchievementUpset, domain.AchievementBeatLeader}
+	if !reflect.DeepEqual(notified, want) {
+		t.Errorf("notified = %v, want %v", notified, want)
+	}
+	// a backdated match replays the history, the achievements unlocked before are not new
+	notified = nil
+	_, err = s.CreateMatch(domain.Match{PlayerA: player1, PlayerB: player2, Date: start.Add(-time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notified) != 0 {
+		t.Errorf("notified = %v after a backdated draw, want none", notified)
+	}
+
+	tests := []struct {
+		name          string
+		id            uuid.UUID
+		longestStreak int
+		currentStreak int
+		achievements  int
+	}{
+		{name: "winner of the streak", id: player1.ID, longestStreak: 8, currentStreak: -1, achievements: 2},
+		{name: "winner of the upset", id: player2.ID, longestStreak: 1, currentStreak: 1, achievements: 3},
+	}
+	for _, tt := range tests {
+		r, err := s.GetRecords(domain.DefaultDisciplineID, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.LongestWinStreak != tt.longestStreak || r.CurrentStreak != tt.currentStreak || len(r.Achievements) != tt.achievements {
+			t.Errorf("%s: records = %d, %d, %d achievements, want %d, %d, %d", tt.name,
+				r.LongestWinStreak, r.CurrentStreak, len(r.Achievements), tt.longestStreak, tt.currentStreak, tt.achievements)
+		}
+	}
+	r, _ := s.GetRecords(domain.DefaultDisciplineID, player2.ID)
+	if r.BiggestUpset.MatchID != 9 || r.BiggestUpset.Probability > domain.UpsetProbability {
+		t.Errorf("biggest upset = %+v, want the last match", r.BiggestUpset)
+	}
+	r, _ = s.GetRecords(domain.DefaultDisciplineID, player1.ID)
+	if !r.PeakRatingDate.Equal(start.Add(7 * time.Minute)) {
+		t.Errorf("peak rating at %v, want after the last win", r.PeakRatingDate)
+	}
+}
+
+func TestPlayerService_Provisional(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	player4 := domain.Player{ID: uuid.New(), Name: "player4"}
+	st.players = []domain.Player{player1, player2, player3, player4}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start.Add(time.Duration(i) * time.Minute)})
+	}
+	_, _ = st.Create(domain.Match{PlayerA: player3, PlayerB: player1, Winner: player3, Date: start.Add(10 * time.Minute)})
+
+	tests := []struct {
+		name        string
+		cfg         config.Rating
+		ranked      []string
+		provisional []string
+	}{
+		{
+			name:   "disabled",
+			cfg:    config.Rating{Systems: []string{rating.Elo, rating.Glicko2}},
+			ranked: []string{"player1", "player3", "player4", "player2"},
+		},
+		{
+			name:        "too few games",
+			cfg:         config.Rating{Systems: []string{rating.Elo, rating.Glicko2}, ProvisionalGames: 3},
+			ranked:      []string{"player1", "player2"},
+			provisional: []string{"player3", "player4"},
+		},
+		{
+			name:        "too high deviation",
+			cfg:         config.Rating{Systems: []string{rating.Elo, rating.Glicko2}, ProvisionalDeviation: 349},
+			ranked:      []string{"player1", "player3", "player2"},
+			provisional: []string{"player4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			systems, err := rating.New(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := New(st, st, st, st, st, st, mem.New(), systems, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ranked, provisional []string
+			for _, player := range s.GetRatings(domain.DefaultDisciplineID) {
+				if player.Provisional {
+					if player.RatingRank != 0 {
+						t.Errorf("provisional %s is ranked %d", player.Name, player.RatingRank)
+					}
+					provisional = append(provisional, player.Name)
+					continue
+				}
+				if len(provisional) > 0 {
+					t.Errorf("established %s is listed after the provisional players", player.Name)
+				}
+				if player.RatingRank != len(ranked)+1 {
+					t.Errorf("%s is ranked %d, want %d", player.Name, player.RatingRank, len(ranked)+1)
+				}
+				ranked = append(ranked, player.Name)
+			}
+			if !reflect.DeepEqual(ranked, tt.ranked) || !reflect.DeepEqual(provisional, tt.provisional) {
+				t.Errorf("GetRatings() = %v and provisional %v, want %v and %v", ranked, provisional, tt.ranked, tt.provisional)
+			}
+		})
+	}
+}
+
+func TestPlayerService_Leaderboard(t *testing.T) {
+	st := &memStorage{}
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	player4 := domain.Player{ID: uuid.New(), Name: "player4"}
+	st.players = []domain.Player{player1, player2, player3, player4}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		winner := player1
+		if i%3 == 0 {
+			winner = player2
+		}
+		_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: winner, Date: start.Add(time.Duration(i) * time.Minute)})
+	}
+	_, _ = st.Create(domain.Match{PlayerA: player3, PlayerB: player4, Winner: player3, Date: start.Add(20 * time.Minute)})
+
+	cfg := config.Rating{Systems: []string{rating.Elo, rating.Glicko2}}
+	systems, err := rating.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(st, st, st, st, st, st, mem.New(), systems, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, leaderboard := range s.Leaderboards() {
+		names = append(names, leaderboard.Name)
+	}
+	if want := []string{rating.Elo, rating.Glicko2, ConservativeLeaderboard}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Leaderboards() = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		leaderboard string
+		score       func(player domain.Player) float64
+		first       string
+	}{
+		{
+			leaderboard: rating.Elo,
+			score:       func(player domain.Player) float64 { return player.Ratings[0].Value },
+			first:       "player1",
+		},
+		{
+			leaderboard: rating.Glicko2,
+			score:       func(player domain.Player) float64 { return player.Ratings[1].Value },
+			first:       "player3",
+		},
+		{
+			leaderboard: ConservativeLeaderboard,
+			score:       func(player domain.Player) float64 { return player.Ratings[1].Value - 2*player.Ratings[1].Deviation },
+			first:       "player1",
+		},
+	}
+	for _, tt := range tests {
+		entries, err := s.Leaderboard(domain.DefaultDisciplineID, tt.leaderboard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 4 || entries[0].Player.Name != tt.first {
+			t.Errorf("%s: leaderboard = %+v, want %s first", tt.leaderboard, entries, tt.first)
+			continue
+		}
+		for i, entry := range entries {
+			if entry.Rank != i+1 {
+				t.Errorf("%s: %s is ranked %d, want %d", tt.leaderboard, entry.Player.Name, entry.Rank, i+1)
+			}
+			if want := tt.score(entry.Player); math.Abs(entry.Score.Value-want) > 1e-9 {
+				t.Errorf("%s: score of %s = %v, want %v", tt.leaderboard, entry.Player.Name, entry.Score.Value, want)
+			}
+			if i > 0 && entry.Score.Value > entries[i-1].Score.Value {
+				t.Errorf("%s: %s is ranked below a lower score", tt.leaderboard, entry.Player.Name)
+			}
+		}
+	}
+	if _, err = s.Leaderboard(domain.DefaultDisciplineID, rating.TrueSkill); err == nil {
+		t.Errorf("leaderboard of a system not configured must fail")
+	}
+}
+
+func TestPlayerService_Simulate(t *testing.T) {
+	player1 := domain.Player{ID: uuid.New(), Name: "player1"}
+	player2 := domain.Player{ID: uuid.New(), Name: "player2"}
+	player3 := domain.Player{ID: uuid.New(), Name: "player3"}
+	start := time.Now().Add(-time.Hour)
+	newStorage := func() *memStorage {
+		st := &memStorage{players: []domain.Player{player1, player2, player3}}
+		_, _ = st.Create(domain.Match{PlayerA: player1, PlayerB: player2, Winner: player1, Date: start})
+		_, _ = st.Create(domain.Match{PlayerA: player2, PlayerB: player3, Winner: player2, Date: start.Add(time.Minute)})
+		return st
+	}
+	night := []domain.Match{
+		{PlayerA: player3, PlayerB: player1, Winner: player3},
+		{PlayerA: player3, PlayerB: player2, Winner: player3},
+	}
+
+	st := newStorage()
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	simulation, err := s.Simulate(domain.DefaultDisciplineID, night)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.matches) != 2 || len(st.history) != 4 {
+		t.Errorf("simulation saved %d matches and %d rating changes, want none", len(st.matches)-2, len(st.history)-4)
+	}
+	if got := s.GetRatings(domain.DefaultDisciplineID)[0].Name; got != "player1" {
+		t.Errorf("leader after the simulation = %s, want player1 unchanged", got)
+	}
+
+	// the same matches recorded for real give the same ratings
+	recorded := newStorage()
+	rs, err := New(recorded, recorded, recorded, recorded, recorded, recorded, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, match := range night {
+		if _, err = rs.CreateMatch(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := make(map[uuid.UUID]domain.Player)
+	for _, player := range rs.GetRatings(domain.DefaultDisciplineID) {
+		want[player.ID] = player
+	}
+	if len(simulation.Players) != len(want) {
+		t.Fatalf("simulated %d players, want %d", len(simulation.Players), len(want))
+	}
+	for _, p := range simulation.Players {
+		w := want[p.After.ID]
+		if p.After.PrimaryRating().Value != w.PrimaryRating().Value || p.After.RatingRank != w.RatingRank {
+			t.Errorf("%s: simulated %v at %d, recorded %v at %d", p.After.Name,
+				p.After.PrimaryRating().Value, p.After.RatingRank, w.PrimaryRating().Value, w.RatingRank)
+		}
+		if change := p.Ratings()[0].Change; change != p.After.PrimaryRating().Value-p.Before.PrimaryRating().Value {
+			t.Errorf("%s: change = %v, want the difference of the ratings", p.After.Name, change)
+		}
+	}
+	if winner := simulation.Players[0]; winner.After.ID != player3.ID || winner.RankChange() != 2 || !winner.Played() {
+		t.Errorf("player3 must move up from the third place to the first, got %+v", winner)
+	}
+	if _, err = s.Simulate(domain.DefaultDisciplineID, nil); err == nil {
+		t.Errorf("simulation without matches must fail")
+	}
+}
+
+// withStaleCache makes the cache outdated and fails the test if the call does not return,
+// as the cache refreshed while holding mu deadlocks.
+func withStaleCache(t *testing.T, s *PlayerService, call func() error) error {
+	t.Helper()
+	s.cacheUpdatedAt.Store(0)
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("the call with the stale cache did not return")
+		return nil
+	}
+}
+
+func TestPlayerService_Tournament(t *testing.T) {
+	players := make([]domain.Player, 4)
+	for i := range players {
+		players[i] = domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i+1)}
+	}
+	st := &memStorage{players: players}
+	start := time.Now().Add(-time.Hour)
+	// player4 is the strongest before the tournament, player1 the weakest
+	_, _ = st.Create(domain.Match{PlayerA: players[3], PlayerB: players[0], Winner: players[3], Date: start})
+	_, _ = st.Create(domain.Match{PlayerA: players[3], PlayerB: players[1], Winner: players[3], Date: start.Add(time.Minute)})
+	_, _ = st.Create(domain.Match{PlayerA: players[2], PlayerB: players[0], Winner: players[2], Date: start.Add(2 * time.Minute)})
+
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cup, err := s.CreateTournament(domain.Tournament{Name: "cup", Format: domain.TournamentSingleElimination, Players: players})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cup.Players[0].ID != players[3].ID || cup.Players[3].ID != players[0].ID {
+		t.Fatalf("seeds = %v, want the strongest player first", cup.Players)
+	}
+	if len(cup.Games) != 2 || !cup.Games[0].Has(players[3].ID, players[0].ID) {
+		t.Fatalf("first round = %v, want the first seed against the last", cup.Games)
+	}
+	if _, err = s.CreateTournament(domain.Tournament{Name: "Cup", Format: domain.TournamentRoundRobin, Players: players}); err == nil {
+		t.Errorf("CreateTournament() with the name of another tournament must fail")
+	}
+	if _, err = s.RecordTournamentMatch(cup.ID, domain.Match{PlayerA: players[3], PlayerB: players[1], Winner: players[3]}); err == nil {
+		t.Errorf("RecordTournamentMatch() of players not paired must fail")
+	}
+	if _, err = s.RecordTournamentMatch(cup.ID, domain.Match{PlayerA: players[3], PlayerB: players[0]}); err == nil {
+		t.Errorf("RecordTournamentMatch() of a draw in the elimination must fail")
+	}
+
+	before := s.GetRatings(domain.DefaultDisciplineID)
+	// the underdogs win the semifinals, player1 wins the final
+	for _, result := range [][2]domain.Player{{players[0], players[3]}, {players[1], players[2]}, {players[0], players[1]}} {
+		match, err := s.RecordTournamentMatch(cup.ID, domain.Match{PlayerA: result[0], PlayerB: result[1], Winner: result[0]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match.PlayerA.PrimaryRating().Change <= 0 {
+			t.Errorf("tournament match %d did not change the ratings", match.ID)
+		}
+	}
+	if len(st.matches) != 6 {
+		t.Errorf("%d matches saved, want the 3 tournament ones added", len(st.matches))
+	}
+	if after := s.GetRatings(domain.DefaultDisciplineID); reflect.DeepEqual(after, before) {
+		t.Errorf("the ratings did not change after the tournament")
+	}
+	cup, err = s.Tournament(cup.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if champion, ok := cup.Champion(); !cup.Finished() || !ok || champion.ID != players[0].ID {
+		t.Errorf("Champion() = %v, %v of a tournament finished %v, want player1", champion.Name, ok, cup.Finished())
+	}
+	if _, err = s.RecordTournamentMatch(cup.ID, domain.Match{PlayerA: players[0], PlayerB: players[1], Winner: players[0]}); err == nil {
+		t.Errorf("RecordTournamentMatch() in a finished tournament must fail")
+	}
+
+	var swiss domain.Tournament
+	err = withStaleCache(t, s, func() (err error) {
+		swiss, err = s.CreateTournament(domain.Tournament{Name: "swiss", Format: domain.TournamentSwiss, Players: players[:3]})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swiss.Rounds != 2 || swiss.Round() != 1 || len(swiss.Pending()) != 1 {
+		t.Fatalf("swiss tournament = %d rounds, round %d with %d pending games, want round 1 of 2 with a bye",
+			swiss.Rounds, swiss.Round(), len(swiss.Pending()))
+	}
+	game := swiss.Pending()[0]
+	var played domain.Match
+	err = withStaleCache(t, s, func() (err error) {
+		played, err = s.RecordTournamentMatch(swiss.ID, domain.Match{PlayerA: game.PlayerA, PlayerB: game.PlayerB, Winner: game.PlayerA})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swiss, _ = s.Tournament(swiss.ID); swiss.Round() != 2 {
+		t.Errorf("round %d after the first one is played, want the second one paired", swiss.Round())
+	}
+	if err = s.VoidMatch("test", played.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if swiss, _ = s.Tournament(swiss.ID); swiss.Standings[0].Points != 1 || len(swiss.Pending()) != 2 {
+		t.Errorf("standings after the match is voided = %+v, want the game unplayed", swiss.Standings)
+	}
+
+	if err = s.MergePlayers(players[1].ID, players[0].ID); err == nil {
+		t.Errorf("MergePlayers() of the players of a tournament must fail")
+	}
+}
+
+func TestPlayerService_Matchmaking(t *testing.T) {
+	players := make([]domain.Player, 5)
+	ids := make([]uuid.UUID, len(players))
+	for i := range players {
+		players[i] = domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i+1)}
+		ids[i] = players[i].ID
+	}
+	st := &memStorage{players: players}
+	start := time.Now().Add(-time.Hour)
+	// player3 and player4 lose to player5, so they are weaker than player1 and player2
+	for i, result := range [][2]int{{4, 2}, {4, 3}, {4, 2}, {4, 3}} {
+		_, _ = st.Create(domain.Match{PlayerA: players[result[0]], PlayerB: players[result[1]], Winner: players[result[0]],
+			Date: start.Add(time.Duration(i) * time.Minute)})
+	}
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := func(m domain.Matchmaking) map[string]bool {
+		pairs := make(map[string]bool)
+		for _, p := range m.Pairings {
+			a, b := p.Prediction.PlayerA.Name, p.Prediction.PlayerB.Name
+			if b < a {
+				a, b = b, a
+			}
+			pairs[a+" "+b] = true
+		}
+		return pairs
+	}
+
+	m, err := s.Matchmaking(domain.DefaultDisciplineID, ids[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"player1 player2": true, "player3 player4": true}; !reflect.DeepEqual(pairs(m), want) || m.Resting != nil {
+		t.Errorf("pairings = %v, want the equal players paired", pairs(m))
+	}
+	if p := m.Pairings[0]; p.Quality != 1 || p.Since != -1 || p.Outcomes().WinA.Probability != p.Outcomes().WinB.Probability {
+		t.Errorf("pairing of the equal players = %+v, want the quality 1 of the players never met", p)
+	}
+
+	// the equal players have just met, so the second best pairings are proposed
+	_, err = s.CreateMatch(domain.Match{PlayerA: players[0], PlayerB: players[1], Date: start.Add(10 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = s.Matchmaking(domain.DefaultDisciplineID, ids[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pairs(m); got["player1 player2"] || len(got) != 2 {
+		t.Errorf("pairings = %v, want no rematch", got)
+	}
+
+	m, err = s.Matchmaking(domain.DefaultDisciplineID, append(ids, ids[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Pairings) != 2 || m.Resting == nil {
+		t.Errorf("matchmaking of 5 players = %d pairings, resting %v, want 2 pairings and one resting", len(m.Pairings), m.Resting)
+	}
+	if _, err = s.Matchmaking(domain.DefaultDisciplineID, ids[:1]); err == nil {
+		t.Errorf("Matchmaking() of a single player must fail")
+	}
+}
+
+func Test_bestPairs(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name    string
+		costs   [][]float64
+		pairs   [][2]int
+		resting int
+	}{
+		{
+			name:    "two",
+			costs:   [][]float64{{0, 1}, {0, 0}},
+			pairs:   [][2]int{{0, 1}},
+			resting: -1,
+		},
+		{
+			name:    "the least total cost, not the least first pair",
+			costs:   [][]float64{{0, 1, 2, 5}, {0, 0, 5, 2}, {0, 0, 0, 10}, {0, 0, 0, 0}},
+			pairs:   [][2]int{{0, 2}, {1, 3}},
+			resting: -1,
+		},
+		{
+			name:    "the impossible pairs are avoided",
+			costs:   [][]float64{{0, inf, 3, 3}, {0, 0, 3, 3}, {0, 0, 0, inf}, {0, 0, 0, 0}},
+			pairs:   [][2]int{{0, 2}, {1, 3}},
+			resting: -1,
+		},
+		{
+			name:    "the odd one rests",
+			costs:   [][]float64{{0, 1, 1}, {0, 0, 0}, {0, 0, 0}},
+			pairs:   [][2]int{{1, 2}},
+			resting: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, resting := bestPairs(tt.costs)
+			if !reflect.DeepEqual(pairs, tt.pairs) || resting != tt.resting {
+				t.Errorf("bestPairs() = %v, %d, want %v, %d", pairs, resting, tt.pairs, tt.resting)
+			}
+		})
+	}
+}
+
+func TestPlayerService_Ladder(t *testing.T) {
+	players := make([]domain.Player, 4)
+	for i := range players {
+		players[i] = domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i+1)}
+	}
+	st := &memStorage{players: players}
+	s, err := New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Ladder(domain.DefaultDisciplineID); err == nil {
+		t.Errorf("Ladder() must fail while the ladder is disabled")
+	}
+	s, err = New(st, st, st, st, st, st, mem.New(), eloSystems(t), config.Rating{Ladder: config.Ladder{Enabled: true, Reach: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range players {
+		err = withStaleCache(t, s, func() error {
+			return s.JoinLadder(domain.DefaultDisciplineID, player.ID)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = s.JoinLadder(domain.DefaultDisciplineID, players[0].ID); err == nil {
+		t.Errorf("JoinLadder() of a player on the ladder must fail")
+	}
+	positions := func() []string {
+		l, err := s.Ladder(domain.DefaultDisciplineID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, rung := range l.Rungs {
+			names = append(names, rung.Player.Name)
+		}
+		return names
+	}
+	challenged := func(challenger, challenged int) domain.LadderChallenge {
+		t.Helper()
+		var c domain.LadderChallenge
+		err := withStaleCache(t, s, func() (err error) {
+			c, err = s.Challenge(domain.DefaultDisciplineID, players[challenger].ID, players[challenged].ID)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	for _, pair := range [][2]int{{3, 0}, {0, 1}, {2, 2}} {
+		if _, err = s.Challenge(domain.DefaultDisciplineID, players[pair[0]].ID, players[pair[1]].ID); err == nil {
+			t.Errorf("Challenge() of %s to %s must fail", players[pair[0]].Name, players[pair[1]].Name)
+		}
+	}
+	first := challenged(3, 1)
+	if first.Status != domain.LadderOpen || first.Deadline.Sub(first.CreatedAt) != defaultLadderDeadline {
+		t.Errorf("challenge = %+v, want open for the default deadline", first)
+	}
+	if _, err = s.Challenge(domain.DefaultDisciplineID, players[2].ID, players[1].ID); err == nil {
+		t.Errorf("Challenge() of a player with an open challenge must fail")
+	}
+	if _, err = s.RecordLadderMatch(first.ID, domain.Match{PlayerA: players[3], PlayerB: players[2], Winner: players[3]}); err == nil {
+		t.Errorf("RecordLadderMatch() of other players must fail")
+	}
+	var match domain.Match
+	err = withStaleCache(t, s, func() (err error) {
+		match, err = s.RecordLadderMatch(first.ID, domain.Match{PlayerA: players[3], PlayerB: players[1], Winner: players[3]})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.PlayerA.PrimaryRating().Change <= 0 {
+		t.Errorf("the ladder match did not change the ratings")
+	}
+	if got, want := positions(), []string{"player1", "player4", "player2", "player3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("positions = %v, want %v", got, want)
+	}
+
+	// player2 does not answer in time
+	forfeit := challenged(2, 1)
+	s.challenges[forfeit.ID-1].Deadline = time.Now()
+	if got, want := positions(), []string{"player1", "player4", "player3", "player2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("positions after the forfeit = %v, want %v", got, want)
+	}
+
+	accepted := challenged(1, 2)
+	err = withStaleCache(t, s, func() (err error) {
+		accepted, err = s.AcceptChallenge(accepted.ID)
+		return err
+	})
+	if err != nil || accepted.Status != domain.LadderAccepted {
+		t.Fatalf("AcceptChallenge() = %v, %v", accepted.Status, err)
+	}
+	if _, err = s.AcceptChallenge(accepted.ID); err == nil {
+		t.Errorf("AcceptChallenge() of an accepted challenge must fail")
+	}
+	s.challenges[accepted.ID-1].Deadline = time.Now()
+	if accepted, _ = s.challenge(accepted.ID); accepted.Status != domain.LadderExpired {
+		t.Errorf("status of the accepted challenge after the deadline = %v, want expired", accepted.Status)
+	}
+	if got, want := positions(), []string{"player1", "player4", "player3", "player2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("positions after the challenge expired = %v, want %v", got, want)
+	}
+	if _, err = s.RecordLadderMatch(accepted.ID, domain.Match{PlayerA: players[1], PlayerB: players[2], Winner: players[1]}); err == nil {
+		t.Errorf("RecordLadderMatch() of an expired challenge must fail")
+	}
+
+	// the expired challenge does not block the players
+	cancelled := challenged(1, 2)
+	if _, err = s.CancelChallenge(cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.RecordLadderMatch(cancelled.ID, domain.Match{PlayerA: players[1], PlayerB: players[2], Winner: players[1]}); err == nil {
+		t.Errorf("RecordLadderMatch() of a cancelled challenge must fail")
+	}
+
+	if err = s.VoidMatch("test", match.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if first, _ = s.challenge(first.ID); first.Status != domain.LadderAccepted {
+		t.Errorf("status of the challenge with the voided match = %v, want accepted", first.Status)
+	}
+	if err = s.MergePlayers(players[1].ID, players[0].ID); err == nil {
+		t.Errorf("MergePlayers() of the players on a ladder must fail")
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goserg/ratingserver/internal/domain"
+	"github.com/goserg/ratingserver/internal/normalize"
+	"github.com/goserg/ratingserver/internal/tournament"
+
+	"github.com/google/uuid"
+)
+
+// Tournaments returns all the tournaments with the games and the standings, the latest first.
+func (s *PlayerService) Tournaments() ([]domain.Tournament, error) {
+	s.refreshCache()
+	s.disciplinesMu.RLock()
+	stored := make([]domain.Tournament, len(s.tournaments))
+	copy(stored, s.tournaments)
+	s.disciplinesMu.RUnlock()
+
+	tournaments := make([]domain.Tournament, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		t, err := s.resolveTournament(stored[i])
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, nil
+}
+
+// Tournament returns the tournament with the games and the standings.
+func (s *PlayerService) Tournament(id int) (domain.Tournament, error) {
+	s.refreshCache()
+	return s.tournament(id)
+}
+
+// tournament is Tournament without refreshing the cache, for the callers holding mu.
+func (s *PlayerService) tournament(id int) (domain.Tournament, error) {
+	s.disciplinesMu.RLock()
+	var stored domain.Tournament
+	for _, t := range s.tournaments {
+		if t.ID == id {
+			stored = t
+		}
+	}
+	s.disciplinesMu.RUnlock()
+
+	if stored.ID == 0 {
+		return domain.Tournament{}, errors.New("турнир " + strconv.Itoa(id) + " не найден")
+	}
+	return s.resolveTournament(stored)
+}
+
+// resolveTournament adds the players, the recorded matches, the bracket games and the standings to the stored tournament.
+// A game is played while its match is not voided and is between the players of the game.
+func (s *PlayerService) resolveTournament(t domain.Tournament) (domain.Tournament, error) {
+	h, err := s.history(t.DisciplineID)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	matches := make(map[int]domain.Match)
+	for _, match := range h.listMatches() {
+		matches[match.ID] = match
+	}
+	players := make(map[uuid.UUID]domain.Player, len(t.Players))
+	seeded := make([]domain.Player, 0, len(t.Players))
+	for _, player := range t.Players {
+		if p, err := s.cachedPlayer(t.DisciplineID, player.ID); err == nil {
+			player = p
+		}
+		players[player.ID] = player
+		seeded = append(seeded, player)
+	}
+	t.Players = seeded
+
+	stored := make([]domain.TournamentGame, 0, len(t.Games))
+	for _, game := range t.Games {
+		game.PlayerA = players[game.PlayerA.ID]
+		if !game.Bye() {
+			game.PlayerB = players[game.PlayerB.ID]
+		}
+		if match, ok := matches[game.MatchID]; ok && !match.Voided && !match.IsTeamMatch() && game.Has(match.PlayerA.ID, match.PlayerB.ID) {
+			game.Match = &match
+		}
+		stored = append(stored, game)
+	}
+	t.Games = tournament.Games(t, stored)
+	t.Standings = tournament.Standings(t)
+	return t, nil
+}
+
+// swissRounds returns the default number of the rounds of a swiss tournament,
+// enough for a single player to win all the games.
+func swissRounds(players int) int {
+	rounds := 1
+	for 1<<rounds < players {
+		rounds++
+	}
+	if rounds >= players {
+		rounds = players - 1
+	}
+	return rounds
+}
+
+// CreateTournament seeds the players by the primary rating and saves the tournament with the first games.
+// Only the IDs of the players are used. A swiss tournament without the number of the rounds
+// gets enough of them to find a single winner.
+func (s *PlayerService) CreateTournament(t domain.Tournament) (domain.Tournament, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.Name = strings.TrimSpace(t.Name)
+	t.DisciplineID = disciplineID(t.DisciplineID)
+	switch {
+	case t.Format != domain.TournamentSwiss:
+		t.Rounds = 0
+	case t.Rounds == 0:
+		t.Rounds = swissRounds(len(t.Players))
+	}
+	err := t.Validate()
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	_, err = s.history(t.DisciplineID)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	s.disciplinesMu.RLock()
+	for _, other := range s.tournaments {
+		if normalize.Name(other.Name) == normalize.Name(t.Name) {
+			s.disciplinesMu.RUnlock()
+			return domain.Tournament{}, errors.New("турнир " + t.Name + " уже существует")
+		}
+	}
+	s.disciplinesMu.RUnlock()
+	players := make([]domain.Player, 0, len(t.Players))
+	for _, player := range t.Players {
+		p, err := s.cachedPlayer(t.DisciplineID, player.ID)
+		if err != nil {
+			return domain.Tournament{}, err
+		}
+		if p.Deactivated {
+			return domain.Tournament{}, errors.New("игрок " + p.Name + " деактивирован")
+		}
+		players = append(players, p)
+	}
+	t.Players = players
+	ratings := primaryRatings(t.Players)
+	sort.SliceStable(t.Players, func(i, j int) bool {
+		return ratings[t.Players[i].ID] > ratings[t.Players[j].ID]
+	})
+	t.CreatedAt = time.Now()
+	t.FinishedAt = time.Time{}
+	t.Games = tournament.Pairings(t, ratings)
+	created, err := s.tournamentStorage.AddTournament(t)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	s.disciplinesMu.Lock()
+	s.tournaments = append(s.tournaments, created)
+	s.disciplinesMu.Unlock()
+	return s.tournament(created.ID)
+}
+
+// primaryRatings returns the primary ratings of the players by their IDs.
+func primaryRatings(players []domain.Player) map[uuid.UUID]float64 {
+	ratings := make(map[uuid.UUID]float64, len(players))
+	for _, player := range players {
+		if len(player.Ratings) > 0 {
+			ratings[player.ID] = player.Ratings[0].Value
+		}
+	}
+	return ratings
+}
+
+// RecordTournamentMatch saves the match as the result of the unplayed game of the tournament between its players,
+// so it changes the ratings like any other match. The next swiss round is paired as soon as the current one is played
+// and the tournament is finished with its last game.
+func (s *PlayerService) RecordTournamentMatch(id int, match domain.Match) (domain.Match, error) {
+	s.refreshCache()
+	s.mu.Lock()
+	created, unlocked, err := s.recordTournamentMatch(id, match)
+	s.unlockAndNotify(unlocked)
+	return created, err
+}
+
+// recordTournamentMatch is RecordTournamentMatch holding mu, it also returns the unlocked achievements.
+func (s *PlayerService) recordTournamentMatch(id int, match domain.Match) (domain.Match, []domain.Achievement, error) {
+	t, err := s.tournament(id)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	if t.Finished() {
+		return domain.Match{}, nil, errors.New("турнир " + t.Name + " уже завершён")
+	}
+	if match.IsTeamMatch() {
+		return domain.Match{}, nil, errors.New("в турнире играют один на один")
+	}
+	var game domain.TournamentGame
+	for _, pending := range t.Pending() {
+		if pending.Has(match.PlayerA.ID, match.PlayerB.ID) {
+			game = pending
+		}
+	}
+	if game.PlayerA.ID == uuid.Nil {
+		return domain.Match{}, nil, errors.New("в турнире " + t.Name + " нет несыгранной игры " + match.PlayerA.Name + " и " + match.PlayerB.Name)
+	}
+	if t.Format.Elimination() && match.Winner.ID == uuid.Nil {
+		return domain.Match{}, nil, errors.New("в турнире на выбывание нужен победитель")
+	}
+	match.DisciplineID = t.DisciplineID
+	created, unlocked, err := s.createMatch(match)
+	if err != nil {
+		return domain.Match{}, nil, err
+	}
+	game.MatchID = created.ID
+	err = s.saveTournamentGames(t.ID, []domain.TournamentGame{game})
+	if err != nil {
+		return domain.Match{}, unlocked, err
+	}
+
+	t, err = s.tournament(id)
+	if err != nil {
+		return domain.Match{}, unlocked, err
+	}
+	next := tournament.Pairings(t, primaryRatings(t.Players))
+	if len(next) > 0 {
+		return created, unlocked, s.saveTournamentGames(t.ID, next)
+	}
+	if tournament.Complete(t) {
+		err = s.finishTournament(t.ID, time.Now())
+	}
+	return created, unlocked, err
+}
+
+// saveTournamentGames stores the games replacing the ones in the same places.
+func (s *PlayerService) saveTournamentGames(id int, games []domain.TournamentGame) error {
+	err := s.tournamentStorage.SaveTournamentGames(id, games)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.Lock()
+	defer s.disciplinesMu.Unlock()
+
+	for i := range s.tournaments {
+		if s.tournaments[i].ID != id {
+			continue
+		}
+		stored := make([]domain.TournamentGame, 0, len(s.tournaments[i].Games)+len(games))
+		for _, old := range s.tournaments[i].Games {
+			replaced := false
+			for _, game := range games {
+				replaced = replaced || sameGame(old, game)
+			}
+			if !replaced {
+				stored = append(stored, old)
+			}
+		}
+		for _, game := range games {
+			game.Match = nil
+			stored = append(stored, game)
+		}
+		sort.SliceStable(stored, func(i, j int) bool {
+			if stored[i].Round != stored[j].Round {
+				return stored[i].Round < stored[j].Round
+			}
+			return stored[i].Position < stored[j].Position
+		})
+		s.tournaments[i].Games = stored
+	}
+	return nil
+}
+
+func sameGame(a, b domain.TournamentGame) bool {
+	return a.Bracket == b.Bracket && a.Round == b.Round && a.Position == b.Position
+}
+
+func (s *PlayerService) finishTournament(id int, now time.Time) error {
+	err := s.tournamentStorage.FinishTournament(id, now)
+	if err != nil {
+		return err
+	}
+	s.disciplinesMu.Lock()
+	defer s.disciplinesMu.Unlock()
+
+	for i := range s.tournaments {
+		if s.tournaments[i].ID == id {
+			s.tournaments[i].FinishedAt = now
+		}
+	}
+	return nil
+}
+
+// sharedTournament returns a tournament both players take part in.
+func (s *PlayerService) sharedTournament(a, b uuid.UUID) (domain.Tournament, bool) {
+	s.disciplinesMu.RLock()
+	defer s.disciplinesMu.RUnlock()
+
+	for _, t := range s.tournaments {
+		if containsID(t.Players, a) && containsID(t.Players, b) {
+			return t, true
+		}
+	}
+	return domain.Tournament{}, false
+}
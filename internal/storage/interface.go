@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/goserg/ratingserver/internal/domain"
 
 	"github.com/google/uuid"
@@ -10,13 +12,79 @@ type PlayerStorage interface {
 	ListPlayers() ([]domain.Player, error)
 	Get(uuid.UUID) (domain.Player, error)
 	Add(domain.Player) (domain.Player, error)
+	// UpdatePlayer saves the name and the deactivated flag of the player.
+	UpdatePlayer(domain.Player) error
+	// MergePlayers moves all the matches and the standings of the player from
+	// to the player to and removes the player from.
+	MergePlayers(from, to uuid.UUID) error
 
 	ImportPlayers([]domain.Player) error
 }
 
 type MatchStorage interface {
+	// ListMatches returns the matches in the order they were played, the ID breaks the ties.
 	ListMatches() ([]domain.Match, error)
 	Create(domain.Match) (domain.Match, error)
 
 	ImportMatches([]domain.Match) error
+
+	// UpdateMatch replaces the players, the result, the date and the void flag of the match
+	// and records the change in the same transaction.
+	UpdateMatch(match domain.Match, change domain.MatchChange) error
+	// DeleteMatch removes the match with its teammates and records the change.
+	DeleteMatch(id int, change domain.MatchChange) error
+	// ListMatchChanges returns the audit trail of the matches, the latest first.
+	ListMatchChanges() ([]domain.MatchChange, error)
+
+	// ListFFAMatches returns free-for-all matches in the order they were played
+	// with the placements ordered by place.
+	ListFFAMatches() ([]domain.FFAMatch, error)
+	CreateFFA(domain.FFAMatch) (domain.FFAMatch, error)
+
+	// ListRatingHistory returns entries ordered by match date.
+	ListRatingHistory(domain.RatingHistoryFilter) ([]domain.RatingHistoryEntry, error)
+	// SaveRatingHistory adds entries of new matches.
+	SaveRatingHistory([]domain.RatingHistoryEntry) error
+	// ReplaceRatingHistory drops the stored history and saves the given one.
+	ReplaceRatingHistory([]domain.RatingHistoryEntry) error
+}
+
+type DisciplineStorage interface {
+	// ListDisciplines returns disciplines ordered by ID, the default one first.
+	ListDisciplines() ([]domain.Discipline, error)
+	AddDiscipline(domain.Discipline) (domain.Discipline, error)
+}
+
+type SeasonStorage interface {
+	// ListSeasons returns seasons ordered by start.
+	ListSeasons() ([]domain.Season, error)
+	AddSeason(domain.Season) (domain.Season, error)
+	// ArchiveSeason saves the final standings of all the disciplines and marks the season archived.
+	ArchiveSeason(season domain.Season, standings []domain.SeasonStanding) error
+	// ListSeasonStandings returns the standings of the season in the discipline ordered by place,
+	// the ratings of a player are in no particular order.
+	ListSeasonStandings(seasonID, disciplineID int) ([]domain.SeasonStanding, error)
+}
+
+type TournamentStorage interface {
+	// ListTournaments returns the tournaments ordered by ID with the players ordered by seed
+	// and the stored games ordered by round and position, only the IDs of the players are set.
+	ListTournaments() ([]domain.Tournament, error)
+	// AddTournament saves the tournament with its players and games.
+	AddTournament(domain.Tournament) (domain.Tournament, error)
+	// SaveTournamentGames adds the games replacing the stored ones with the same bracket, round and position.
+	SaveTournamentGames(tournamentID int, games []domain.TournamentGame) error
+	FinishTournament(id int, finishedAt time.Time) error
+}
+
+type LadderStorage interface {
+	// ListLadderEntries returns the players of the ladders in the order they joined,
+	// only the IDs of the players are set.
+	ListLadderEntries() ([]domain.LadderEntry, error)
+	AddLadderEntry(domain.LadderEntry) error
+	// ListChallenges returns the challenges ordered by ID, only the IDs of the players are set.
+	ListChallenges() ([]domain.LadderChallenge, error)
+	AddChallenge(domain.LadderChallenge) (domain.LadderChallenge, error)
+	// UpdateChallenge saves the acceptance, the cancellation and the match of the challenge.
+	UpdateChallenge(domain.LadderChallenge) error
 }
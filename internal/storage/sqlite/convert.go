@@ -28,14 +28,16 @@ func convertPlayerToDomain(player model.Players) (domain.Player, error) {
 		ID:           id,
 		Name:         player.Name,
 		RegisteredAt: player.CreatedAt,
+		Deactivated:  player.Deactivated,
 	}, nil
 }
 
 func convertPlayerFromDomain(player domain.Player) model.Players {
 	return model.Players{
-		ID:        player.ID.String(),
-		Name:      player.Name,
-		CreatedAt: player.RegisteredAt,
+		ID:          player.ID.String(),
+		Name:        player.Name,
+		CreatedAt:   player.RegisteredAt,
+		Deactivated: player.Deactivated,
 	}
 }
 
@@ -70,11 +72,460 @@ func convertMatchToDomain(match model.Matches) (domain.Match, error) {
 			winner = playerB
 		}
 	}
+	var score *domain.Score
+	if match.ScoreA != nil && match.ScoreB != nil {
+		score = &domain.Score{
+			A: int(*match.ScoreA),
+			B: int(*match.ScoreB),
+		}
+	}
 	return domain.Match{
-		ID:      int(match.ID),
-		PlayerA: playerA,
-		PlayerB: playerB,
-		Winner:  winner,
-		Date:    match.CreatedAt,
+		ID:           int(match.ID),
+		DisciplineID: int(match.DisciplineID),
+		PlayerA:      playerA,
+		PlayerB:      playerB,
+		Winner:       winner,
+		Date:         match.PlayedAt,
+		Score:        score,
+		Voided:       match.Voided,
+	}, nil
+}
+
+const (
+	sideA = "a"
+	sideB = "b"
+)
+
+func convertTeammatesFromDomain(match domain.Match) []model.MatchTeammates {
+	teammates := make([]model.MatchTeammates, 0, len(match.TeammatesA)+len(match.TeammatesB))
+	for i, player := range match.TeammatesA {
+		teammates = append(teammates, model.MatchTeammates{
+			MatchID:  int32(match.ID),
+			PlayerID: player.ID.String(),
+			Side:     sideA,
+			Position: int32(i),
+		})
+	}
+	for i, player := range match.TeammatesB {
+		teammates = append(teammates, model.MatchTeammates{
+			MatchID:  int32(match.ID),
+			PlayerID: player.ID.String(),
+			Side:     sideB,
+			Position: int32(i),
+		})
+	}
+	return teammates
+}
+
+// addTeammatesToDomain fills the teammates of the matches, the teammates must be ordered by position.
+func addTeammatesToDomain(matches []domain.Match, teammates []model.MatchTeammates) error {
+	byID := make(map[int]*domain.Match, len(matches))
+	for i := range matches {
+		byID[matches[i].ID] = &matches[i]
+	}
+	for _, teammate := range teammates {
+		match, ok := byID[int(teammate.MatchID)]
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(teammate.PlayerID)
+		if err != nil {
+			return err
+		}
+		if teammate.Side == sideA {
+			match.TeammatesA = append(match.TeammatesA, domain.Player{ID: id})
+		} else {
+			match.TeammatesB = append(match.TeammatesB, domain.Player{ID: id})
+		}
+	}
+	return nil
+}
+
+func convertRatingHistoryToDomain(entries []model.RatingHistory) ([]domain.RatingHistoryEntry, error) {
+	converted := make([]domain.RatingHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		playerID, err := uuid.Parse(entry.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, domain.RatingHistoryEntry{
+			MatchID:         intValue(entry.MatchID),
+			FFAMatchID:      intValue(entry.FfaMatchID),
+			DisciplineID:    int(entry.DisciplineID),
+			PlayerID:        playerID,
+			System:          entry.System,
+			RatingBefore:    entry.RatingBefore,
+			RatingAfter:     entry.RatingAfter,
+			DeviationBefore: entry.DeviationBefore,
+			DeviationAfter:  entry.DeviationAfter,
+			Date:            entry.PlayedAt,
+		})
+	}
+	return converted, nil
+}
+
+func convertRatingHistoryFromDomain(entry domain.RatingHistoryEntry) model.RatingHistory {
+	return model.RatingHistory{
+		MatchID:         int32Ptr(entry.MatchID),
+		FfaMatchID:      int32Ptr(entry.FFAMatchID),
+		DisciplineID:    int32(entry.DisciplineID),
+		PlayerID:        entry.PlayerID.String(),
+		System:          entry.System,
+		RatingBefore:    entry.RatingBefore,
+		RatingAfter:     entry.RatingAfter,
+		DeviationBefore: entry.DeviationBefore,
+		DeviationAfter:  entry.DeviationAfter,
+		PlayedAt:        entry.Date,
+	}
+}
+
+// int32Ptr returns nil for zero, so it is stored as null.
+func int32Ptr(v int) *int32 {
+	if v == 0 {
+		return nil
+	}
+	i := int32(v)
+	return &i
+}
+
+func intValue(v *int32) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
+func convertFFAMatchesToDomain(matches []model.FfaMatches, placements []model.FfaPlacements) ([]domain.FFAMatch, error) {
+	converted := make([]domain.FFAMatch, 0, len(matches))
+	byID := make(map[int32]int, len(matches))
+	for _, match := range matches {
+		byID[match.ID] = len(converted)
+		converted = append(converted, domain.FFAMatch{
+			ID:           int(match.ID),
+			DisciplineID: int(match.DisciplineID),
+			Date:         match.PlayedAt,
+		})
+	}
+	for _, placement := range placements {
+		i, ok := byID[placement.MatchID]
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(placement.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		converted[i].Placements = append(converted[i].Placements, domain.Placement{
+			Player: domain.Player{ID: id},
+			Place:  int(placement.Place),
+		})
+	}
+	return converted, nil
+}
+
+func convertPlacementsFromDomain(match domain.FFAMatch) []model.FfaPlacements {
+	placements := make([]model.FfaPlacements, 0, len(match.Placements))
+	for i, placement := range match.Placements {
+		placements = append(placements, model.FfaPlacements{
+			MatchID:  int32(match.ID),
+			PlayerID: placement.Player.ID.String(),
+			Place:    int32(placement.Place),
+			Position: int32(i),
+		})
+	}
+	return placements
+}
+
+func convertDisciplinesToDomain(disciplines []model.Disciplines) []domain.Discipline {
+	converted := make([]domain.Discipline, 0, len(disciplines))
+	for _, discipline := range disciplines {
+		converted = append(converted, convertDisciplineToDomain(discipline))
+	}
+	return converted
+}
+
+func convertDisciplineToDomain(discipline model.Disciplines) domain.Discipline {
+	return domain.Discipline{
+		ID:        int(discipline.ID),
+		Name:      discipline.Name,
+		CreatedAt: discipline.CreatedAt,
+	}
+}
+
+func convertSeasonsToDomain(seasons []model.Seasons) []domain.Season {
+	converted := make([]domain.Season, 0, len(seasons))
+	for _, season := range seasons {
+		converted = append(converted, convertSeasonToDomain(season))
+	}
+	return converted
+}
+
+func convertSeasonToDomain(season model.Seasons) domain.Season {
+	converted := domain.Season{
+		ID:        int(season.ID),
+		Name:      season.Name,
+		Start:     season.StartAt,
+		End:       season.EndAt,
+		Carryover: season.Carryover,
+	}
+	if season.ArchivedAt != nil {
+		converted.ArchivedAt = *season.ArchivedAt
+	}
+	return converted
+}
+
+func convertStandingsFromDomain(standings []domain.SeasonStanding) []model.SeasonStandings {
+	var converted []model.SeasonStandings
+	for _, standing := range standings {
+		for _, r := range standing.Ratings {
+			converted = append(converted, model.SeasonStandings{
+				SeasonID:     int32(standing.SeasonID),
+				DisciplineID: int32(standing.DisciplineID),
+				PlayerID:     standing.Player.ID.String(),
+				Place:        int32(standing.Place),
+				GamesPlayed:  int32(standing.GamesPlayed),
+				System:       r.System,
+				Rating:       r.Value,
+				Deviation:    r.Deviation,
+				IntervalMin:  r.Interval.Min,
+				IntervalMax:  r.Interval.Max,
+				Precision:    int32(r.Precision),
+			})
+		}
+	}
+	return converted
+}
+
+// convertStandingsToDomain groups the rows by player, the rows of a player must be adjacent.
+func convertStandingsToDomain(rows []model.SeasonStandings) ([]domain.SeasonStanding, error) {
+	var converted []domain.SeasonStanding
+	for _, row := range rows {
+		id, err := uuid.Parse(row.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(converted); n == 0 || converted[n-1].Player.ID != id {
+			converted = append(converted, domain.SeasonStanding{
+				SeasonID:     int(row.SeasonID),
+				DisciplineID: int(row.DisciplineID),
+				Player:       domain.Player{ID: id},
+				Place:        int(row.Place),
+				GamesPlayed:  int(row.GamesPlayed),
+			})
+		}
+		standing := &converted[len(converted)-1]
+		standing.Ratings = append(standing.Ratings, domain.Rating{
+			System:    row.System,
+			Value:     row.Rating,
+			Deviation: row.Deviation,
+			Interval: domain.Interval{
+				Min: row.IntervalMin,
+				Max: row.IntervalMax,
+			},
+			Precision: int(row.Precision),
+		})
+	}
+	return converted, nil
+}
+
+func convertMatchChangeFromDomain(change domain.MatchChange) model.MatchChanges {
+	return model.MatchChanges{
+		MatchID:   int32(change.MatchID),
+		Action:    change.Action,
+		Author:    change.Author,
+		OldValue:  change.Before,
+		NewValue:  change.After,
+		CreatedAt: change.Date,
+	}
+}
+
+func convertMatchChangesToDomain(changes []model.MatchChanges) []domain.MatchChange {
+	converted := make([]domain.MatchChange, 0, len(changes))
+	for _, change := range changes {
+		converted = append(converted, domain.MatchChange{
+			ID:      int(change.ID),
+			MatchID: int(change.MatchID),
+			Action:  change.Action,
+			Author:  change.Author,
+			Before:  change.OldValue,
+			After:   change.NewValue,
+			Date:    change.CreatedAt,
+		})
+	}
+	return converted
+}
+
+// convertTournamentsToDomain adds the players and the games to their tournaments.
+func convertTournamentsToDomain(
+	tournaments []model.Tournaments,
+	players []model.TournamentPlayers,
+	games []model.TournamentGames,
+) ([]domain.Tournament, error) {
+	converted := make([]domain.Tournament, 0, len(tournaments))
+	byID := make(map[int32]int, len(tournaments))
+	for _, tournament := range tournaments {
+		byID[tournament.ID] = len(converted)
+		t := domain.Tournament{
+			ID:           int(tournament.ID),
+			Name:         tournament.Name,
+			DisciplineID: int(tournament.DisciplineID),
+			Format:       domain.TournamentFormat(tournament.Format),
+			Rounds:       int(tournament.Rounds),
+			CreatedAt:    tournament.CreatedAt,
+		}
+		if tournament.FinishedAt != nil {
+			t.FinishedAt = *tournament.FinishedAt
+		}
+		converted = append(converted, t)
+	}
+	for _, player := range players {
+		i, ok := byID[player.TournamentID]
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(player.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		converted[i].Players = append(converted[i].Players, domain.Player{ID: id})
+	}
+	for _, game := range games {
+		i, ok := byID[game.TournamentID]
+		if !ok {
+			continue
+		}
+		g, err := convertTournamentGameToDomain(game)
+		if err != nil {
+			return nil, err
+		}
+		converted[i].Games = append(converted[i].Games, g)
+	}
+	return converted, nil
+}
+
+func convertTournamentGameToDomain(game model.TournamentGames) (domain.TournamentGame, error) {
+	idA, err := uuid.Parse(game.PlayerA)
+	if err != nil {
+		return domain.TournamentGame{}, err
+	}
+	var idB uuid.UUID
+	if game.PlayerB != "" {
+		idB, err = uuid.Parse(game.PlayerB)
+		if err != nil {
+			return domain.TournamentGame{}, err
+		}
+	}
+	return domain.TournamentGame{
+		Bracket:  domain.TournamentBracket(game.Bracket),
+		Round:    int(game.Round),
+		Position: int(game.Position),
+		PlayerA:  domain.Player{ID: idA},
+		PlayerB:  domain.Player{ID: idB},
+		MatchID:  intValue(game.MatchID),
 	}, nil
 }
+
+func convertTournamentPlayersFromDomain(tournament domain.Tournament) []model.TournamentPlayers {
+	players := make([]model.TournamentPlayers, 0, len(tournament.Players))
+	for i, player := range tournament.Players {
+		players = append(players, model.TournamentPlayers{
+			TournamentID: int32(tournament.ID),
+			PlayerID:     player.ID.String(),
+			Seed:         int32(i + 1),
+		})
+	}
+	return players
+}
+
+func convertTournamentGamesFromDomain(tournamentID int, games []domain.TournamentGame) []model.TournamentGames {
+	converted := make([]model.TournamentGames, 0, len(games))
+	for _, game := range games {
+		g := model.TournamentGames{
+			TournamentID: int32(tournamentID),
+			Bracket:      string(game.Bracket),
+			Round:        int32(game.Round),
+			Position:     int32(game.Position),
+			PlayerA:      game.PlayerA.ID.String(),
+			MatchID:      int32Ptr(game.MatchID),
+		}
+		if !game.Bye() {
+			g.PlayerB = game.PlayerB.ID.String()
+		}
+		converted = append(converted, g)
+	}
+	return converted
+}
+
+func convertLadderEntriesToDomain(entries []model.LadderPlayers) ([]domain.LadderEntry, error) {
+	converted := make([]domain.LadderEntry, 0, len(entries))
+	for _, entry := range entries {
+		id, err := uuid.Parse(entry.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, domain.LadderEntry{
+			DisciplineID: int(entry.DisciplineID),
+			Player:       domain.Player{ID: id},
+			JoinedAt:     entry.JoinedAt,
+		})
+	}
+	return converted, nil
+}
+
+func convertChallengesToDomain(challenges []model.LadderChallenges) ([]domain.LadderChallenge, error) {
+	converted := make([]domain.LadderChallenge, 0, len(challenges))
+	for _, challenge := range challenges {
+		c, err := convertChallengeToDomain(challenge)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, c)
+	}
+	return converted, nil
+}
+
+func convertChallengeToDomain(challenge model.LadderChallenges) (domain.LadderChallenge, error) {
+	challenger, err := uuid.Parse(challenge.ChallengerID)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	challenged, err := uuid.Parse(challenge.ChallengedID)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	converted := domain.LadderChallenge{
+		ID:           int(challenge.ID),
+		DisciplineID: int(challenge.DisciplineID),
+		Challenger:   domain.Player{ID: challenger},
+		Challenged:   domain.Player{ID: challenged},
+		CreatedAt:    challenge.CreatedAt,
+		Deadline:     challenge.Deadline,
+		MatchID:      intValue(challenge.MatchID),
+	}
+	if challenge.AcceptedAt != nil {
+		converted.AcceptedAt = *challenge.AcceptedAt
+	}
+	if challenge.CancelledAt != nil {
+		converted.CancelledAt = *challenge.CancelledAt
+	}
+	return converted, nil
+}
+
+func convertChallengeFromDomain(challenge domain.LadderChallenge) model.LadderChallenges {
+	converted := model.LadderChallenges{
+		ID:           int32(challenge.ID),
+		DisciplineID: int32(challenge.DisciplineID),
+		ChallengerID: challenge.Challenger.ID.String(),
+		ChallengedID: challenge.Challenged.ID.String(),
+		CreatedAt:    challenge.CreatedAt,
+		Deadline:     challenge.Deadline,
+		MatchID:      int32Ptr(challenge.MatchID),
+	}
+	if !challenge.AcceptedAt.IsZero() {
+		converted.AcceptedAt = &challenge.AcceptedAt
+	}
+	if !challenge.CancelledAt.IsZero() {
+		converted.CancelledAt = &challenge.CancelledAt
+	}
+	return converted
+}
@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"database/sql"
+	"sort"
+	"time"
 
 	"github.com/goserg/ratingserver/gen/model"
 	"github.com/goserg/ratingserver/gen/table"
@@ -10,6 +12,7 @@ import (
 	mig "github.com/goserg/ratingserver/internal/migrate"
 	"github.com/goserg/ratingserver/internal/storage"
 
+	"github.com/go-jet/jet/v2/qrm"
 	"github.com/go-jet/jet/v2/sqlite"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -23,6 +26,11 @@ type Storage struct {
 var (
 	_ storage.PlayerStorage = (*Storage)(nil)
 	_ storage.MatchStorage  = (*Storage)(nil)
+
+	_ storage.DisciplineStorage = (*Storage)(nil)
+	_ storage.SeasonStorage     = (*Storage)(nil)
+	_ storage.TournamentStorage = (*Storage)(nil)
+	_ storage.LadderStorage     = (*Storage)(nil)
 )
 
 func New(l *logrus.Logger, cfg config.Server) (*Storage, error) {
@@ -72,10 +80,15 @@ func (s *Storage) ListMatches() ([]domain.Match, error) {
 	err := table.Matches.
 		SELECT(table.Matches.AllColumns).
 		FROM(table.Matches).
+		ORDER_BY(table.Matches.ID).
 		Query(s.db, &matches)
 	if err != nil {
 		return nil, err
 	}
+	// timestamps are stored as text with an offset, so they are compared here
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].PlayedAt.Before(matches[j].PlayedAt)
+	})
 	players, err := s.ListPlayers()
 	if err != nil {
 		return nil, err
@@ -85,9 +98,28 @@ func (s *Storage) ListMatches() ([]domain.Match, error) {
 	if err != nil {
 		return nil, err
 	}
+	var teammates []model.MatchTeammates
+	err = table.MatchTeammates.
+		SELECT(table.MatchTeammates.AllColumns).
+		FROM(table.MatchTeammates).
+		ORDER_BY(table.MatchTeammates.MatchID, table.MatchTeammates.Position).
+		Query(s.db, &teammates)
+	if err != nil {
+		return nil, err
+	}
+	err = addTeammatesToDomain(domainMatches, teammates)
+	if err != nil {
+		return nil, err
+	}
 	for i := range domainMatches {
 		domainMatches[i].PlayerA = playerMap[domainMatches[i].PlayerA.ID]
 		domainMatches[i].PlayerB = playerMap[domainMatches[i].PlayerB.ID]
+		for j := range domainMatches[i].TeammatesA {
+			domainMatches[i].TeammatesA[j] = playerMap[domainMatches[i].TeammatesA[j].ID]
+		}
+		for j := range domainMatches[i].TeammatesB {
+			domainMatches[i].TeammatesB[j] = playerMap[domainMatches[i].TeammatesB[j].ID]
+		}
 		if domainMatches[i].Winner.ID != uuid.Nil {
 			domainMatches[i].Winner = playerMap[domainMatches[i].Winner.ID]
 		}
@@ -115,16 +147,51 @@ func (s *Storage) ImportPlayers(players []domain.Player) error {
 	return nil
 }
 
+// ImportMatches saves the matches in one transaction,
+// the matches without an ID get a new one.
 func (s *Storage) ImportMatches(matches []domain.Match) error {
-	mMatches := make([]model.Matches, 0, len(matches))
-	for i := range matches {
-		mMatches = append(mMatches, convertMatchesFromDomain(matches[i]))
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
 	}
-	_, err := table.Matches.INSERT(table.Matches.AllColumns).MODELS(mMatches).Exec(s.db)
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+	var teammates []model.MatchTeammates
+	for _, match := range matches {
+		m := convertMatchesFromDomain(match)
+		m.CreatedAt = now
+		columns := table.Matches.AllColumns
+		if m.ID == 0 {
+			columns = table.Matches.MutableColumns
+		}
+		err = table.Matches.
+			INSERT(columns).
+			MODEL(m).
+			RETURNING(table.Matches.ID).
+			Query(tx, &m)
+		if err != nil {
+			return err
+		}
+		match.ID = int(m.ID)
+		teammates = append(teammates, convertTeammatesFromDomain(match)...)
+	}
+	err = saveTeammates(tx, teammates)
 	if err != nil {
 		return err
 	}
-	return nil
+	return tx.Commit()
+}
+
+func saveTeammates(db qrm.Executable, teammates []model.MatchTeammates) error {
+	if len(teammates) == 0 {
+		return nil
+	}
+	_, err := table.MatchTeammates.
+		INSERT(table.MatchTeammates.AllColumns).
+		MODELS(teammates).
+		Exec(db)
+	return err
 }
 
 func convertMatchesFromDomain(match domain.Match) model.Matches {
@@ -136,26 +203,213 @@ func convertMatchesFromDomain(match domain.Match) model.Matches {
 		id := match.Winner.ID.String()
 		m.Winner = &id
 	}
-	m.CreatedAt = match.Date
+	m.PlayedAt = match.Date
+	m.DisciplineID = int32(match.DisciplineID)
+	m.Voided = match.Voided
+	if match.Score != nil {
+		a, b := int32(match.Score.A), int32(match.Score.B)
+		m.ScoreA = &a
+		m.ScoreB = &b
+	}
 	return m
 }
 
 func (s *Storage) Create(match domain.Match) (domain.Match, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return domain.Match{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
 	dMatch := convertMatchesFromDomain(match)
-	err := table.Matches.
+	err = table.Matches.
 		INSERT(
 			table.Matches.PlayerA,
 			table.Matches.PlayerB,
 			table.Matches.Winner,
-			table.Matches.CreatedAt,
+			table.Matches.PlayedAt,
+			table.Matches.ScoreA,
+			table.Matches.ScoreB,
+			table.Matches.DisciplineID,
 		).
 		MODEL(dMatch).
 		RETURNING(table.Matches.AllColumns).
-		Query(s.db, &dMatch)
+		Query(tx, &dMatch)
 	if err != nil {
 		return domain.Match{}, err
 	}
-	return convertMatchToDomain(dMatch)
+	created, err := convertMatchToDomain(dMatch)
+	if err != nil {
+		return domain.Match{}, err
+	}
+	created.TeammatesA = match.TeammatesA
+	created.TeammatesB = match.TeammatesB
+	err = saveTeammates(tx, convertTeammatesFromDomain(created))
+	if err != nil {
+		return domain.Match{}, err
+	}
+	return created, tx.Commit()
+}
+
+func (s *Storage) UpdateMatch(match domain.Match, change domain.MatchChange) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	dMatch := convertMatchesFromDomain(match)
+	_, err = table.Matches.
+		UPDATE(
+			table.Matches.PlayerA,
+			table.Matches.PlayerB,
+			table.Matches.Winner,
+			table.Matches.PlayedAt,
+			table.Matches.ScoreA,
+			table.Matches.ScoreB,
+			table.Matches.Voided,
+		).
+		MODEL(dMatch).
+		WHERE(table.Matches.ID.EQ(sqlite.Int(int64(match.ID)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.MatchTeammates.
+		DELETE().
+		WHERE(table.MatchTeammates.MatchID.EQ(sqlite.Int(int64(match.ID)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	err = saveTeammates(tx, convertTeammatesFromDomain(match))
+	if err != nil {
+		return err
+	}
+	err = saveMatchChange(tx, change)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Storage) DeleteMatch(id int, change domain.MatchChange) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// foreign keys are not enforced, so the teammates are not deleted by cascade
+	_, err = table.MatchTeammates.
+		DELETE().
+		WHERE(table.MatchTeammates.MatchID.EQ(sqlite.Int(int64(id)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.Matches.
+		DELETE().
+		WHERE(table.Matches.ID.EQ(sqlite.Int(int64(id)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	err = saveMatchChange(tx, change)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveMatchChange(db qrm.Executable, change domain.MatchChange) error {
+	_, err := table.MatchChanges.
+		INSERT(table.MatchChanges.MutableColumns).
+		MODEL(convertMatchChangeFromDomain(change)).
+		Exec(db)
+	return err
+}
+
+func (s *Storage) ListMatchChanges() ([]domain.MatchChange, error) {
+	var changes []model.MatchChanges
+	err := table.MatchChanges.
+		SELECT(table.MatchChanges.AllColumns).
+		FROM(table.MatchChanges).
+		ORDER_BY(table.MatchChanges.ID.DESC()).
+		Query(s.db, &changes)
+	if err != nil {
+		return nil, err
+	}
+	return convertMatchChangesToDomain(changes), nil
+}
+
+func (s *Storage) ListFFAMatches() ([]domain.FFAMatch, error) {
+	var matches []model.FfaMatches
+	err := table.FfaMatches.
+		SELECT(table.FfaMatches.AllColumns).
+		FROM(table.FfaMatches).
+		ORDER_BY(table.FfaMatches.ID).
+		Query(s.db, &matches)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].PlayedAt.Before(matches[j].PlayedAt)
+	})
+	var placements []model.FfaPlacements
+	err = table.FfaPlacements.
+		SELECT(table.FfaPlacements.AllColumns).
+		FROM(table.FfaPlacements).
+		ORDER_BY(table.FfaPlacements.MatchID, table.FfaPlacements.Position).
+		Query(s.db, &placements)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertFFAMatchesToDomain(matches, placements)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.ListPlayers()
+	if err != nil {
+		return nil, err
+	}
+	playerMap := convertPlayersToMap(players)
+	for i := range converted {
+		for j := range converted[i].Placements {
+			converted[i].Placements[j].Player = playerMap[converted[i].Placements[j].Player.ID]
+		}
+	}
+	return converted, nil
+}
+
+func (s *Storage) CreateFFA(match domain.FFAMatch) (domain.FFAMatch, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return domain.FFAMatch{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	dMatch := model.FfaMatches{
+		PlayedAt:     match.Date,
+		DisciplineID: int32(match.DisciplineID),
+	}
+	err = table.FfaMatches.
+		INSERT(table.FfaMatches.PlayedAt, table.FfaMatches.DisciplineID).
+		MODEL(dMatch).
+		RETURNING(table.FfaMatches.AllColumns).
+		Query(tx, &dMatch)
+	if err != nil {
+		return domain.FFAMatch{}, err
+	}
+	match.ID = int(dMatch.ID)
+	_, err = table.FfaPlacements.
+		INSERT(table.FfaPlacements.AllColumns).
+		MODELS(convertPlacementsFromDomain(match)).
+		Exec(tx)
+	if err != nil {
+		return domain.FFAMatch{}, err
+	}
+	return match, tx.Commit()
 }
 
 func (s *Storage) Get(id uuid.UUID) (domain.Player, error) {
@@ -183,3 +437,500 @@ func (s *Storage) Add(player domain.Player) (domain.Player, error) {
 	}
 	return convertPlayerToDomain(dbPlayer)
 }
+
+func (s *Storage) UpdatePlayer(player domain.Player) error {
+	_, err := table.Players.
+		UPDATE(table.Players.Name, table.Players.Deactivated).
+		MODEL(convertPlayerFromDomain(player)).
+		WHERE(table.Players.ID.EQ(sqlite.String(player.ID.String()))).
+		Exec(s.db)
+	return err
+}
+
+func (s *Storage) MergePlayers(from, to uuid.UUID) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	fromID, toID := sqlite.String(from.String()), sqlite.String(to.String())
+	for _, column := range []sqlite.ColumnString{table.Matches.PlayerA, table.Matches.PlayerB, table.Matches.Winner} {
+		_, err = table.Matches.
+			UPDATE(column).
+			SET(toID).
+			WHERE(column.EQ(fromID)).
+			Exec(tx)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = table.MatchTeammates.
+		UPDATE(table.MatchTeammates.PlayerID).
+		SET(toID).
+		WHERE(table.MatchTeammates.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.FfaPlacements.
+		UPDATE(table.FfaPlacements.PlayerID).
+		SET(toID).
+		WHERE(table.FfaPlacements.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.RatingHistory.
+		UPDATE(table.RatingHistory.PlayerID).
+		SET(toID).
+		WHERE(table.RatingHistory.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	// the archived place of the remaining player is kept if both of them played in the season
+	other := table.SeasonStandings.AS("other")
+	_, err = table.SeasonStandings.
+		DELETE().
+		WHERE(table.SeasonStandings.PlayerID.EQ(fromID).AND(sqlite.EXISTS(
+			other.
+				SELECT(other.PlayerID).
+				FROM(other).
+				WHERE(
+					other.PlayerID.EQ(toID).
+						AND(other.SeasonID.EQ(table.SeasonStandings.SeasonID)).
+						AND(other.DisciplineID.EQ(table.SeasonStandings.DisciplineID)).
+						AND(other.System.EQ(table.SeasonStandings.System)),
+				),
+		))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.SeasonStandings.
+		UPDATE(table.SeasonStandings.PlayerID).
+		SET(toID).
+		WHERE(table.SeasonStandings.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	_, err = table.TournamentPlayers.
+		UPDATE(table.TournamentPlayers.PlayerID).
+		SET(toID).
+		WHERE(table.TournamentPlayers.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	for _, column := range []sqlite.ColumnString{table.TournamentGames.PlayerA, table.TournamentGames.PlayerB} {
+		_, err = table.TournamentGames.
+			UPDATE(column).
+			SET(toID).
+			WHERE(column.EQ(fromID)).
+			Exec(tx)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = table.LadderPlayers.
+		UPDATE(table.LadderPlayers.PlayerID).
+		SET(toID).
+		WHERE(table.LadderPlayers.PlayerID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	for _, column := range []sqlite.ColumnString{table.LadderChallenges.ChallengerID, table.LadderChallenges.ChallengedID} {
+		_, err = table.LadderChallenges.
+			UPDATE(column).
+			SET(toID).
+			WHERE(column.EQ(fromID)).
+			Exec(tx)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = table.Players.
+		DELETE().
+		WHERE(table.Players.ID.EQ(fromID)).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Storage) ListDisciplines() ([]domain.Discipline, error) {
+	var disciplines []model.Disciplines
+	err := table.Disciplines.
+		SELECT(table.Disciplines.AllColumns).
+		FROM(table.Disciplines).
+		ORDER_BY(table.Disciplines.ID).
+		Query(s.db, &disciplines)
+	if err != nil {
+		return nil, err
+	}
+	return convertDisciplinesToDomain(disciplines), nil
+}
+
+func (s *Storage) AddDiscipline(discipline domain.Discipline) (domain.Discipline, error) {
+	dbDiscipline := model.Disciplines{
+		Name:      discipline.Name,
+		CreatedAt: discipline.CreatedAt,
+	}
+	err := table.Disciplines.
+		INSERT(table.Disciplines.Name, table.Disciplines.CreatedAt).
+		MODEL(dbDiscipline).
+		RETURNING(table.Disciplines.AllColumns).
+		Query(s.db, &dbDiscipline)
+	if err != nil {
+		return domain.Discipline{}, err
+	}
+	return convertDisciplineToDomain(dbDiscipline), nil
+}
+
+func (s *Storage) ListSeasons() ([]domain.Season, error) {
+	var seasons []model.Seasons
+	err := table.Seasons.
+		SELECT(table.Seasons.AllColumns).
+		FROM(table.Seasons).
+		ORDER_BY(table.Seasons.StartAt, table.Seasons.ID).
+		Query(s.db, &seasons)
+	if err != nil {
+		return nil, err
+	}
+	return convertSeasonsToDomain(seasons), nil
+}
+
+func (s *Storage) AddSeason(season domain.Season) (domain.Season, error) {
+	dbSeason := model.Seasons{
+		Name:      season.Name,
+		StartAt:   season.Start,
+		EndAt:     season.End,
+		Carryover: season.Carryover,
+		CreatedAt: time.Now(),
+	}
+	err := table.Seasons.
+		INSERT(table.Seasons.Name, table.Seasons.StartAt, table.Seasons.EndAt, table.Seasons.Carryover, table.Seasons.CreatedAt).
+		MODEL(dbSeason).
+		RETURNING(table.Seasons.AllColumns).
+		Query(s.db, &dbSeason)
+	if err != nil {
+		return domain.Season{}, err
+	}
+	return convertSeasonToDomain(dbSeason), nil
+}
+
+func (s *Storage) ArchiveSeason(season domain.Season, standings []domain.SeasonStanding) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = table.SeasonStandings.
+		DELETE().
+		WHERE(table.SeasonStandings.SeasonID.EQ(sqlite.Int(int64(season.ID)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	rows := convertStandingsFromDomain(standings)
+	for start := 0; start < len(rows); start += ratingHistoryBatchSize {
+		end := start + ratingHistoryBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		_, err = table.SeasonStandings.
+			INSERT(table.SeasonStandings.AllColumns).
+			MODELS(rows[start:end]).
+			Exec(tx)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = table.Seasons.
+		UPDATE(table.Seasons.ArchivedAt).
+		SET(sqlite.DATETIME(season.ArchivedAt)).
+		WHERE(table.Seasons.ID.EQ(sqlite.Int(int64(season.ID)))).
+		Exec(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Storage) ListSeasonStandings(seasonID, disciplineID int) ([]domain.SeasonStanding, error) {
+	var rows []model.SeasonStandings
+	err := table.SeasonStandings.
+		SELECT(table.SeasonStandings.AllColumns).
+		FROM(table.SeasonStandings).
+		WHERE(table.SeasonStandings.SeasonID.EQ(sqlite.Int(int64(seasonID))).
+			AND(table.SeasonStandings.DisciplineID.EQ(sqlite.Int(int64(disciplineID))))).
+		ORDER_BY(table.SeasonStandings.Place, table.SeasonStandings.PlayerID).
+		Query(s.db, &rows)
+	if err != nil {
+		return nil, err
+	}
+	return convertStandingsToDomain(rows)
+}
+
+func (s *Storage) ListRatingHistory(filter domain.RatingHistoryFilter) ([]domain.RatingHistoryEntry, error) {
+	condition := sqlite.Bool(true)
+	if filter.PlayerID != uuid.Nil {
+		condition = condition.AND(table.RatingHistory.PlayerID.EQ(sqlite.String(filter.PlayerID.String())))
+	}
+	if filter.DisciplineID != 0 {
+		condition = condition.AND(table.RatingHistory.DisciplineID.EQ(sqlite.Int(int64(filter.DisciplineID))))
+	}
+	if filter.System != "" {
+		condition = condition.AND(table.RatingHistory.System.EQ(sqlite.String(filter.System)))
+	}
+	var entries []model.RatingHistory
+	err := table.RatingHistory.
+		SELECT(table.RatingHistory.AllColumns).
+		FROM(table.RatingHistory).
+		WHERE(condition).
+		Query(s.db, &entries)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertRatingHistoryToDomain(entries)
+	if err != nil {
+		return nil, err
+	}
+	// timestamps are stored as text with an offset, so they are compared here
+	sort.SliceStable(converted, func(i, j int) bool {
+		if !converted[i].Date.Equal(converted[j].Date) {
+			return converted[i].Date.Before(converted[j].Date)
+		}
+		if converted[i].MatchID != converted[j].MatchID {
+			return converted[i].MatchID < converted[j].MatchID
+		}
+		return converted[i].FFAMatchID < converted[j].FFAMatchID
+	})
+	if !filter.To.IsZero() {
+		n := 0
+		for i := range converted {
+			if !converted[i].Date.After(filter.To) {
+				converted[n] = converted[i]
+				n++
+			}
+		}
+		converted = converted[:n]
+	}
+	return converted, nil
+}
+
+func (s *Storage) SaveRatingHistory(entries []domain.RatingHistoryEntry) error {
+	return saveRatingHistory(s.db, entries)
+}
+
+func (s *Storage) ReplaceRatingHistory(entries []domain.RatingHistoryEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = table.RatingHistory.DELETE().WHERE(sqlite.Bool(true)).Exec(tx)
+	if err != nil {
+		return err
+	}
+	err = saveRatingHistory(tx, entries)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ratingHistoryBatchSize keeps the number of query parameters under the sqlite limit.
+const ratingHistoryBatchSize = 1000
+
+func saveRatingHistory(db qrm.Executable, entries []domain.RatingHistoryEntry) error {
+	for start := 0; start < len(entries); start += ratingHistoryBatchSize {
+		end := start + ratingHistoryBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		mEntries := make([]model.RatingHistory, 0, end-start)
+		for i := start; i < end; i++ {
+			mEntries = append(mEntries, convertRatingHistoryFromDomain(entries[i]))
+		}
+		_, err := table.RatingHistory.
+			INSERT(table.RatingHistory.MutableColumns).
+			MODELS(mEntries).
+			Exec(db)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) ListTournaments() ([]domain.Tournament, error) {
+	var tournaments []model.Tournaments
+	err := table.Tournaments.
+		SELECT(table.Tournaments.AllColumns).
+		FROM(table.Tournaments).
+		ORDER_BY(table.Tournaments.ID).
+		Query(s.db, &tournaments)
+	if err != nil {
+		return nil, err
+	}
+	var players []model.TournamentPlayers
+	err = table.TournamentPlayers.
+		SELECT(table.TournamentPlayers.AllColumns).
+		FROM(table.TournamentPlayers).
+		ORDER_BY(table.TournamentPlayers.TournamentID, table.TournamentPlayers.Seed).
+		Query(s.db, &players)
+	if err != nil {
+		return nil, err
+	}
+	var games []model.TournamentGames
+	err = table.TournamentGames.
+		SELECT(table.TournamentGames.AllColumns).
+		FROM(table.TournamentGames).
+		ORDER_BY(table.TournamentGames.TournamentID, table.TournamentGames.Round, table.TournamentGames.Position).
+		Query(s.db, &games)
+	if err != nil {
+		return nil, err
+	}
+	return convertTournamentsToDomain(tournaments, players, games)
+}
+
+func (s *Storage) AddTournament(tournament domain.Tournament) (domain.Tournament, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	dbTournament := model.Tournaments{
+		Name:         tournament.Name,
+		DisciplineID: int32(tournament.DisciplineID),
+		Format:       string(tournament.Format),
+		Rounds:       int32(tournament.Rounds),
+		CreatedAt:    tournament.CreatedAt,
+	}
+	err = table.Tournaments.
+		INSERT(table.Tournaments.MutableColumns.Except(table.Tournaments.FinishedAt)).
+		MODEL(dbTournament).
+		RETURNING(table.Tournaments.AllColumns).
+		Query(tx, &dbTournament)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	tournament.ID = int(dbTournament.ID)
+	tournament.Name = dbTournament.Name
+	tournament.Format = domain.TournamentFormat(dbTournament.Format)
+	_, err = table.TournamentPlayers.
+		INSERT(table.TournamentPlayers.AllColumns).
+		MODELS(convertTournamentPlayersFromDomain(tournament)).
+		Exec(tx)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	err = saveTournamentGames(tx, tournament.ID, tournament.Games)
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	return tournament, tx.Commit()
+}
+
+func (s *Storage) SaveTournamentGames(tournamentID int, games []domain.TournamentGame) error {
+	return saveTournamentGames(s.db, tournamentID, games)
+}
+
+func saveTournamentGames(db qrm.Executable, tournamentID int, games []domain.TournamentGame) error {
+	if len(games) == 0 {
+		return nil
+	}
+	_, err := table.TournamentGames.
+		INSERT(table.TournamentGames.AllColumns).
+		MODELS(convertTournamentGamesFromDomain(tournamentID, games)).
+		ON_CONFLICT(
+			table.TournamentGames.TournamentID,
+			table.TournamentGames.Bracket,
+			table.TournamentGames.Round,
+			table.TournamentGames.Position,
+		).
+		DO_UPDATE(sqlite.SET(
+			table.TournamentGames.PlayerA.SET(table.TournamentGames.EXCLUDED.PlayerA),
+			table.TournamentGames.PlayerB.SET(table.TournamentGames.EXCLUDED.PlayerB),
+			table.TournamentGames.MatchID.SET(table.TournamentGames.EXCLUDED.MatchID),
+		)).
+		Exec(db)
+	return err
+}
+
+func (s *Storage) FinishTournament(id int, finishedAt time.Time) error {
+	_, err := table.Tournaments.
+		UPDATE(table.Tournaments.FinishedAt).
+		SET(sqlite.DATETIME(finishedAt)).
+		WHERE(table.Tournaments.ID.EQ(sqlite.Int(int64(id)))).
+		Exec(s.db)
+	return err
+}
+
+func (s *Storage) ListLadderEntries() ([]domain.LadderEntry, error) {
+	var entries []model.LadderPlayers
+	err := table.LadderPlayers.
+		SELECT(table.LadderPlayers.AllColumns).
+		FROM(table.LadderPlayers).
+		ORDER_BY(table.LadderPlayers.JoinedAt).
+		Query(s.db, &entries)
+	if err != nil {
+		return nil, err
+	}
+	return convertLadderEntriesToDomain(entries)
+}
+
+func (s *Storage) AddLadderEntry(entry domain.LadderEntry) error {
+	_, err := table.LadderPlayers.
+		INSERT(table.LadderPlayers.AllColumns).
+		MODEL(model.LadderPlayers{
+			DisciplineID: int32(entry.DisciplineID),
+			PlayerID:     entry.Player.ID.String(),
+			JoinedAt:     entry.JoinedAt,
+		}).
+		Exec(s.db)
+	return err
+}
+
+func (s *Storage) ListChallenges() ([]domain.LadderChallenge, error) {
+	var challenges []model.LadderChallenges
+	err := table.LadderChallenges.
+		SELECT(table.LadderChallenges.AllColumns).
+		FROM(table.LadderChallenges).
+		ORDER_BY(table.LadderChallenges.ID).
+		Query(s.db, &challenges)
+	if err != nil {
+		return nil, err
+	}
+	return convertChallengesToDomain(challenges)
+}
+
+func (s *Storage) AddChallenge(challenge domain.LadderChallenge) (domain.LadderChallenge, error) {
+	dbChallenge := convertChallengeFromDomain(challenge)
+	err := table.LadderChallenges.
+		INSERT(table.LadderChallenges.MutableColumns).
+		MODEL(dbChallenge).
+		RETURNING(table.LadderChallenges.AllColumns).
+		Query(s.db, &dbChallenge)
+	if err != nil {
+		return domain.LadderChallenge{}, err
+	}
+	return convertChallengeToDomain(dbChallenge)
+}
+
+func (s *Storage) UpdateChallenge(challenge domain.LadderChallenge) error {
+	_, err := table.LadderChallenges.
+		UPDATE(table.LadderChallenges.AcceptedAt, table.LadderChallenges.CancelledAt, table.LadderChallenges.MatchID).
+		MODEL(convertChallengeFromDomain(challenge)).
+		WHERE(table.LadderChallenges.ID.EQ(sqlite.Int(int64(challenge.ID)))).
+		Exec(s.db)
+	return err
+}
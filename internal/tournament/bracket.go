@@ -0,0 +1,303 @@
+package tournament
+
+import (
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// slot identifies a game of a bracket.
+type slot struct {
+	bracket  domain.TournamentBracket
+	round    int
+	position int
+}
+
+// side is a participant of a game, it is not known until the game it comes from is played.
+// A known side without a player is an empty place of the bracket, the opponent advances.
+type side struct {
+	player domain.Player
+	known  bool
+}
+
+func (s side) get() (domain.Player, bool) {
+	return s.player, s.known
+}
+
+func (s side) empty() bool {
+	return s.known && s.player.ID == uuid.Nil
+}
+
+// bracket resolves the games of an elimination tournament from the seeds and the results.
+// The size of the bracket is a power of two, the places of the missing players are empty,
+// so the strongest seeds skip the first round.
+//
+// The losers of a double elimination drop to the losers bracket: the losers of the first round
+// play each other, then every even round the winners of the losers bracket meet the players who
+// have just lost in the next round of the winners bracket, and every odd round they play each other.
+// The winners of the two brackets meet in the final, which is replayed if the winner
+// of the losers bracket wins it, so everyone is knocked out by the second loss.
+type bracket struct {
+	format domain.TournamentFormat
+	// seeds are the players by their places in the first round.
+	seeds []domain.Player
+	// rounds is the number of the rounds of the winners bracket.
+	rounds  int
+	results map[slot]domain.TournamentGame
+	// outcomes caches the results, the same games are resolved for many later ones.
+	outcomes map[slot]outcome
+}
+
+type outcome struct {
+	winner, loser side
+	ok            bool
+}
+
+func newBracket(t domain.Tournament, stored []domain.TournamentGame) *bracket {
+	size, rounds := 1, 0
+	for size < len(t.Players) {
+		size *= 2
+		rounds++
+	}
+	b := bracket{
+		format:   t.Format,
+		seeds:    make([]domain.Player, size),
+		rounds:   rounds,
+		results:  make(map[slot]domain.TournamentGame, len(stored)),
+		outcomes: make(map[slot]outcome),
+	}
+	for i, seed := range seedOrder(size) {
+		if seed < len(t.Players) {
+			b.seeds[i] = t.Players[seed]
+		}
+	}
+	for _, game := range stored {
+		if game.Match != nil {
+			b.results[slot{bracket: game.Bracket, round: game.Round, position: game.Position}] = game
+		}
+	}
+	return &b
+}
+
+// seedOrder returns the seeds by their places in the first round of a bracket of the size,
+// the strongest ones meet as late as possible: 0 7 3 4 1 6 2 5 for 8 players.
+func seedOrder(size int) []int {
+	order := []int{0}
+	for n := 1; n < size; n *= 2 {
+		next := make([]int, 0, n*2)
+		for _, seed := range order {
+			next = append(next, seed, n*2-1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+func (b *bracket) double() bool {
+	return b.format == domain.TournamentDoubleElimination
+}
+
+// count returns the number of the games of the round.
+func (b *bracket) count(bracket domain.TournamentBracket, round int) int {
+	size := len(b.seeds)
+	switch bracket {
+	case domain.BracketWinners:
+		return size >> round
+	case domain.BracketLosers:
+		return size >> ((round+1)/2 + 1)
+	}
+	return 1
+}
+
+// losersRounds returns the number of the rounds of the losers bracket.
+func (b *bracket) losersRounds() int {
+	if !b.double() || b.rounds < 2 {
+		return 0
+	}
+	return 2 * (b.rounds - 1)
+}
+
+// slots returns all the games of the bracket in the order they are played.
+func (b *bracket) slots() []slot {
+	var slots []slot
+	add := func(bracket domain.TournamentBracket, round int) {
+		for position := 0; position < b.count(bracket, round); position++ {
+			slots = append(slots, slot{bracket: bracket, round: round, position: position})
+		}
+	}
+	for round := 1; round <= b.rounds; round++ {
+		add(domain.BracketWinners, round)
+	}
+	for round := 1; round <= b.losersRounds(); round++ {
+		add(domain.BracketLosers, round)
+	}
+	if b.double() {
+		add(domain.BracketFinal, 1)
+		add(domain.BracketFinal, 2)
+	}
+	return slots
+}
+
+// participants returns the sides of the game.
+func (b *bracket) participants(s slot) (side, side) {
+	switch s.bracket {
+	case domain.BracketWinners:
+		if s.round == 1 {
+			return side{player: b.seeds[2*s.position], known: true}, side{player: b.seeds[2*s.position+1], known: true}
+		}
+		return b.winner(slot{domain.BracketWinners, s.round - 1, 2 * s.position}),
+			b.winner(slot{domain.BracketWinners, s.round - 1, 2*s.position + 1})
+	case domain.BracketLosers:
+		switch {
+		case s.round == 1:
+			return b.loser(slot{domain.BracketWinners, 1, 2 * s.position}),
+				b.loser(slot{domain.BracketWinners, 1, 2*s.position + 1})
+		case s.round%2 == 0:
+			// the dropped players come in the reverse order, so they do not meet the same opponents again
+			winners := slot{domain.BracketWinners, s.round/2 + 1, 0}
+			winners.position = b.count(domain.BracketWinners, winners.round) - 1 - s.position
+			return b.winner(slot{domain.BracketLosers, s.round - 1, s.position}), b.loser(winners)
+		default:
+			return b.winner(slot{domain.BracketLosers, s.round - 1, 2 * s.position}),
+				b.winner(slot{domain.BracketLosers, s.round - 1, 2*s.position + 1})
+		}
+	}
+	a, c := b.winner(slot{domain.BracketWinners, b.rounds, 0}), b.losersChampion()
+	if s.round == 1 {
+		return a, c
+	}
+	first := b.winner(slot{domain.BracketFinal, 1, 0})
+	switch {
+	case !first.known:
+		return side{}, side{}
+	case first.player.ID == c.player.ID && !first.empty():
+		return a, c
+	}
+	// the final is not replayed
+	return side{known: true}, side{known: true}
+}
+
+// losersChampion returns the winner of the losers bracket.
+func (b *bracket) losersChampion() side {
+	if b.losersRounds() == 0 {
+		return b.loser(slot{domain.BracketWinners, b.rounds, 0})
+	}
+	return b.winner(slot{domain.BracketLosers, b.losersRounds(), 0})
+}
+
+// result returns the winner and the loser of the game, false until it is played.
+// An empty side loses to any player without a game.
+func (b *bracket) result(s slot) (side, side, bool) {
+	o, ok := b.outcomes[s]
+	if !ok {
+		o.winner, o.loser, o.ok = b.resolve(s)
+		b.outcomes[s] = o
+	}
+	return o.winner, o.loser, o.ok
+}
+
+func (b *bracket) resolve(s slot) (side, side, bool) {
+	a, c := b.participants(s)
+	switch {
+	case a.empty():
+		return c, a, c.known
+	case c.empty():
+		return a, c, a.known
+	case !a.known || !c.known:
+		return side{}, side{}, false
+	}
+	game, ok := b.results[s]
+	if !ok || !game.Has(a.player.ID, c.player.ID) {
+		return side{}, side{}, false
+	}
+	switch game.Match.Winner.ID {
+	case a.player.ID:
+		return a, c, true
+	case c.player.ID:
+		return c, a, true
+	}
+	// a draw does not decide the game
+	return side{}, side{}, false
+}
+
+func (b *bracket) winner(s slot) side {
+	winner, _, _ := b.result(s)
+	return winner
+}
+
+func (b *bracket) loser(s slot) side {
+	_, loser, _ := b.result(s)
+	return loser
+}
+
+// champion returns the winner of the tournament.
+func (b *bracket) champion() side {
+	if !b.double() {
+		return b.winner(slot{domain.BracketWinners, b.rounds, 0})
+	}
+	first := b.winner(slot{domain.BracketFinal, 1, 0})
+	if first.known && first.player.ID == b.winner(slot{domain.BracketWinners, b.rounds, 0}).player.ID {
+		return first
+	}
+	return b.winner(slot{domain.BracketFinal, 2, 0})
+}
+
+// games returns the games with both sides known, the players with an empty opponent get a bye.
+func (b *bracket) games() []domain.TournamentGame {
+	var games []domain.TournamentGame
+	for _, s := range b.slots() {
+		a, c := b.participants(s)
+		if !a.known || !c.known || a.empty() && c.empty() {
+			continue
+		}
+		if a.empty() {
+			a, c = c, a
+		}
+		game := domain.TournamentGame{
+			Bracket:  s.bracket,
+			Round:    s.round,
+			Position: s.position,
+			PlayerA:  a.player,
+			PlayerB:  c.player,
+		}
+		if result, ok := b.results[s]; ok && !c.empty() && result.Has(a.player.ID, c.player.ID) {
+			game.MatchID, game.Match = result.MatchID, result.Match
+		}
+		games = append(games, game)
+	}
+	return games
+}
+
+// stages returns the number of the stages the players can be knocked out at.
+func (b *bracket) stages() int {
+	if b.double() {
+		return b.losersRounds() + 1
+	}
+	return b.rounds
+}
+
+// eliminated returns the stage every knocked out player has lost at, the later stages are greater.
+// The winners bracket knocks out only in a single elimination, the losers bracket and the final
+// in a double one.
+func (b *bracket) eliminated() map[uuid.UUID]int {
+	stages := make(map[uuid.UUID]int)
+	for _, s := range b.slots() {
+		winner, loser, ok := b.result(s)
+		if !ok || loser.empty() {
+			continue
+		}
+		switch {
+		case !b.double():
+			stages[loser.player.ID] = s.round
+		case s.bracket == domain.BracketLosers:
+			stages[loser.player.ID] = s.round
+		case s.bracket == domain.BracketFinal:
+			if s.round == 1 && winner.player.ID != b.winner(slot{domain.BracketWinners, b.rounds, 0}).player.ID {
+				// the winner of the winners bracket has lost once, the final is replayed
+				continue
+			}
+			stages[loser.player.ID] = b.stages()
+		}
+	}
+	return stages
+}
@@ -0,0 +1,304 @@
+package tournament
+
+import (
+	"sort"
+
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Pairings returns the games to add to the tournament: all the rounds of a round robin
+// before it starts and the next round of a swiss tournament once the current one is played.
+// The swiss players with equal points are paired by the ratings.
+// The games of the elimination brackets are never stored in advance, see Games.
+func Pairings(t domain.Tournament, ratings map[uuid.UUID]float64) []domain.TournamentGame {
+	switch t.Format {
+	case domain.TournamentRoundRobin:
+		if len(t.Games) > 0 {
+			return nil
+		}
+		return roundRobin(t.Players)
+	case domain.TournamentSwiss:
+		return swissRound(t, ratings)
+	}
+	return nil
+}
+
+// Games returns the games of the tournament with the players known by now.
+// The stored games are returned as is, the games of the elimination brackets are resolved
+// from the seeds and the results of the stored ones.
+func Games(t domain.Tournament, stored []domain.TournamentGame) []domain.TournamentGame {
+	if !t.Format.Elimination() {
+		return stored
+	}
+	return newBracket(t, stored).games()
+}
+
+// Complete reports whether all the games of the tournament are played.
+func Complete(t domain.Tournament) bool {
+	switch t.Format {
+	case domain.TournamentSwiss:
+		if t.Round() < t.Rounds {
+			return false
+		}
+	case domain.TournamentSingleElimination, domain.TournamentDoubleElimination:
+		_, ok := newBracket(t, t.Games).champion().get()
+		return ok
+	}
+	return len(t.Pending()) == 0
+}
+
+// roundRobin pairs everyone with everyone by the circle method, the first player stays
+// and the others rotate around them. With an odd number of players one of them rests every round.
+func roundRobin(players []domain.Player) []domain.TournamentGame {
+	circle := make([]domain.Player, len(players), len(players)+1)
+	copy(circle, players)
+	if len(circle)%2 == 1 {
+		circle = append(circle, domain.Player{})
+	}
+	n := len(circle)
+	var games []domain.TournamentGame
+	for round := 1; round < n; round++ {
+		position := 0
+		for i := 0; i < n/2; i++ {
+			a, b := circle[i], circle[n-1-i]
+			if a.ID == uuid.Nil || b.ID == uuid.Nil {
+				continue
+			}
+			if i == 0 && round%2 == 0 {
+				// the fixed player changes the sides
+				a, b = b, a
+			}
+			games = append(games, domain.TournamentGame{
+				Bracket:  domain.BracketMain,
+				Round:    round,
+				Position: position,
+				PlayerA:  a,
+				PlayerB:  b,
+			})
+			position++
+		}
+		circle = append([]domain.Player{circle[0], circle[n-1]}, circle[1:n-1]...)
+	}
+	return games
+}
+
+// swissRound pairs the players with the closest points who have not met yet.
+// With an odd number of players the lowest ranked one without a bye gets it.
+func swissRound(t domain.Tournament, ratings map[uuid.UUID]float64) []domain.TournamentGame {
+	round := t.Round()
+	if round >= t.Rounds || len(t.Pending()) > 0 {
+		return nil
+	}
+	records := scores(t)
+	seeds := make(map[uuid.UUID]int, len(t.Players))
+	for i, player := range t.Players {
+		seeds[player.ID] = i
+	}
+	order := make([]domain.Player, len(t.Players))
+	copy(order, t.Players)
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i].ID, order[j].ID
+		if records[a].Points != records[b].Points {
+			return records[a].Points > records[b].Points
+		}
+		if ratings[a] != ratings[b] {
+			return ratings[a] > ratings[b]
+		}
+		return seeds[a] < seeds[b]
+	})
+
+	var games []domain.TournamentGame
+	next := func(a, b domain.Player) {
+		games = append(games, domain.TournamentGame{
+			Bracket:  domain.BracketMain,
+			Round:    round + 1,
+			Position: len(games),
+			PlayerA:  a,
+			PlayerB:  b,
+		})
+	}
+	var bye []domain.Player
+	if len(order)%2 == 1 {
+		i := len(order) - 1
+		for i > 0 && records[order[i].ID].bye {
+			i--
+		}
+		bye = append(bye, order[i])
+		order = append(order[:i:i], order[i+1:]...)
+	}
+	met := func(a, b uuid.UUID) bool {
+		return records[a].opponents[b]
+	}
+	pairs := pairUp(order, met)
+	if pairs == nil {
+		// everyone has met everyone they could be paired with, the neighbours play again
+		for i := 0; i+1 < len(order); i += 2 {
+			pairs = append(pairs, [2]domain.Player{order[i], order[i+1]})
+		}
+	}
+	for _, pair := range pairs {
+		next(pair[0], pair[1])
+	}
+	for _, player := range bye {
+		next(player, domain.Player{})
+	}
+	return games
+}
+
+// pairUp pairs the first player with the highest ranked one they have not met
+// and the rest of the players the same way, backtracking if the rest cannot be paired.
+// It returns nil if there are no such pairings.
+func pairUp(players []domain.Player, met func(a, b uuid.UUID) bool) [][2]domain.Player {
+	if len(players) == 0 {
+		return [][2]domain.Player{}
+	}
+	first := players[0]
+	for i := 1; i < len(players); i++ {
+		if met(first.ID, players[i].ID) {
+			continue
+		}
+		rest := make([]domain.Player, 0, len(players)-2)
+		rest = append(rest, players[1:i]...)
+		rest = append(rest, players[i+1:]...)
+		if pairs := pairUp(rest, met); pairs != nil {
+			return append([][2]domain.Player{{first, players[i]}}, pairs...)
+		}
+	}
+	return nil
+}
+
+// record is the results of a player in the played games.
+type record struct {
+	domain.TournamentStanding
+	bye       bool
+	opponents map[uuid.UUID]bool
+	beaten    map[uuid.UUID]bool
+	drawn     []uuid.UUID
+}
+
+// scores returns the results of the players by their IDs.
+func scores(t domain.Tournament) map[uuid.UUID]*record {
+	records := make(map[uuid.UUID]*record, len(t.Players))
+	for _, player := range t.Players {
+		records[player.ID] = &record{
+			TournamentStanding: domain.TournamentStanding{Player: player},
+			opponents:          make(map[uuid.UUID]bool),
+			beaten:             make(map[uuid.UUID]bool),
+		}
+	}
+	for _, game := range t.Games {
+		a, b := records[game.PlayerA.ID], records[game.PlayerB.ID]
+		if a == nil || !game.Played() {
+			continue
+		}
+		if game.Bye() {
+			a.bye = true
+			a.Points++
+			continue
+		}
+		if b == nil {
+			continue
+		}
+		a.opponents[b.Player.ID] = true
+		b.opponents[a.Player.ID] = true
+		switch winner := game.Winner().ID; winner {
+		case uuid.Nil:
+			a.Draws++
+			b.Draws++
+			a.Points += 0.5
+			b.Points += 0.5
+			a.drawn = append(a.drawn, b.Player.ID)
+			b.drawn = append(b.drawn, a.Player.ID)
+		case a.Player.ID:
+			a.Wins++
+			b.Losses++
+			a.Points++
+			a.beaten[b.Player.ID] = true
+		default:
+			b.Wins++
+			a.Losses++
+			b.Points++
+			b.beaten[a.Player.ID] = true
+		}
+	}
+	return records
+}
+
+// Standings returns the places of the players. Round robin and swiss players are ordered
+// by the points, the Buchholz and the Sonneborn-Berger scores and the number of wins,
+// the players of the elimination brackets by the round they were knocked out in.
+// The players equal by all of them share the place.
+func Standings(t domain.Tournament) []domain.TournamentStanding {
+	records := scores(t)
+	if t.Format.Elimination() {
+		return eliminationStandings(t, records)
+	}
+	for _, r := range records {
+		for id := range r.opponents {
+			r.Buchholz += records[id].Points
+			if r.beaten[id] {
+				r.SonnebornBerger += records[id].Points
+			}
+		}
+		for _, id := range r.drawn {
+			r.SonnebornBerger += records[id].Points / 2
+		}
+	}
+	standings := make([]domain.TournamentStanding, 0, len(t.Players))
+	for _, player := range t.Players {
+		standings = append(standings, records[player.ID].TournamentStanding)
+	}
+	less := func(a, b domain.TournamentStanding) bool {
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.Buchholz != b.Buchholz:
+			return a.Buchholz > b.Buchholz
+		case a.SonnebornBerger != b.SonnebornBerger:
+			return a.SonnebornBerger > b.SonnebornBerger
+		}
+		return a.Wins > b.Wins
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return less(standings[i], standings[j])
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		if i > 0 && !less(standings[i-1], standings[i]) {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+	return standings
+}
+
+// eliminationStandings places the players by the stage they were knocked out at,
+// the players still in the tournament share the place after the last stage.
+func eliminationStandings(t domain.Tournament, records map[uuid.UUID]*record) []domain.TournamentStanding {
+	b := newBracket(t, t.Games)
+	stages := b.eliminated()
+	last := b.stages() + 1
+	standings := make([]domain.TournamentStanding, 0, len(t.Players))
+	for _, player := range t.Players {
+		standing := records[player.ID].TournamentStanding
+		standing.Eliminated = stages[player.ID] != 0
+		standings = append(standings, standing)
+	}
+	stage := func(s domain.TournamentStanding) int {
+		if s.Eliminated {
+			return stages[s.Player.ID]
+		}
+		return last
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return stage(standings[i]) > stage(standings[j])
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		if i > 0 && stage(standings[i-1]) == stage(standings[i]) {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+	return standings
+}
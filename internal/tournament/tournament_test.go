@@ -0,0 +1,293 @@
+package tournament
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/goserg/ratingserver/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func newPlayers(n int) []domain.Player {
+	players := make([]domain.Player, n)
+	for i := range players {
+		players[i] = domain.Player{ID: uuid.New(), Name: "player" + strconv.Itoa(i+1)}
+	}
+	return players
+}
+
+// play records the results of the games until the tournament is complete.
+// The winner returns the winner of a game, an empty player for a draw.
+func play(t *testing.T, tournament domain.Tournament, winner func(a, b domain.Player) domain.Player) domain.Tournament {
+	t.Helper()
+	stored := Pairings(tournament, nil)
+	for id := 1; ; id++ {
+		if id > 1000 {
+			t.Fatal("the tournament never completes")
+		}
+		tournament.Games = Games(tournament, stored)
+		pending := tournament.Pending()
+		if len(pending) == 0 {
+			next := Pairings(tournament, nil)
+			if len(next) == 0 {
+				break
+			}
+			stored = append(stored, next...)
+			continue
+		}
+		game := pending[0]
+		game.MatchID = id
+		game.Match = &domain.Match{ID: id, PlayerA: game.PlayerA, PlayerB: game.PlayerB, Winner: winner(game.PlayerA, game.PlayerB)}
+		replaced := false
+		for i := range stored {
+			if stored[i].Bracket == game.Bracket && stored[i].Round == game.Round && stored[i].Position == game.Position {
+				stored[i], replaced = game, true
+			}
+		}
+		if !replaced {
+			stored = append(stored, game)
+		}
+	}
+	if !Complete(tournament) {
+		t.Fatal("all the games are played, but the tournament is not complete")
+	}
+	tournament.Standings = Standings(tournament)
+	return tournament
+}
+
+// stronger makes the player with the lower number win.
+func stronger(a, b domain.Player) domain.Player {
+	if a.Name < b.Name {
+		return a
+	}
+	return b
+}
+
+func places(standings []domain.TournamentStanding) map[string]int {
+	places := make(map[string]int, len(standings))
+	for _, standing := range standings {
+		places[standing.Player.Name] = standing.Place
+	}
+	return places
+}
+
+func TestRoundRobin(t *testing.T) {
+	for _, n := range []int{2, 4, 5, 8} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			tournament := play(t, domain.Tournament{Format: domain.TournamentRoundRobin, Players: newPlayers(n)}, stronger)
+			met := make(map[[2]uuid.UUID]int)
+			busy := make(map[int]map[uuid.UUID]bool)
+			for _, game := range tournament.Games {
+				if game.Bye() {
+					t.Fatal("round robin has no byes")
+				}
+				if busy[game.Round] == nil {
+					busy[game.Round] = make(map[uuid.UUID]bool)
+				}
+				for _, id := range []uuid.UUID{game.PlayerA.ID, game.PlayerB.ID} {
+					if busy[game.Round][id] {
+						t.Errorf("a player plays twice in round %d", game.Round)
+					}
+					busy[game.Round][id] = true
+				}
+				key := [2]uuid.UUID{game.PlayerA.ID, game.PlayerB.ID}
+				if game.PlayerB.ID.String() < game.PlayerA.ID.String() {
+					key = [2]uuid.UUID{game.PlayerB.ID, game.PlayerA.ID}
+				}
+				met[key]++
+			}
+			if want := n * (n - 1) / 2; len(met) != want || len(tournament.Games) != want {
+				t.Errorf("%d games between %d pairs, want %d", len(tournament.Games), len(met), want)
+			}
+			for i, standing := range tournament.Standings {
+				if standing.Place != i+1 || standing.Player.Name != "player"+strconv.Itoa(i+1) || standing.Wins != n-1-i {
+					t.Errorf("standing %d = %+v", i, standing)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundRobin_Tiebreaks(t *testing.T) {
+	players := newPlayers(3)
+	// everyone beats one and loses to one, the draw is not possible
+	cycle := func(a, b domain.Player) domain.Player {
+		beats := map[string]string{"player1": "player2", "player2": "player3", "player3": "player1"}
+		if beats[a.Name] == b.Name {
+			return a
+		}
+		return b
+	}
+	tournament := play(t, domain.Tournament{Format: domain.TournamentRoundRobin, Players: players}, cycle)
+	for _, standing := range tournament.Standings {
+		if standing.Place != 1 || standing.Points != 1 || standing.Buchholz != 2 || standing.SonnebornBerger != 1 {
+			t.Errorf("standing of %s = %+v, want a shared first place", standing.Player.Name, standing)
+		}
+	}
+
+	// player1 and player2 draw and both beat player3, player1 beats player4 and player2 draws with them
+	results := map[string]string{"player1 player3": "player1", "player2 player3": "player2", "player1 player4": "player1",
+		"player3 player4": "player3"}
+	tournament = play(t, domain.Tournament{Format: domain.TournamentRoundRobin, Players: newPlayers(4)}, func(a, b domain.Player) domain.Player {
+		if a.Name > b.Name {
+			a, b = b, a
+		}
+		if results[a.Name+" "+b.Name] == a.Name {
+			return a
+		}
+		if results[a.Name+" "+b.Name] == b.Name {
+			return b
+		}
+		return domain.Player{}
+	})
+	want := map[string]int{"player1": 1, "player2": 2, "player3": 3, "player4": 4}
+	if got := places(tournament.Standings); !reflect.DeepEqual(got, want) {
+		t.Errorf("places = %v, want %v", got, want)
+	}
+	if got := tournament.Standings[0].PointsString(); got != "2.5" {
+		t.Errorf("points of the winner = %s, want 2.5", got)
+	}
+}
+
+func TestSwiss(t *testing.T) {
+	for _, n := range []int{4, 5, 8} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			tournament := play(t, domain.Tournament{Format: domain.TournamentSwiss, Players: newPlayers(n), Rounds: 3}, stronger)
+			if tournament.Round() != 3 {
+				t.Fatalf("played %d rounds, want 3", tournament.Round())
+			}
+			met := make(map[[2]uuid.UUID]bool)
+			byes := make(map[uuid.UUID]int)
+			for _, game := range tournament.Games {
+				if game.Bye() {
+					byes[game.PlayerA.ID]++
+					continue
+				}
+				key := [2]uuid.UUID{game.PlayerA.ID, game.PlayerB.ID}
+				if game.PlayerB.ID.String() < game.PlayerA.ID.String() {
+					key = [2]uuid.UUID{game.PlayerB.ID, game.PlayerA.ID}
+				}
+				if met[key] {
+					t.Errorf("%s and %s meet again", game.PlayerA.Name, game.PlayerB.Name)
+				}
+				met[key] = true
+			}
+			for _, count := range byes {
+				if count > 1 {
+					t.Error("a player gets two byes")
+				}
+			}
+			if n%2 == 1 && len(byes) != 3 {
+				t.Errorf("%d players got a bye, want one every round", len(byes))
+			}
+			if winner := tournament.Standings[0]; winner.Player.Name != "player1" || winner.Points != 3 || winner.Place != 1 {
+				t.Errorf("winner = %+v, want player1 with 3 points", winner)
+			}
+		})
+	}
+}
+
+func TestSwiss_PairsByRating(t *testing.T) {
+	players := newPlayers(4)
+	ratings := map[uuid.UUID]float64{players[0].ID: 1000, players[1].ID: 1100, players[2].ID: 1200, players[3].ID: 900}
+	games := Pairings(domain.Tournament{Format: domain.TournamentSwiss, Players: players, Rounds: 2}, ratings)
+	if len(games) != 2 || !games[0].Has(players[2].ID, players[1].ID) || !games[1].Has(players[0].ID, players[3].ID) {
+		t.Errorf("first round = %v, want the closest ratings paired", games)
+	}
+}
+
+func TestSingleElimination(t *testing.T) {
+	tournament := play(t, domain.Tournament{Format: domain.TournamentSingleElimination, Players: newPlayers(6)}, stronger)
+	byes := 0
+	for _, game := range tournament.Games {
+		if game.Bye() {
+			byes++
+			if game.Round != 1 || game.PlayerA.Name != "player1" && game.PlayerA.Name != "player2" {
+				t.Errorf("bye of %s in round %d, want the top seeds in the first round", game.PlayerA.Name, game.Round)
+			}
+		}
+	}
+	if byes != 2 {
+		t.Errorf("%d byes, want 2", byes)
+	}
+	want := map[string]int{"player1": 1, "player2": 2, "player3": 3, "player4": 3, "player5": 5, "player6": 5}
+	if got := places(tournament.Standings); !reflect.DeepEqual(got, want) {
+		t.Errorf("places = %v, want %v", got, want)
+	}
+	if champion, ok := tournament.Champion(); ok {
+		t.Errorf("unfinished tournament has a champion %s", champion.Name)
+	}
+}
+
+func TestDoubleElimination(t *testing.T) {
+	for _, n := range []int{2, 3, 4, 5, 8} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			tournament := play(t, domain.Tournament{Format: domain.TournamentDoubleElimination, Players: newPlayers(n)}, stronger)
+			losses := make(map[string]int)
+			for _, game := range tournament.Games {
+				if loser := game.Loser(); loser.ID != uuid.Nil {
+					losses[loser.Name]++
+				}
+			}
+			for _, standing := range tournament.Standings {
+				want := 2
+				if standing.Place == 1 {
+					want = 0
+				}
+				if losses[standing.Player.Name] != want {
+					t.Errorf("%s lost %d times, want %d", standing.Player.Name, losses[standing.Player.Name], want)
+				}
+				if standing.Eliminated != (standing.Place != 1) {
+					t.Errorf("%s eliminated = %v at place %d", standing.Player.Name, standing.Eliminated, standing.Place)
+				}
+			}
+			if got := places(tournament.Standings); got["player1"] != 1 || got["player2"] != 2 {
+				t.Errorf("places = %v, want player1 and player2 first", got)
+			}
+		})
+	}
+}
+
+func TestDoubleElimination_ReplayedFinal(t *testing.T) {
+	// player2 loses to player1 in the winners bracket and beats them in both finals
+	finals := 0
+	tournament := play(t, domain.Tournament{Format: domain.TournamentDoubleElimination, Players: newPlayers(4)}, func(a, b domain.Player) domain.Player {
+		if a.Name == "player1" && b.Name == "player2" || a.Name == "player2" && b.Name == "player1" {
+			finals++
+			if finals > 1 {
+				if a.Name == "player2" {
+					return a
+				}
+				return b
+			}
+		}
+		return stronger(a, b)
+	})
+	var final []domain.TournamentGame
+	for _, game := range tournament.Games {
+		if game.Bracket == domain.BracketFinal {
+			final = append(final, game)
+		}
+	}
+	if len(final) != 2 {
+		t.Fatalf("%d finals played, want 2", len(final))
+	}
+	want := map[string]int{"player2": 1, "player1": 2, "player3": 3, "player4": 4}
+	if got := places(tournament.Standings); !reflect.DeepEqual(got, want) {
+		t.Errorf("places = %v, want %v", got, want)
+	}
+}
+
+func TestGames_DrawDoesNotAdvance(t *testing.T) {
+	players := newPlayers(2)
+	tournament := domain.Tournament{Format: domain.TournamentSingleElimination, Players: players}
+	game := Games(tournament, nil)[0]
+	game.MatchID = 1
+	game.Match = &domain.Match{ID: 1, PlayerA: game.PlayerA, PlayerB: game.PlayerB}
+	tournament.Games = Games(tournament, []domain.TournamentGame{game})
+	if Complete(tournament) {
+		t.Error("a draw completes the elimination tournament")
+	}
+}
@@ -0,0 +1,166 @@
+package trueskill
+
+import "math"
+
+// Rating is a player's skill belief: the mean and the standard deviation.
+type Rating struct {
+	Mu    float64
+	Sigma float64
+}
+
+// Conservative returns the skill estimate the player exceeds with ~99% probability.
+func (r Rating) Conservative() float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+type Params struct {
+	// Mu and Sigma of a new player.
+	Mu    float64
+	Sigma float64
+	// Beta is the distance in skill which guarantees ~76% chance of winning.
+	Beta float64
+	// Tau is the dynamic factor added to sigma before every match.
+	Tau float64
+	// DrawProbability is the expected share of draws between equal players.
+	DrawProbability float64
+}
+
+// DefaultParams are the values from the original TrueSkill paper.
+func DefaultParams() Params {
+	return Params{
+		Mu:              25,
+		Sigma:           25.0 / 3,
+		Beta:            25.0 / 6,
+		Tau:             25.0 / 300,
+		DrawProbability: 0.1,
+	}
+}
+
+// NewRating returns the rating of a new player.
+func (p Params) NewRating() Rating {
+	return Rating{Mu: p.Mu, Sigma: p.Sigma}
+}
+
+// Calculate new ratings of a two player match.
+// If draw is true the order of players does not matter.
+func Calculate(winner Rating, loser Rating, draw bool, p Params) (Rating, Rating) {
+	winners, losers := CalculateTeams([]Rating{winner}, []Rating{loser}, draw, p)
+	return winners[0], losers[0]
+}
+
+// CalculateTeams calculates new ratings of a match between two teams,
+// the team performance is the sum of the performances of its members.
+// If draw is true the order of teams does not matter.
+func CalculateTeams(winners []Rating, losers []Rating, draw bool, p Params) ([]Rating, []Rating) {
+	winnerVariances, winnerMu, winnerVariance := teamVariances(winners, p)
+	loserVariances, loserMu, loserVariance := teamVariances(losers, p)
+	players := float64(len(winners) + len(losers))
+	c := math.Sqrt(players*p.Beta*p.Beta + winnerVariance + loserVariance)
+	t := (winnerMu - loserMu) / c
+	e := drawMargin(p) / c
+
+	var v, w float64
+	if draw {
+		v, w = vDraw(t, e), wDraw(t, e)
+	} else {
+		v, w = vWin(t, e), wWin(t, e)
+	}
+	newWinners := make([]Rating, len(winners))
+	for i, r := range winners {
+		variance := winnerVariances[i]
+		newWinners[i] = Rating{
+			Mu:    r.Mu + variance/c*v,
+			Sigma: math.Sqrt(variance * math.Max(1-variance/(c*c)*w, 0)),
+		}
+	}
+	newLosers := make([]Rating, len(losers))
+	for i, r := range losers {
+		variance := loserVariances[i]
+		newLosers[i] = Rating{
+			Mu:    r.Mu - variance/c*v,
+			Sigma: math.Sqrt(variance * math.Max(1-variance/(c*c)*w, 0)),
+		}
+	}
+	return newWinners, newLosers
+}
+
+// teamVariances returns the variances of the members with the dynamic factor added,
+// the sum of their means and the sum of the variances.
+func teamVariances(team []Rating, p Params) ([]float64, float64, float64) {
+	variances := make([]float64, len(team))
+	var mu, variance float64
+	for i, r := range team {
+		variances[i] = r.Sigma*r.Sigma + p.Tau*p.Tau
+		mu += r.Mu
+		variance += variances[i]
+	}
+	return variances, mu, variance
+}
+
+// WinProbability returns the probability of player a performing better than player b.
+func WinProbability(a, b Rating, p Params) float64 {
+	c := math.Sqrt(2*p.Beta*p.Beta + a.Sigma*a.Sigma + b.Sigma*b.Sigma)
+	return cdf((a.Mu - b.Mu) / c)
+}
+
+func drawMargin(p Params) float64 {
+	return invCDF((p.DrawProbability+1)/2) * math.Sqrt2 * p.Beta
+}
+
+func pdf(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func cdf(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func invCDF(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+func vWin(t, e float64) float64 {
+	denom := cdf(t - e)
+	if denom < 2.222758749e-162 {
+		return -t + e
+	}
+	return pdf(t-e) / denom
+}
+
+func wWin(t, e float64) float64 {
+	denom := cdf(t - e)
+	if denom < 2.222758749e-162 {
+		if t < 0 {
+			return 1
+		}
+		return 0
+	}
+	v := vWin(t, e)
+	return v * (v + t - e)
+}
+
+func vDraw(t, e float64) float64 {
+	a := math.Abs(t)
+	denom := cdf(e-a) - cdf(-e-a)
+	if denom < 2.222758749e-162 {
+		if t < 0 {
+			return -t - e
+		}
+		return -t + e
+	}
+	v := (pdf(-e-a) - pdf(e-a)) / denom
+	if t < 0 {
+		return -v
+	}
+	return v
+}
+
+func wDraw(t, e float64) float64 {
+	a := math.Abs(t)
+	denom := cdf(e-a) - cdf(-e-a)
+	if denom < 2.222758749e-162 {
+		return 1
+	}
+	v := vDraw(a, e)
+	return v*v + ((e-a)*pdf(e-a)+(e+a)*pdf(e+a))/denom
+}
@@ -0,0 +1,83 @@
+package trueskill
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	p := DefaultParams()
+	tests := []struct {
+		name       string
+		winner     Rating
+		loser      Rating
+		draw       bool
+		wantWinner Rating
+		wantLoser  Rating
+	}{
+		{
+			name:       "new players win",
+			winner:     p.NewRating(),
+			loser:      p.NewRating(),
+			wantWinner: Rating{Mu: 29.396, Sigma: 7.171},
+			wantLoser:  Rating{Mu: 20.604, Sigma: 7.171},
+		},
+		{
+			name:       "new players draw",
+			winner:     p.NewRating(),
+			loser:      p.NewRating(),
+			draw:       true,
+			wantWinner: Rating{Mu: 25, Sigma: 6.458},
+			wantLoser:  Rating{Mu: 25, Sigma: 6.458},
+		},
+		{
+			name:       "upset",
+			winner:     Rating{Mu: 20, Sigma: 2},
+			loser:      Rating{Mu: 30, Sigma: 2},
+			wantWinner: Rating{Mu: 21.263, Sigma: 1.919},
+			wantLoser:  Rating{Mu: 28.737, Sigma: 1.919},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotWinner, gotLoser := Calculate(tt.winner, tt.loser, tt.draw, p)
+			if !isEqual(gotWinner, tt.wantWinner) || !isEqual(gotLoser, tt.wantLoser) {
+				t.Errorf("Calculate() = %v, %v, want %v, %v", gotWinner, gotLoser, tt.wantWinner, tt.wantLoser)
+			}
+		})
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	p := DefaultParams()
+	if got := WinProbability(p.NewRating(), p.NewRating(), p); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("WinProbability() of equal players = %v, want 0.5", got)
+	}
+	strong := Rating{Mu: 30, Sigma: 2}
+	weak := Rating{Mu: 20, Sigma: 2}
+	if got, reverse := WinProbability(strong, weak, p), WinProbability(weak, strong, p); math.Abs(got+reverse-1) > 1e-9 || got <= 0.5 {
+		t.Errorf("WinProbability() = %v and %v, want complementary and the stronger one above 0.5", got, reverse)
+	}
+}
+
+func TestCalculateTeams(t *testing.T) {
+	p := DefaultParams()
+	winners, losers := CalculateTeams(
+		[]Rating{p.NewRating(), p.NewRating()},
+		[]Rating{p.NewRating(), p.NewRating()},
+		false, p)
+	if !isEqual(winners[0], winners[1]) || !isEqual(losers[0], losers[1]) {
+		t.Errorf("CalculateTeams() = %v, %v, want equal changes within a team", winners, losers)
+	}
+	if math.Abs(winners[0].Mu-p.Mu-(p.Mu-losers[0].Mu)) > 1e-9 || winners[0].Mu <= p.Mu {
+		t.Errorf("CalculateTeams() = %v, %v, want symmetric changes", winners, losers)
+	}
+	single, _ := Calculate(p.NewRating(), p.NewRating(), false, p)
+	if winners[0].Mu >= single.Mu {
+		t.Errorf("CalculateTeams() winner mu = %v, want less than %v of a single player", winners[0].Mu, single.Mu)
+	}
+}
+
+func isEqual(a, b Rating) bool {
+	return math.Abs(a.Mu-b.Mu) < 0.001 && math.Abs(a.Sigma-b.Sigma) < 0.001
+}
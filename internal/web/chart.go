@@ -0,0 +1,18 @@
+package web
+
+import (
+	"html/template"
+
+	"github.com/goserg/ratingserver/internal/chart"
+	"github.com/goserg/ratingserver/internal/domain"
+)
+
+// trendChart draws the primary ratings of the players of the head-to-head like the chart
+// of the player card, the legend names the players.
+func trendChart(h2h domain.HeadToHead) template.HTML {
+	image := chart.Rating(h2h.PlayerA.Name+" и "+h2h.PlayerB.Name, []domain.RatingSeries{
+		{System: domain.RatingSystem{Title: h2h.PlayerA.Name}, Points: h2h.TrendA},
+		{System: domain.RatingSystem{Title: h2h.PlayerB.Name}, Points: h2h.TrendB},
+	})
+	return template.HTML(image)
+}
@@ -4,13 +4,16 @@ import (
 	"errors"
 
 	"github.com/goserg/ratingserver/auth/users"
+	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/web/webpath"
 )
 
 type data struct {
-	Title  string
-	Path   map[string]string
-	User   users.User
+	Title string
+	Path  map[string]string
+	User  users.User
+	// Admin is set for the users allowed to change the recorded matches.
+	Admin  bool
 	Errors []string
 	Data   map[string]any
 }
@@ -25,9 +28,24 @@ func newData(title string) data {
 
 func (m data) WithUser(user users.User) data {
 	m.User = user
+	m.Admin = isAdmin(user)
 	return m
 }
 
+func isAdmin(user users.User) bool {
+	for _, role := range user.Roles {
+		if role == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDiscipline adds the current discipline and the list of all disciplines for the menu.
+func (m data) WithDiscipline(current domain.Discipline, all []domain.Discipline) data {
+	return m.With("Discipline", current).With("Disciplines", all)
+}
+
 func (m data) With(key string, value any) data {
 	if m.Data == nil {
 		m.Data = make(map[string]any)
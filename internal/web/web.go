@@ -4,7 +4,9 @@ import (
 	"errors"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,6 +16,7 @@ import (
 	embedded "github.com/goserg/ratingserver"
 	authservice "github.com/goserg/ratingserver/auth/service"
 	"github.com/goserg/ratingserver/auth/users"
+	"github.com/goserg/ratingserver/internal/chart"
 	"github.com/goserg/ratingserver/internal/config"
 	"github.com/goserg/ratingserver/internal/domain"
 	"github.com/goserg/ratingserver/internal/normalize"
@@ -43,6 +46,7 @@ func New(ps *service.PlayerService, cfg config.Server, authService *authservice.
 	engine.Reload(cfg.Debug)
 	engine.Debug(cfg.Debug)
 	engine.AddFunc("FormatDate", formatDate)
+	engine.AddFunc("TrendChart", trendChart)
 
 	app := fiber.New(fiber.Config{
 		Views: engine,
@@ -53,7 +57,7 @@ func New(ps *service.PlayerService, cfg config.Server, authService *authservice.
 	}))
 	app.Use(webpath.Api, func(c *fiber.Ctx) error {
 		tokenCookie := c.Cookies("token")
-		user, err := authService.Auth(c.Context(), tokenCookie, c.Method(), c.OriginalURL())
+		user, err := authService.Auth(c.Context(), tokenCookie, c.Method(), c.Path())
 		if err != nil {
 			switch {
 			case errors.Is(err, authservice.ErrForbidden):
@@ -81,9 +85,37 @@ func New(ps *service.PlayerService, cfg config.Server, authService *authservice.
 	app.Get(webpath.ApiMatchesList, server.handleMatches)
 	app.Get(webpath.ApiNewMatch, server.handleCreateMatchGet)
 	app.Post(webpath.ApiNewMatch, server.handleCreateMatchPost)
+	app.Get(webpath.ApiNewFFAMatch, server.handleCreateFFAMatchGet)
+	app.Post(webpath.ApiNewFFAMatch, server.handleCreateFFAMatchPost)
+	app.Get(webpath.ApiMatch, server.handleEditMatchGet)
+	app.Post(webpath.ApiMatch, server.handleEditMatchPost)
+	app.Post(webpath.ApiVoidMatch, server.handleVoidMatchPost)
+	app.Post(webpath.ApiDeleteMatch, server.handleDeleteMatchPost)
+	app.Get(webpath.ApiAudit, server.handleAudit)
 	app.Get(webpath.ApiGetPlayers, server.handlePlayerInfo)
+	app.Get(webpath.ApiPlayerChart, server.handlePlayerChart)
+	app.Post(webpath.ApiRenamePlayer, server.handleRenamePlayerPost)
+	app.Post(webpath.ApiMergePlayer, server.handleMergePlayerPost)
+	app.Post(webpath.ApiDeactivatePlayer, server.handleDeactivatePlayerPost)
 	app.Get(webpath.ApiNewPlayer, server.handleNewPlayerGet)
 	app.Post(webpath.ApiNewPlayer, server.handleNewPlayerPost)
+	app.Get(webpath.ApiPredict, server.handlePredict)
+	app.Get(webpath.ApiHeadToHead, server.handleHeadToHead)
+	app.Get(webpath.ApiMatchmaking, server.handleMatchmaking)
+	app.Get(webpath.ApiSimulate, server.handleSimulateGet)
+	app.Post(webpath.ApiSimulate, server.handleSimulatePost)
+	app.Get(webpath.ApiDisciplines, server.handleNewDisciplineGet)
+	app.Post(webpath.ApiDisciplines, server.handleNewDisciplinePost)
+	app.Get(webpath.ApiSeasons, server.handleSeasons)
+	app.Post(webpath.ApiSeasons, server.handleNewSeasonPost)
+	app.Get(webpath.ApiSeason, server.handleSeason)
+	app.Get(webpath.ApiTournaments, server.handleTournaments)
+	app.Post(webpath.ApiTournaments, server.handleNewTournamentPost)
+	app.Get(webpath.ApiTournament, server.handleTournament)
+	app.Post(webpath.ApiTournament, server.handleTournamentResultPost)
+	app.Get(webpath.ApiLadder, server.handleLadder)
+	app.Post(webpath.ApiLadder, server.handleLadderPost)
+	app.Post(webpath.ApiLadderChallenge, server.handleChallengePost)
 	server.app = app
 	return &server, nil
 }
@@ -97,93 +129,803 @@ func (s *Server) Serve() error {
 
 const userKey = "user"
 
+// admin returns the user of the request or fiber.ErrForbidden if they are not an admin.
+// The admin handlers check it themselves, as an old config may miss the rule.
+func admin(ctx *fiber.Ctx) (users.User, error) {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return users.User{}, errors.New("assertion failed")
+	}
+	if !isAdmin(user) {
+		return users.User{}, fiber.ErrForbidden
+	}
+	return user, nil
+}
+
+// disciplineKey is the query parameter and the cookie the current discipline is chosen by.
+const disciplineKey = "discipline"
+
+// discipline returns the discipline named in the query. It is remembered
+// in a cookie, so the other pages show the same discipline.
+func (s *Server) discipline(ctx *fiber.Ctx) (domain.Discipline, error) {
+	if name := ctx.Query(disciplineKey); name != "" {
+		discipline, err := s.playerService.Discipline(name)
+		if err != nil {
+			return domain.Discipline{}, err
+		}
+		ctx.Cookie(&fiber.Cookie{
+			Name:  disciplineKey,
+			Value: strconv.Itoa(discipline.ID),
+			Path:  webpath.Api,
+		})
+		return discipline, nil
+	}
+	disciplines := s.playerService.Disciplines()
+	id, err := strconv.Atoi(ctx.Cookies(disciplineKey))
+	if err != nil {
+		return disciplines[0], nil
+	}
+	for _, discipline := range disciplines {
+		if discipline.ID == id {
+			return discipline, nil
+		}
+	}
+	return disciplines[0], nil
+}
+
 func (s *Server) handleMain(ctx *fiber.Ctx) error {
 	user, ok := ctx.Context().UserValue(userKey).(users.User)
 	if !ok {
 		return errors.New("assertion failed")
 	}
-	globalRating := s.playerService.GetRatings()
-	return ctx.Render("index", newData("Рейтинг").
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	leaderboards := s.playerService.Leaderboards()
+	leaderboard := leaderboards[0]
+	for _, l := range leaderboards {
+		if l.Name == ctx.Query("rating") {
+			leaderboard = l
+		}
+	}
+	entries, err := s.playerService.Leaderboard(discipline.ID, leaderboard.Name)
+	if err != nil {
+		return err
+	}
+	var ranked, provisional []domain.LeaderboardEntry
+	for _, entry := range entries {
+		if entry.Rank == 0 {
+			provisional = append(provisional, entry)
+			continue
+		}
+		ranked = append(ranked, entry)
+	}
+	data := newData("Рейтинг").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "rating").
+		With("Systems", s.playerService.RatingSystems()).
+		With("Leaderboards", leaderboards).
+		With("Leaderboard", leaderboard).
+		With("Players", ranked).
+		With("Provisional", provisional)
+	if season, ok := s.playerService.CurrentSeason(); ok {
+		data = data.With("Season", season)
+	}
+	return ctx.Render("index", data, "layouts/main")
+}
+
+func (s *Server) handleMatches(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	matches, err := s.playerService.GetMatches(discipline.ID)
+	if err != nil {
+		return err
+	}
+	ffaMatches, err := s.playerService.GetFFAMatches(discipline.ID)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("matches",
+		newData("Список матчей").
+			WithUser(user).
+			WithDiscipline(discipline, s.playerService.Disciplines()).
+			With("Button", "matches").
+			With("Matches", matches).
+			With("FFAMatches", ffaMatches),
+		"layouts/main")
+}
+
+func (s *Server) handleCreateMatchGet(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("newMatch",
+		newData("Добавить игру").
+			WithUser(user).
+			WithDiscipline(discipline, s.playerService.Disciplines()),
+		"layouts/main")
+}
+
+func (s *Server) handleCreateFFAMatchGet(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("newFFAMatch",
+		newData("Добавить игру на несколько игроков").
+			WithUser(user).
+			WithDiscipline(discipline, s.playerService.Disciplines()),
+		"layouts/main")
+}
+
+func (s *Server) handleCreateFFAMatchPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	discipline, err := s.playerService.Discipline(ctx.FormValue(disciplineKey))
+	if err != nil {
+		return err
+	}
+	placements, err := s.playerService.PlacementsByNames(discipline.ID, strings.Split(ctx.FormValue("places"), ","))
+	if err != nil {
+		return err
+	}
+	date, err := parsePlayedAt(ctx.FormValue("date"))
+	if err != nil {
+		return err
+	}
+	_, err = s.playerService.CreateFFAMatch(domain.FFAMatch{
+		DisciplineID: discipline.ID,
+		Date:         date,
+		Placements:   placements,
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(webpath.ApiMatchesList)
+}
+
+// playersByNames finds the players listed in a comma separated string.
+func (s *Server) playersByNames(discipline int, names string) ([]domain.Player, error) {
+	var players []domain.Player
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		player, err := s.playerByName(discipline, name)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+func (s *Server) playerByName(discipline int, name string) (domain.Player, error) {
+	name = strings.TrimSpace(name)
+	player, err := s.playerService.GetByName(discipline, normalize.Name(name))
+	if err != nil {
+		return domain.Player{}, errors.New("игрок " + name + " не найден")
+	}
+	return player, nil
+}
+
+func (s *Server) handleCreateMatchPost(ctx *fiber.Ctx) error {
+	discipline, err := s.playerService.Discipline(ctx.FormValue(disciplineKey))
+	if err != nil {
+		return err
+	}
+	winner, err := s.playerService.GetByName(discipline.ID, normalize.Name(ctx.FormValue("winner")))
+	if err != nil {
+		return err
+	}
+	loser, err := s.playerService.GetByName(discipline.ID, normalize.Name(ctx.FormValue("loser")))
+	if err != nil {
+		return err
+	}
+	winnerTeam, err := s.playersByNames(discipline.ID, ctx.FormValue("winner_team"))
+	if err != nil {
+		return err
+	}
+	loserTeam, err := s.playersByNames(discipline.ID, ctx.FormValue("loser_team"))
+	if err != nil {
+		return err
+	}
+	date, err := parsePlayedAt(ctx.FormValue("date"))
+	if err != nil {
+		return err
+	}
+	m := domain.Match{
+		DisciplineID: discipline.ID,
+		PlayerA:      winner,
+		PlayerB:      loser,
+		TeammatesA:   winnerTeam,
+		TeammatesB:   loserTeam,
+		Winner:       winner,
+		Date:         date,
+	}
+	if ctx.FormValue("draw") == "on" {
+		m.Winner = domain.Player{}
+	}
+	if score := ctx.FormValue("score"); score != "" {
+		parsed, err := domain.ParseScore(score)
+		if err != nil {
+			return err
+		}
+		m.Score = &parsed
+	}
+	_, err = s.playerService.CreateMatch(m)
+	if err != nil {
+		return err
+	}
+	err = ctx.Redirect(webpath.ApiHome)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// matchForm is the match as it is shown in the edit form.
+type matchForm struct {
+	ID      int
+	PlayerA string
+	TeamA   string
+	PlayerB string
+	TeamB   string
+	// Result is "a", "b" or "draw".
+	Result string
+	Score  string
+	Date   string
+	Voided bool
+}
+
+// matchDateLayout is the format of the datetime-local inputs.
+const matchDateLayout = "2006-01-02T15:04"
+
+// parsePlayedAt reads the date of a match from the form,
+// the zero time is returned if it is not set.
+func parsePlayedAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(matchDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("неверная дата матча")
+	}
+	return date, nil
+}
+
+func newMatchForm(match domain.Match) matchForm {
+	form := matchForm{
+		ID:      match.ID,
+		PlayerA: match.PlayerA.Name,
+		TeamA:   joinNames(match.TeammatesA),
+		PlayerB: match.PlayerB.Name,
+		TeamB:   joinNames(match.TeammatesB),
+		Result:  "draw",
+		Date:    match.Date.Local().Format(matchDateLayout),
+		Voided:  match.Voided,
+	}
+	switch match.Winner.ID {
+	case match.PlayerA.ID:
+		form.Result = "a"
+	case match.PlayerB.ID:
+		form.Result = "b"
+	}
+	if match.Score != nil {
+		form.Score = match.Score.String()
+	}
+	return form
+}
+
+func joinNames(players []domain.Player) string {
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// matchID returns the match the ID in the path refers to.
+func (s *Server) matchID(ctx *fiber.Ctx) (domain.Match, error) {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return domain.Match{}, errors.New("неверный номер матча")
+	}
+	return s.playerService.GetMatch(id)
+}
+
+func (s *Server) handleEditMatchGet(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	match, err := s.matchID(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("editMatch",
+		newData("Изменить матч").
+			WithUser(user).
+			With("Button", "matches").
+			With("Match", newMatchForm(match)),
+		"layouts/main")
+}
+
+func (s *Server) handleEditMatchPost(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	old, err := s.matchID(ctx)
+	if err != nil {
+		return err
+	}
+	match, err := s.parseMatch(ctx, old)
+	if err == nil {
+		_, err = s.playerService.EditMatch(user.Name, match)
+	}
+	if err != nil {
+		form := matchForm{
+			ID:      old.ID,
+			PlayerA: ctx.FormValue("player_a"),
+			TeamA:   ctx.FormValue("team_a"),
+			PlayerB: ctx.FormValue("player_b"),
+			TeamB:   ctx.FormValue("team_b"),
+			Result:  ctx.FormValue("result"),
+			Score:   ctx.FormValue("score"),
+			Date:    ctx.FormValue("date"),
+			Voided:  old.Voided,
+		}
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("editMatch",
+			newData("Изменить матч").
+				WithUser(user).
+				With("Button", "matches").
+				With("Match", form).
+				WithErrors(err),
+			"layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiMatchesList)
+}
+
+// parseMatch reads the edited match from the form, the discipline of the old one is kept.
+func (s *Server) parseMatch(ctx *fiber.Ctx, old domain.Match) (domain.Match, error) {
+	playerA, err := s.playerService.GetByName(old.DisciplineID, normalize.Name(ctx.FormValue("player_a")))
+	if err != nil {
+		return domain.Match{}, errors.New("игрок " + ctx.FormValue("player_a") + " не найден")
+	}
+	playerB, err := s.playerService.GetByName(old.DisciplineID, normalize.Name(ctx.FormValue("player_b")))
+	if err != nil {
+		return domain.Match{}, errors.New("игрок " + ctx.FormValue("player_b") + " не найден")
+	}
+	teamA, err := s.playersByNames(old.DisciplineID, ctx.FormValue("team_a"))
+	if err != nil {
+		return domain.Match{}, err
+	}
+	teamB, err := s.playersByNames(old.DisciplineID, ctx.FormValue("team_b"))
+	if err != nil {
+		return domain.Match{}, err
+	}
+	date, err := parsePlayedAt(ctx.FormValue("date"))
+	if err != nil {
+		return domain.Match{}, err
+	}
+	match := domain.Match{
+		ID:           old.ID,
+		DisciplineID: old.DisciplineID,
+		PlayerA:      playerA,
+		PlayerB:      playerB,
+		TeammatesA:   teamA,
+		TeammatesB:   teamB,
+		Date:         date,
+	}
+	switch ctx.FormValue("result") {
+	case "a":
+		match.Winner = playerA
+	case "b":
+		match.Winner = playerB
+	case "draw":
+	default:
+		return domain.Match{}, errors.New("неверный результат матча")
+	}
+	if score := ctx.FormValue("score"); score != "" {
+		parsed, err := domain.ParseScore(score)
+		if err != nil {
+			return domain.Match{}, err
+		}
+		match.Score = &parsed
+	}
+	return match, nil
+}
+
+func (s *Server) handleVoidMatchPost(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	match, err := s.matchID(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.playerService.VoidMatch(user.Name, match.ID, ctx.FormValue("voided") == "on")
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(webpath.ApiMatchesList)
+}
+
+func (s *Server) handleDeleteMatchPost(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	match, err := s.matchID(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.playerService.DeleteMatch(user.Name, match.ID)
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(webpath.ApiMatchesList)
+}
+
+func (s *Server) handleAudit(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := s.playerService.GetMatchChanges()
+	if err != nil {
+		return err
+	}
+	return ctx.Render("audit",
+		newData("Журнал изменений").
+			WithUser(user).
+			With("Button", "audit").
+			With("Changes", changes),
+		"layouts/main")
+}
+
+func (s *Server) handlePlayerInfo(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	return s.renderPlayerCard(ctx, id, nil)
+}
+
+// handlePlayerChart sends the rating chart of the player in the current discipline as an image.
+func (s *Server) handlePlayerChart(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	player, err := s.playerService.Get(discipline.ID, id)
+	if err != nil {
+		return err
+	}
+	series, err := s.playerService.RatingChart(discipline.ID, id)
+	if err != nil {
+		return err
+	}
+	image := chart.Rating(player.Name+", "+discipline.Name, series)
+	if image == nil {
+		return fiber.ErrNotFound
+	}
+	ctx.Set(fiber.HeaderContentType, chart.ContentType)
+	return ctx.Send(image)
+}
+
+// renderPlayerCard shows the player with the errors of the admin forms.
+func (s *Server) renderPlayerCard(ctx *fiber.Ctx, id uuid.UUID, formErr error) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	card, err := s.playerService.GetPlayerData(discipline.ID, id)
+	if err != nil {
+		return err
+	}
+	data := newData(card.Player.Name).
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("PlayerCard", card).
+		With("Systems", s.playerService.RatingSystems()).
+		With("Button", "playerCard")
+	if formErr != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		data = data.WithErrors(formErr)
+	}
+	return ctx.Render("playerCard", data, "layouts/main")
+}
+
+func (s *Server) handleRenamePlayerPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	_, err = s.playerService.RenamePlayer(id, ctx.FormValue("name"))
+	if err != nil {
+		return s.renderPlayerCard(ctx, id, err)
+	}
+	return ctx.Redirect(webpath.Api + "/players/" + id.String())
+}
+
+func (s *Server) handleMergePlayerPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	name := ctx.FormValue("into")
+	into, err := s.playerService.GetByName(domain.DefaultDisciplineID, normalize.Name(name))
+	if err != nil {
+		return s.renderPlayerCard(ctx, id, errors.New("игрок "+name+" не найден"))
+	}
+	err = s.playerService.MergePlayers(id, into.ID)
+	if err != nil {
+		return s.renderPlayerCard(ctx, id, err)
+	}
+	return ctx.Redirect(webpath.Api + "/players/" + into.ID.String())
+}
+
+func (s *Server) handleDeactivatePlayerPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	err = s.playerService.DeactivatePlayer(id, ctx.FormValue("deactivated") == "on")
+	if err != nil {
+		return s.renderPlayerCard(ctx, id, err)
+	}
+	return ctx.Redirect(webpath.Api + "/players/" + id.String())
+}
+
+func (s *Server) handlePredict(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	data := newData("Прогноз").
 		WithUser(user).
-		With("Button", "rating").
-		With("Players", globalRating),
-		"layouts/main",
-	)
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "predict").
+		With("A", ctx.Query("a")).
+		With("B", ctx.Query("b"))
+	if ctx.Query("a") == "" || ctx.Query("b") == "" {
+		return ctx.Render("predict", data, "layouts/main")
+	}
+	prediction, err := s.predict(discipline.ID, ctx.Query("a"), ctx.Query("b"))
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("predict", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Render("predict", data.With("Prediction", prediction), "layouts/main")
 }
 
-func (s *Server) handleMatches(ctx *fiber.Ctx) error {
+func (s *Server) handleMatchmaking(ctx *fiber.Ctx) error {
 	user, ok := ctx.Context().UserValue(userKey).(users.User)
 	if !ok {
 		return errors.New("assertion failed")
 	}
-	matches, err := s.playerService.GetMatches()
+	discipline, err := s.discipline(ctx)
 	if err != nil {
 		return err
 	}
-	return ctx.Render("matches",
-		newData("Список матчей").
-			WithUser(user).
-			With("Button", "matches").
-			With("Matches", matches),
-		"layouts/main")
+	data := newData("Подбор пар").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "matchmaking").
+		With("Players", ctx.Query("players"))
+	if strings.TrimSpace(ctx.Query("players")) == "" {
+		return ctx.Render("matchmaking", data, "layouts/main")
+	}
+	matchmaking, err := s.matchmaking(discipline.ID, ctx.Query("players"))
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("matchmaking", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Render("matchmaking", data.With("Matchmaking", matchmaking), "layouts/main")
 }
 
-func (s *Server) handleCreateMatchGet(ctx *fiber.Ctx) error {
+// matchmaking proposes the games between the players separated by commas or new lines.
+func (s *Server) matchmaking(discipline int, names string) (domain.Matchmaking, error) {
+	players, err := s.playersByNames(discipline, strings.ReplaceAll(names, "\n", ","))
+	if err != nil {
+		return domain.Matchmaking{}, err
+	}
+	ids := make([]uuid.UUID, 0, len(players))
+	for _, player := range players {
+		ids = append(ids, player.ID)
+	}
+	return s.playerService.Matchmaking(discipline, ids)
+}
+
+func (s *Server) handleHeadToHead(ctx *fiber.Ctx) error {
 	user, ok := ctx.Context().UserValue(userKey).(users.User)
 	if !ok {
 		return errors.New("assertion failed")
 	}
-	return ctx.Render("newMatch", newData("Добавить игру").WithUser(user), "layouts/main")
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	data := newData("Сравнение").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "h2h").
+		With("A", ctx.Query("a")).
+		With("B", ctx.Query("b"))
+	if ctx.Query("a") == "" || ctx.Query("b") == "" {
+		return ctx.Render("h2h", data, "layouts/main")
+	}
+	h2h, err := s.headToHead(discipline.ID, ctx.Query("a"), ctx.Query("b"))
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("h2h", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Render("h2h", data.With("HeadToHead", h2h), "layouts/main")
 }
 
-func (s *Server) handleCreateMatchPost(ctx *fiber.Ctx) error {
-	winner, err := s.playerService.GetByName(normalize.Name(ctx.FormValue("winner")))
+func (s *Server) handleSimulateGet(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
 	if err != nil {
 		return err
 	}
-	loser, err := s.playerService.GetByName(normalize.Name(ctx.FormValue("loser")))
+	data, _, err := s.simulateData(ctx)
 	if err != nil {
 		return err
 	}
-	m := domain.Match{
-		PlayerA: winner,
-		PlayerB: loser,
-		Winner:  winner,
-	}
-	if ctx.FormValue("draw") == "on" {
-		m.Winner = domain.Player{}
-	}
-	_, err = s.playerService.CreateMatch(m)
+	return ctx.Render("simulate", data, "layouts/main")
+}
+
+func (s *Server) handleSimulatePost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
 	if err != nil {
 		return err
 	}
-	err = ctx.Redirect(webpath.ApiHome)
+	data, discipline, err := s.simulateData(ctx)
 	if err != nil {
 		return err
 	}
-	return nil
+	results := ctx.FormValue("results")
+	data = data.With("Results", results)
+	matches, err := s.parseSimulation(discipline.ID, results)
+	if err == nil {
+		var simulation domain.Simulation
+		simulation, err = s.playerService.Simulate(discipline.ID, matches)
+		data = data.With("Simulation", simulation)
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		data = data.WithErrors(err)
+	}
+	return ctx.Render("simulate", data, "layouts/main")
 }
 
-func (s *Server) handlePlayerInfo(ctx *fiber.Ctx) error {
+// simulateData returns the data of the simulator page and the discipline the matches are simulated in.
+func (s *Server) simulateData(ctx *fiber.Ctx) (data, domain.Discipline, error) {
 	user, ok := ctx.Context().UserValue(userKey).(users.User)
 	if !ok {
-		return errors.New("assertion failed")
+		return data{}, domain.Discipline{}, errors.New("assertion failed")
 	}
-	strID := ctx.Params("id")
-	id, err := uuid.Parse(strID)
+	discipline, err := s.discipline(ctx)
 	if err != nil {
-		return err
+		return data{}, domain.Discipline{}, err
+	}
+	d := newData("Что если").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Systems", s.playerService.RatingSystems()).
+		With("Button", "simulate")
+	return d, discipline, nil
+}
+
+// parseSimulation reads the hypothetical matches, one per line: the winner and the loser,
+// or both sides and "ничья" for a draw. The players of a team are joined by "+".
+func (s *Server) parseSimulation(discipline int, results string) ([]domain.Match, error) {
+	var matches []domain.Match
+	for _, line := range strings.Split(results, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 3 || len(fields) < 2 || len(fields) == 3 && normalize.Name(fields[2]) != "ничья" {
+			return nil, errors.New(`неверная строка "` + strings.TrimSpace(line) + `", нужно "победитель проигравший" или "игрок1 игрок2 ничья"`)
+		}
+		sideA, err := s.playersByNames(discipline, strings.ReplaceAll(fields[0], "+", ","))
+		if err != nil {
+			return nil, err
+		}
+		sideB, err := s.playersByNames(discipline, strings.ReplaceAll(fields[1], "+", ","))
+		if err != nil {
+			return nil, err
+		}
+		if len(sideA) == 0 || len(sideB) == 0 {
+			return nil, errors.New(`неверная строка "` + strings.TrimSpace(line) + `"`)
+		}
+		match := domain.Match{
+			DisciplineID: discipline,
+			PlayerA:      sideA[0],
+			PlayerB:      sideB[0],
+			TeammatesA:   sideA[1:],
+			TeammatesB:   sideB[1:],
+			Winner:       sideA[0],
+		}
+		if len(fields) == 3 {
+			match.Winner = domain.Player{}
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+func (s *Server) headToHead(discipline int, nameA, nameB string) (domain.HeadToHead, error) {
+	playerA, err := s.playerService.GetByName(discipline, normalize.Name(nameA))
+	if err != nil {
+		return domain.HeadToHead{}, errors.New("игрок " + nameA + " не найден")
 	}
-	card, err := s.playerService.GetPlayerData(id)
+	playerB, err := s.playerService.GetByName(discipline, normalize.Name(nameB))
 	if err != nil {
-		return err
+		return domain.HeadToHead{}, errors.New("игрок " + nameB + " не найден")
 	}
-	return ctx.Render("playerCard",
-		newData(card.Player.Name).
-			WithUser(user).
-			With("PlayerCard", card).
-			With("Button", "playerCard"),
-		"layouts/main")
+	return s.playerService.HeadToHead(discipline, playerA.ID, playerB.ID)
+}
+
+func (s *Server) predict(discipline int, nameA, nameB string) (domain.Prediction, error) {
+	playerA, err := s.playerService.GetByName(discipline, normalize.Name(nameA))
+	if err != nil {
+		return domain.Prediction{}, errors.New("игрок " + nameA + " не найден")
+	}
+	playerB, err := s.playerService.GetByName(discipline, normalize.Name(nameB))
+	if err != nil {
+		return domain.Prediction{}, errors.New("игрок " + nameB + " не найден")
+	}
+	return s.playerService.Predict(discipline, playerA.ID, playerB.ID)
 }
 
 func (s *Server) handleGetSignIn(ctx *fiber.Ctx) error {
@@ -267,6 +1009,398 @@ func (s *Server) handleNewPlayerPost(ctx *fiber.Ctx) error {
 	return ctx.Redirect(webpath.ApiHome)
 }
 
+func (s *Server) handleNewDisciplineGet(ctx *fiber.Ctx) error {
+	return ctx.Render("newDiscipline", newData("Добавить дисциплину"), "layouts/main")
+}
+
+func (s *Server) handleNewDisciplinePost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	discipline, err := s.playerService.CreateDiscipline(ctx.FormValue("name"))
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(webpath.ApiHome + "?" + disciplineKey + "=" + url.QueryEscape(discipline.Name))
+}
+
+func (s *Server) handleSeasons(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	return ctx.Render("seasons", s.seasonsData(user), "layouts/main")
+}
+
+func (s *Server) seasonsData(user users.User) data {
+	current, _ := s.playerService.CurrentSeason()
+	return newData("Сезоны").
+		WithUser(user).
+		With("Button", "seasons").
+		With("Seasons", s.playerService.Seasons()).
+		With("Current", current)
+}
+
+// seasonDateLayout is the format of the date inputs.
+const seasonDateLayout = "2006-01-02"
+
+func (s *Server) handleNewSeasonPost(ctx *fiber.Ctx) error {
+	user, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	season, err := parseSeason(ctx)
+	if err == nil {
+		season, err = s.playerService.CreateSeason(season)
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("seasons", s.seasonsData(user).WithErrors(err), "layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiSeasons + "/" + strconv.Itoa(season.ID))
+}
+
+// parseSeason reads the season from the form, the end date is inclusive
+// and the carryover is given in percent.
+func parseSeason(ctx *fiber.Ctx) (domain.Season, error) {
+	start, err := time.ParseInLocation(seasonDateLayout, ctx.FormValue("start"), time.Local)
+	if err != nil {
+		return domain.Season{}, errors.New("неверная дата начала сезона")
+	}
+	end, err := time.ParseInLocation(seasonDateLayout, ctx.FormValue("end"), time.Local)
+	if err != nil {
+		return domain.Season{}, errors.New("неверная дата окончания сезона")
+	}
+	var carryover float64
+	if v := ctx.FormValue("carryover"); v != "" {
+		carryover, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return domain.Season{}, errors.New("неверная доля сохраняемого рейтинга")
+		}
+	}
+	return domain.Season{
+		Name:      ctx.FormValue("name"),
+		Start:     start,
+		End:       end.AddDate(0, 0, 1),
+		Carryover: carryover / 100,
+	}, nil
+}
+
+func (s *Server) handleSeason(ctx *fiber.Ctx) error {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return errors.New("assertion failed")
+	}
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	season, err := s.playerService.Season(id)
+	if err != nil {
+		return err
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return err
+	}
+	standings, err := s.playerService.GetSeasonStandings(discipline.ID, season.ID)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("season",
+		newData(season.Name).
+			WithUser(user).
+			WithDiscipline(discipline, s.playerService.Disciplines()).
+			With("Button", "seasons").
+			With("Season", season).
+			With("Systems", s.playerService.RatingSystems()).
+			With("Standings", standings),
+		"layouts/main")
+}
+
+func (s *Server) handleTournaments(ctx *fiber.Ctx) error {
+	data, _, err := s.tournamentsData(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("tournaments", data, "layouts/main")
+}
+
+// tournamentsData returns the data of the page listing the tournaments of the current discipline.
+func (s *Server) tournamentsData(ctx *fiber.Ctx) (data, domain.Discipline, error) {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return data{}, domain.Discipline{}, errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return data{}, domain.Discipline{}, err
+	}
+	all, err := s.playerService.Tournaments()
+	if err != nil {
+		return data{}, domain.Discipline{}, err
+	}
+	var tournaments []domain.Tournament
+	for _, tournament := range all {
+		if tournament.DisciplineID == discipline.ID {
+			tournaments = append(tournaments, tournament)
+		}
+	}
+	d := newData("Турниры").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "tournaments").
+		With("Tournaments", tournaments).
+		With("Formats", domain.TournamentFormats)
+	return d, discipline, nil
+}
+
+func (s *Server) handleNewTournamentPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	data, discipline, err := s.tournamentsData(ctx)
+	if err != nil {
+		return err
+	}
+	tournament, err := s.parseTournament(ctx, discipline.ID)
+	if err == nil {
+		tournament, err = s.playerService.CreateTournament(tournament)
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("tournaments", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiTournaments + "/" + strconv.Itoa(tournament.ID))
+}
+
+// parseTournament reads the tournament from the form, the players are separated by commas or new lines
+// and the number of the rounds may be omitted.
+func (s *Server) parseTournament(ctx *fiber.Ctx, discipline int) (domain.Tournament, error) {
+	players, err := s.playersByNames(discipline, strings.ReplaceAll(ctx.FormValue("players"), "\n", ","))
+	if err != nil {
+		return domain.Tournament{}, err
+	}
+	var rounds int
+	if v := strings.TrimSpace(ctx.FormValue("rounds")); v != "" {
+		rounds, err = strconv.Atoi(v)
+		if err != nil {
+			return domain.Tournament{}, errors.New("неверное число туров")
+		}
+	}
+	return domain.Tournament{
+		Name:         ctx.FormValue("name"),
+		DisciplineID: discipline,
+		Format:       domain.TournamentFormat(ctx.FormValue("format")),
+		Rounds:       rounds,
+		Players:      players,
+	}, nil
+}
+
+func (s *Server) handleTournament(ctx *fiber.Ctx) error {
+	data, _, err := s.tournamentData(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("tournament", data, "layouts/main")
+}
+
+// tournamentData returns the data of the tournament page, the discipline is the one of the tournament.
+func (s *Server) tournamentData(ctx *fiber.Ctx) (data, domain.Tournament, error) {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return data{}, domain.Tournament{}, errors.New("assertion failed")
+	}
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return data{}, domain.Tournament{}, err
+	}
+	tournament, err := s.playerService.Tournament(id)
+	if err != nil {
+		return data{}, domain.Tournament{}, err
+	}
+	disciplines := s.playerService.Disciplines()
+	var discipline domain.Discipline
+	for _, d := range disciplines {
+		if d.ID == tournament.DisciplineID {
+			discipline = d
+		}
+	}
+	d := newData(tournament.Name).
+		WithUser(user).
+		WithDiscipline(discipline, disciplines).
+		With("Button", "tournaments").
+		With("Tournament", tournament)
+	if champion, ok := tournament.Champion(); ok {
+		d = d.With("Champion", champion)
+	}
+	return d, tournament, nil
+}
+
+func (s *Server) handleTournamentResultPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	data, tournament, err := s.tournamentData(ctx)
+	if err != nil {
+		return err
+	}
+	match, err := parseTournamentResult(ctx, tournament)
+	if err == nil {
+		_, err = s.playerService.RecordTournamentMatch(tournament.ID, match)
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("tournament", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiTournaments + "/" + strconv.Itoa(tournament.ID))
+}
+
+// parseTournamentResult reads the result of an unplayed game: the IDs of its players
+// and the ID of the winner or "draw".
+func parseTournamentResult(ctx *fiber.Ctx, tournament domain.Tournament) (domain.Match, error) {
+	a, errA := uuid.Parse(ctx.FormValue("a"))
+	b, errB := uuid.Parse(ctx.FormValue("b"))
+	if errA != nil || errB != nil {
+		return domain.Match{}, errors.New("игра не найдена")
+	}
+	for _, game := range tournament.Pending() {
+		if !game.Has(a, b) {
+			continue
+		}
+		match := domain.Match{PlayerA: game.PlayerA, PlayerB: game.PlayerB}
+		switch ctx.FormValue("winner") {
+		case "draw":
+		case game.PlayerA.ID.String():
+			match.Winner = game.PlayerA
+		case game.PlayerB.ID.String():
+			match.Winner = game.PlayerB
+		default:
+			return domain.Match{}, errors.New("неверный результат")
+		}
+		return match, nil
+	}
+	return domain.Match{}, errors.New("игра не найдена")
+}
+
+func (s *Server) handleLadder(ctx *fiber.Ctx) error {
+	data, _, err := s.ladderData(ctx)
+	if err != nil {
+		return err
+	}
+	return ctx.Render("ladder", data, "layouts/main")
+}
+
+// ladderData returns the data of the ladder page of the current discipline, without the ladder if it is disabled.
+func (s *Server) ladderData(ctx *fiber.Ctx) (data, domain.Discipline, error) {
+	user, ok := ctx.Context().UserValue(userKey).(users.User)
+	if !ok {
+		return data{}, domain.Discipline{}, errors.New("assertion failed")
+	}
+	discipline, err := s.discipline(ctx)
+	if err != nil {
+		return data{}, domain.Discipline{}, err
+	}
+	d := newData("Лестница").
+		WithUser(user).
+		WithDiscipline(discipline, s.playerService.Disciplines()).
+		With("Button", "ladder")
+	if !s.playerService.LadderEnabled() {
+		return d, discipline, nil
+	}
+	ladder, err := s.playerService.Ladder(discipline.ID)
+	if err != nil {
+		return data{}, domain.Discipline{}, err
+	}
+	return d.With("Ladder", ladder), discipline, nil
+}
+
+// handleLadderPost puts a player on the ladder or saves a challenge, depending on the action of the form.
+func (s *Server) handleLadderPost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	data, discipline, err := s.ladderData(ctx)
+	if err != nil {
+		return err
+	}
+	switch ctx.FormValue("action") {
+	case "join":
+		var player domain.Player
+		player, err = s.playerByName(discipline.ID, ctx.FormValue("player"))
+		if err == nil {
+			err = s.playerService.JoinLadder(discipline.ID, player.ID)
+		}
+	case "challenge":
+		var challenger, challenged domain.Player
+		challenger, err = s.playerByName(discipline.ID, ctx.FormValue("challenger"))
+		if err == nil {
+			challenged, err = s.playerByName(discipline.ID, ctx.FormValue("challenged"))
+		}
+		if err == nil {
+			_, err = s.playerService.Challenge(discipline.ID, challenger.ID, challenged.ID)
+		}
+	default:
+		err = errors.New("неизвестное действие")
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("ladder", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiLadder)
+}
+
+// handleChallengePost accepts, cancels or saves the result of the challenge.
+func (s *Server) handleChallengePost(ctx *fiber.Ctx) error {
+	_, err := admin(ctx)
+	if err != nil {
+		return err
+	}
+	data, _, err := s.ladderData(ctx)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+	switch ctx.FormValue("action") {
+	case "accept":
+		_, err = s.playerService.AcceptChallenge(id)
+	case "cancel":
+		_, err = s.playerService.CancelChallenge(id)
+	case "result":
+		var challenge domain.LadderChallenge
+		challenge, err = s.playerService.LadderChallenge(id)
+		if err == nil {
+			match := domain.Match{PlayerA: challenge.Challenger, PlayerB: challenge.Challenged}
+			switch ctx.FormValue("winner") {
+			case "draw":
+			case challenge.Challenger.ID.String():
+				match.Winner = challenge.Challenger
+			case challenge.Challenged.ID.String():
+				match.Winner = challenge.Challenged
+			default:
+				err = errors.New("неверный результат")
+			}
+			if err == nil {
+				_, err = s.playerService.RecordLadderMatch(id, match)
+			}
+		}
+	default:
+		err = errors.New("неизвестное действие")
+	}
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest)
+		return ctx.Render("ladder", data.WithErrors(err), "layouts/main")
+	}
+	return ctx.Redirect(webpath.ApiLadder)
+}
+
 func formatDate(t time.Time) string {
 	return t.Format("02.01.2006г.")
 }
@@ -6,24 +6,54 @@ const (
 	Signout = "/signout"
 	Home    = "/"
 
-	Api            = "/api"
-	ApiHome        = Api + Home
-	ApiMatchesList = Api + "/matches-list"
-	ApiNewMatch    = Api + "/matches"
-	ApiGetPlayers  = Api + "/players/:id"
-	ApiNewPlayer   = Api + "/players"
+	Api                 = "/api"
+	ApiHome             = Api + Home
+	ApiMatchesList      = Api + "/matches-list"
+	ApiNewMatch         = Api + "/matches"
+	ApiNewFFAMatch      = Api + "/ffa-matches"
+	ApiMatch            = Api + "/matches/:id"
+	ApiVoidMatch        = ApiMatch + "/void"
+	ApiDeleteMatch      = ApiMatch + "/delete"
+	ApiAudit            = Api + "/audit"
+	ApiGetPlayers       = Api + "/players/:id"
+	ApiRenamePlayer     = ApiGetPlayers + "/rename"
+	ApiMergePlayer      = ApiGetPlayers + "/merge"
+	ApiDeactivatePlayer = ApiGetPlayers + "/deactivate"
+	ApiPlayerChart      = ApiGetPlayers + "/chart.svg"
+	ApiNewPlayer        = Api + "/players"
+	ApiPredict          = Api + "/predict"
+	ApiHeadToHead       = Api + "/h2h"
+	ApiMatchmaking      = Api + "/matchmaking"
+	ApiSimulate         = Api + "/simulate"
+	ApiDisciplines      = Api + "/disciplines"
+	ApiSeasons          = Api + "/seasons"
+	ApiSeason           = Api + "/seasons/:id"
+	ApiTournaments      = Api + "/tournaments"
+	ApiTournament       = ApiTournaments + "/:id"
+	ApiLadder           = Api + "/ladder"
+	ApiLadderChallenge  = ApiLadder + "/challenges/:id"
 )
 
 func Path() map[string]string {
 	return map[string]string{
-		"SignUp":       Signup,
-		"SignIn":       Signin,
-		"SignOut":      Signout,
-		"Home":         Home,
-		"Api":          Api,
-		"ApiHome":      ApiHome,
-		"ApiNewMatch":  ApiNewMatch,
-		"ApiMatches":   ApiMatchesList,
-		"ApiNewPlayer": ApiNewPlayer,
+		"SignUp":         Signup,
+		"SignIn":         Signin,
+		"SignOut":        Signout,
+		"Home":           Home,
+		"Api":            Api,
+		"ApiHome":        ApiHome,
+		"ApiNewMatch":    ApiNewMatch,
+		"ApiNewFFA":      ApiNewFFAMatch,
+		"ApiMatches":     ApiMatchesList,
+		"ApiNewPlayer":   ApiNewPlayer,
+		"ApiPredict":     ApiPredict,
+		"ApiHeadToHead":  ApiHeadToHead,
+		"ApiMatchmaking": ApiMatchmaking,
+		"ApiSimulate":    ApiSimulate,
+		"ApiDisciplines": ApiDisciplines,
+		"ApiSeasons":     ApiSeasons,
+		"ApiTournaments": ApiTournaments,
+		"ApiLadder":      ApiLadder,
+		"ApiAudit":       ApiAudit,
 	}
 }
@@ -0,0 +1,2 @@
+drop index if exists rating_history_player_id_system_played_at_index;
+drop table if exists rating_history;
@@ -0,0 +1,2 @@
+alter table matches drop column score_b;
+alter table matches drop column score_a;
@@ -0,0 +1,2 @@
+alter table matches add column score_a integer;
+alter table matches add column score_b integer;
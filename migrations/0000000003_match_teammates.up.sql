@@ -0,0 +1,15 @@
+create table if not exists match_teammates
+(
+    match_id  integer not null
+        constraint match_teammates_matches_id_fk
+            references matches
+            on delete cascade,
+    player_id text    not null
+        constraint match_teammates_players_id_fk
+            references players
+            on delete cascade,
+    side      text    not null,
+    position  integer not null,
+    constraint match_teammates_pk
+        primary key (match_id, player_id)
+);
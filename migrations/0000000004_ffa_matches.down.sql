@@ -0,0 +1,33 @@
+create table rating_history_old
+(
+    match_id         integer   not null
+        constraint rating_history_matches_id_fk
+            references matches
+            on delete cascade,
+    player_id        text      not null
+        constraint rating_history_players_id_fk
+            references players
+            on delete cascade,
+    system           text      not null,
+    rating_before    double    not null,
+    rating_after     double    not null,
+    deviation_before double    not null,
+    deviation_after  double    not null,
+    played_at        timestamp not null,
+    constraint rating_history_pk
+        primary key (match_id, player_id, system)
+);
+
+insert into rating_history_old
+select match_id, player_id, system, rating_before, rating_after, deviation_before, deviation_after, played_at
+from rating_history
+where match_id is not null;
+
+drop table rating_history;
+alter table rating_history_old rename to rating_history;
+
+create index if not exists rating_history_player_id_system_played_at_index
+    on rating_history (player_id, system, played_at);
+
+drop table if exists ffa_placements;
+drop table if exists ffa_matches;
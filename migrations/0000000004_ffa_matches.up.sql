@@ -0,0 +1,62 @@
+create table if not exists ffa_matches
+(
+    id         integer   not null
+        constraint ffa_matches_pk
+            primary key autoincrement,
+    created_at timestamp default CURRENT_TIMESTAMP not null
+);
+
+create table if not exists ffa_placements
+(
+    match_id  integer not null
+        constraint ffa_placements_ffa_matches_id_fk
+            references ffa_matches
+            on delete cascade,
+    player_id text    not null
+        constraint ffa_placements_players_id_fk
+            references players
+            on delete cascade,
+    place     integer not null,
+    position  integer not null,
+    constraint ffa_placements_pk
+        primary key (match_id, player_id)
+);
+
+-- rating history entries belong either to a match or to a free-for-all match
+create table rating_history_new
+(
+    id               integer   not null
+        constraint rating_history_pk
+            primary key autoincrement,
+    match_id         integer
+        constraint rating_history_matches_id_fk
+            references matches
+            on delete cascade,
+    ffa_match_id     integer
+        constraint rating_history_ffa_matches_id_fk
+            references ffa_matches
+            on delete cascade,
+    player_id        text      not null
+        constraint rating_history_players_id_fk
+            references players
+            on delete cascade,
+    system           text      not null,
+    rating_before    double    not null,
+    rating_after     double    not null,
+    deviation_before double    not null,
+    deviation_after  double    not null,
+    played_at        timestamp not null,
+    constraint rating_history_match_check
+        check ((match_id is null) != (ffa_match_id is null))
+);
+
+insert into rating_history_new (match_id, player_id, system, rating_before, rating_after,
+                                deviation_before, deviation_after, played_at)
+select match_id, player_id, system, rating_before, rating_after, deviation_before, deviation_after, played_at
+from rating_history;
+
+drop table rating_history;
+alter table rating_history_new rename to rating_history;
+
+create index if not exists rating_history_player_id_system_played_at_index
+    on rating_history (player_id, system, played_at);
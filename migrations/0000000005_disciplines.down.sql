@@ -0,0 +1,12 @@
+drop index if exists ffa_matches_discipline_id_index;
+drop index if exists matches_discipline_id_index;
+
+delete from rating_history where discipline_id != 1;
+delete from ffa_matches where discipline_id != 1;
+delete from matches where discipline_id != 1;
+
+alter table rating_history drop column discipline_id;
+alter table ffa_matches drop column discipline_id;
+alter table matches drop column discipline_id;
+
+drop table if exists disciplines;
@@ -0,0 +1,21 @@
+create table if not exists disciplines
+(
+    id         integer not null
+        constraint disciplines_pk
+            primary key autoincrement,
+    name       text    not null
+        unique,
+    created_at timestamp default CURRENT_TIMESTAMP not null
+);
+
+-- the matches recorded before disciplines were introduced belong to the default one
+insert into disciplines (id, name) values (1, 'общий');
+
+alter table matches add column discipline_id integer not null default 1;
+alter table ffa_matches add column discipline_id integer not null default 1;
+alter table rating_history add column discipline_id integer not null default 1;
+
+create index if not exists matches_discipline_id_index
+    on matches (discipline_id);
+create index if not exists ffa_matches_discipline_id_index
+    on ffa_matches (discipline_id);
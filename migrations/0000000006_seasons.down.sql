@@ -0,0 +1,2 @@
+drop table if exists season_standings;
+drop table if exists seasons;
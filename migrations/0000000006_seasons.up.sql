@@ -0,0 +1,32 @@
+create table if not exists seasons
+(
+    id          integer not null
+        constraint seasons_pk
+            primary key autoincrement,
+    name        text    not null
+        unique,
+    start_at    timestamp not null,
+    end_at      timestamp not null,
+    -- the share of the all-time rating advantage kept at the start, 0 is a full reset
+    carryover   double  not null default 0,
+    archived_at timestamp,
+    created_at  timestamp default CURRENT_TIMESTAMP not null
+);
+
+-- the final standings of the finished seasons, a row for every rating system
+create table if not exists season_standings
+(
+    season_id     integer not null,
+    discipline_id integer not null,
+    player_id     text    not null,
+    place         integer not null,
+    games_played  integer not null,
+    system        text    not null,
+    rating        double  not null,
+    deviation     double  not null,
+    interval_min  double  not null,
+    interval_max  double  not null,
+    precision     integer not null,
+    constraint season_standings_pk
+        primary key (season_id, discipline_id, player_id, system)
+);
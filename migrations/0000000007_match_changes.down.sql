@@ -0,0 +1,4 @@
+drop index if exists match_changes_match_id_index;
+drop table if exists match_changes;
+
+alter table matches drop column voided;
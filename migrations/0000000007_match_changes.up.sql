@@ -0,0 +1,19 @@
+-- void matches stay in the list, but do not change the ratings
+alter table matches add column voided boolean not null default false;
+
+create table if not exists match_changes
+(
+    id         integer not null
+        constraint match_changes_pk
+            primary key autoincrement,
+    match_id   integer not null,
+    action     text    not null,
+    author     text    not null,
+    -- the match before and after the change as shown to users
+    old_value  text    not null default '',
+    new_value  text    not null default '',
+    created_at timestamp not null
+);
+
+create index if not exists match_changes_match_id_index
+    on match_changes (match_id);
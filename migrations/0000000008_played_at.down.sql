@@ -0,0 +1,2 @@
+alter table ffa_matches drop column played_at;
+alter table matches drop column played_at;
@@ -0,0 +1,25 @@
+-- created_at is kept as the time the match was recorded
+alter table matches add column played_at timestamp not null default '0001-01-01 00:00:00+00:00';
+alter table ffa_matches add column played_at timestamp not null default '0001-01-01 00:00:00+00:00';
+
+update matches set played_at = created_at;
+update ffa_matches set played_at = created_at;
+
+-- the matches added from the web form were saved without a date,
+-- they take the date of the neighbouring match to keep their place in the history
+update matches
+set played_at = coalesce(
+        (select p.created_at
+         from matches p
+         where p.id < matches.id
+           and p.created_at > '0001-01-01 00:00:00+00:00'
+         order by p.id desc
+         limit 1),
+        (select n.created_at
+         from matches n
+         where n.id > matches.id
+           and n.created_at > '0001-01-01 00:00:00+00:00'
+         order by n.id
+         limit 1),
+        played_at)
+where created_at <= '0001-01-01 00:00:00+00:00';
@@ -0,0 +1 @@
+alter table players drop column deactivated;
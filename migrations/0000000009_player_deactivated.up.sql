@@ -0,0 +1,2 @@
+-- deactivated players are hidden from the leaderboards, their matches are kept
+alter table players add column deactivated boolean not null default false;
@@ -0,0 +1,3 @@
+drop table if exists tournament_games;
+drop table if exists tournament_players;
+drop table if exists tournaments;
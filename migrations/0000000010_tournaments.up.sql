@@ -0,0 +1,40 @@
+create table if not exists tournaments
+(
+    id            integer not null
+        constraint tournaments_pk
+            primary key autoincrement,
+    name          text    not null
+        unique,
+    discipline_id integer not null,
+    -- round_robin, swiss, single_elimination or double_elimination
+    format        text    not null,
+    -- the number of the rounds of a swiss tournament, 0 for the other formats
+    rounds        integer not null default 0,
+    created_at    timestamp not null,
+    finished_at   timestamp
+);
+
+create table if not exists tournament_players
+(
+    tournament_id integer not null,
+    player_id     text    not null,
+    -- 1 is the strongest player at the start
+    seed          integer not null,
+    constraint tournament_players_pk
+        primary key (tournament_id, player_id)
+);
+
+-- the pairings of the round robin and swiss tournaments and the played games of the brackets,
+-- player_b is empty for a bye and match_id is set when the result is recorded
+create table if not exists tournament_games
+(
+    tournament_id integer not null,
+    bracket       text    not null,
+    round         integer not null,
+    position      integer not null,
+    player_a      text    not null,
+    player_b      text    not null default '',
+    match_id      integer,
+    constraint tournament_games_pk
+        primary key (tournament_id, bracket, round, position)
+);
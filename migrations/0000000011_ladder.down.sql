@@ -0,0 +1,2 @@
+drop table if exists ladder_challenges;
+drop table if exists ladder_players;
@@ -0,0 +1,27 @@
+-- the players join the bottom of the ladder of the discipline,
+-- their positions follow from the order they joined in and the results of the challenges
+create table if not exists ladder_players
+(
+    discipline_id integer   not null,
+    player_id     text      not null,
+    joined_at     timestamp not null,
+    constraint ladder_players_pk
+        primary key (discipline_id, player_id)
+);
+
+-- the challenged player has to accept or play the challenge before the deadline,
+-- match_id is set when the result is recorded
+create table if not exists ladder_challenges
+(
+    id            integer   not null
+        constraint ladder_challenges_pk
+            primary key autoincrement,
+    discipline_id integer   not null,
+    challenger_id text      not null,
+    challenged_id text      not null,
+    created_at    timestamp not null,
+    deadline      timestamp not null,
+    accepted_at   timestamp,
+    cancelled_at  timestamp,
+    match_id      integer
+);